@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/cache"
+	"github.com/hserkanyilmaz/costdiff/internal/history"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+var backfillGranularity string
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Store closed months of costs before Cost Explorer drops them",
+	Long: `Store the grouped costs of closed months in the local history, so they can
+still be compared once Cost Explorer no longer returns them (after about 13
+months).
+
+Each run only fetches the months that are not stored yet, from the oldest month
+Cost Explorer still returns (or --from) to the last settled month; --refresh
+fetches stored months again. Costs are
+stored per metric, grouping and filter: run backfill with the flags you compare
+with. Every command then reads months beyond the retention from the history.
+History is stored in costdiff/history in the user config directory; set
+COSTDIFF_HISTORY_DIR to move it.
+
+Examples:
+  costdiff backfill                        # Store every month still available
+  costdiff backfill -g service,region      # Store costs by service and region
+  costdiff backfill --granularity monthly  # Skip daily costs
+  costdiff backfill --from 2024-06         # Only store months since June 2024
+  costdiff backfill status                 # Show stored months`,
+	Args:    cobra.NoArgs,
+	PreRunE: setupFiscalCalendar,
+	RunE:    runBackfill,
+}
+
+var backfillStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the months stored in the local history",
+	Args:  cobra.NoArgs,
+	RunE:  runBackfillStatus,
+}
+
+func init() {
+	backfillCmd.Flags().StringVar(&backfillGranularity, "granularity", "both", "Granularity to store (monthly|daily|both)")
+	backfillCmd.AddCommand(backfillStatusCmd)
+	rootCmd.AddCommand(backfillCmd)
+}
+
+func runBackfill(cmd *cobra.Command, args []string) error {
+	monthly, daily, err := parseBackfillGranularity(backfillGranularity)
+	if err != nil {
+		return err
+	}
+
+	kind, _, err := parseSource(dataSource)
+	if err != nil {
+		return err
+	}
+	if kind == sourceCUR {
+		return fmt.Errorf("backfill only applies to the Cost Explorer source; CUR exports keep their own history")
+	}
+
+	start, end, err := backfillRange(fromPeriod, time.Now())
+	if err != nil {
+		return err
+	}
+	debugf("Backfilling %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	groupTypes, _, err := parseGrouping(groupBy, tagKey)
+	if err != nil {
+		return err
+	}
+	metric, err := getAWSMetric()
+	if err != nil {
+		return err
+	}
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+
+	store, err := openHistoryStore()
+	if err != nil {
+		return err
+	}
+	series := history.NewSeries(currentProfile(), metric, groupTypes, costFilter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	// Keys are stored as the source reports them; aliases apply when reading
+	client, err := newQueryFetcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	var storedMonthly, storedDaily int
+	if monthly {
+		missing, err := missingMonths(store, series, history.GranularityMonthly, start, end)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			first, last := missing[0], missing[len(missing)-1].AddDate(0, 1, 0)
+			monthlyCosts, err := withSpinner("Fetching monthly cost data...", func() ([]aws.MonthlyCosts, error) {
+				return client.GetMonthlyCosts(ctx, first, last, groupTypes, metric, costFilter)
+			})
+			if err != nil {
+				return handleFetchError(err)
+			}
+
+			byMonth := make(map[string]map[string]float64, len(monthlyCosts))
+			for _, mc := range monthlyCosts {
+				byMonth[mc.Month.Format("2006-01")] = mc.Costs
+			}
+			for _, month := range missing {
+				// A month without costs is stored empty so it is not fetched again
+				costs := byMonth[month.Format("2006-01")]
+				if costs == nil {
+					costs = make(map[string]float64)
+				}
+				if err := store.PutMonthly(series, month, costs); err != nil {
+					return err
+				}
+				storedMonthly++
+			}
+		}
+	}
+
+	if daily {
+		missing, err := missingMonths(store, series, history.GranularityDaily, start, end)
+		if err != nil {
+			return err
+		}
+		for _, month := range missing {
+			dailyCosts, err := withSpinner(fmt.Sprintf("Fetching daily cost data for %s...", month.Format("Jan 2006")), func() ([]aws.DailyCost, error) {
+				return client.GetDailyCosts(ctx, month, month.AddDate(0, 1, 0), groupTypes, metric, costFilter)
+			})
+			if err != nil {
+				return handleFetchError(err)
+			}
+			if err := store.PutDaily(series, month, dailyCosts); err != nil {
+				return err
+			}
+			storedDaily++
+		}
+	}
+
+	if !quiet {
+		span := fmt.Sprintf("%s to %s", start.Format("Jan 2006"), end.AddDate(0, -1, 0).Format("Jan 2006"))
+		if storedMonthly == 0 && storedDaily == 0 {
+			fmt.Println(output.Success(fmt.Sprintf("History already covers %s", span)))
+		} else {
+			fmt.Println(output.Success(fmt.Sprintf("Stored %d monthly and %d daily records for %s in %s",
+				storedMonthly, storedDaily, span, store.Dir())))
+		}
+	}
+	return nil
+}
+
+func runBackfillStatus(cmd *cobra.Command, args []string) error {
+	store, err := openHistoryStore()
+	if err != nil {
+		return err
+	}
+
+	coverages, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(coverages) == 0 {
+		fmt.Println(output.Muted(fmt.Sprintf("No history in %s", store.Dir())))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tMETRIC\tGROUP\tFILTER\tMONTHLY\tDAILY")
+	for _, coverage := range coverages {
+		series := coverage.Series
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			orDash(series.Profile),
+			series.Metric,
+			orDash(series.GroupBy),
+			orDash(series.Filter),
+			monthRanges(coverage.Monthly),
+			monthRanges(coverage.Daily))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println(output.Muted(fmt.Sprintf("Cost Explorer returns costs since %s; older months are read from %s",
+		history.Horizon(time.Now()).Format("Jan 2006"), store.Dir())))
+	return nil
+}
+
+func openHistoryStore() (*history.Store, error) {
+	dir, err := history.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return history.NewStore(dir), nil
+}
+
+// parseBackfillGranularity returns which granularities --granularity selects
+func parseBackfillGranularity(s string) (monthly, daily bool, err error) {
+	switch s {
+	case history.GranularityMonthly:
+		return true, false, nil
+	case history.GranularityDaily:
+		return false, true, nil
+	case "both":
+		return true, true, nil
+	default:
+		return false, false, fmt.Errorf("invalid granularity: %s (must be monthly|daily|both)", s)
+	}
+}
+
+// backfillRange returns the months to store: from the oldest month Cost
+// Explorer still returns, or the month --from starts in, up to the last month
+// whose costs have settled (end exclusive)
+func backfillRange(from string, now time.Time) (time.Time, time.Time, error) {
+	horizon := history.Horizon(now)
+	start := horizon
+	if from != "" {
+		period, err := parseTopPeriod(from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid date: %w", err)
+		}
+		start = time.Date(period.Start.Year(), period.Start.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if start.Before(horizon) {
+			return time.Time{}, time.Time{}, fmt.Errorf("Cost Explorer no longer returns costs before %s", horizon.Format("Jan 2006"))
+		}
+	}
+
+	settled := now.UTC().AddDate(0, 0, -cache.SettleDays)
+	end := time.Date(settled.Year(), settled.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("no closed months to backfill since %s", start.Format("Jan 2006"))
+	}
+	return start, end, nil
+}
+
+// missingMonths returns the months in [start, end) not stored at a granularity,
+// or all of them with --refresh
+func missingMonths(store *history.Store, series history.Series, granularity string, start, end time.Time) ([]time.Time, error) {
+	stored := make(map[string]bool)
+	if !refreshCache {
+		coverages, err := store.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, coverage := range coverages {
+			if coverage.Series != series {
+				continue
+			}
+			months := coverage.Monthly
+			if granularity == history.GranularityDaily {
+				months = coverage.Daily
+			}
+			for _, month := range months {
+				stored[month.Format("2006-01")] = true
+			}
+		}
+	}
+
+	var missing []time.Time
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		if !stored[month.Format("2006-01")] {
+			missing = append(missing, month)
+		}
+	}
+	return missing, nil
+}
+
+// monthRanges formats months as runs of consecutive months, e.g.
+// "2023-01..2023-12, 2024-03"
+func monthRanges(months []time.Time) string {
+	if len(months) == 0 {
+		return "-"
+	}
+
+	var s string
+	for i := 0; i < len(months); {
+		j := i
+		for j+1 < len(months) && months[j+1].Equal(months[j].AddDate(0, 1, 0)) {
+			j++
+		}
+		if s != "" {
+			s += ", "
+		}
+		s += months[i].Format("2006-01")
+		if j > i {
+			s += ".." + months[j].Format("2006-01")
+		}
+		i = j + 1
+	}
+	return s
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
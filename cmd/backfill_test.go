@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackfillRange(t *testing.T) {
+	now := time.Date(2024, 12, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		from      string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		// November is still settling on Dec 2, so the last stored month is October
+		{"default", "", time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), false},
+		{"from month", "2024-06", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), false},
+		{"from day", "2024-06-15..2024-07-01", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), false},
+		{"beyond retention", "2023-01", time.Time{}, time.Time{}, true},
+		{"nothing settled", "2024-11", time.Time{}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := backfillRange(tt.from, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("backfillRange(%q) error = nil, want error", tt.from)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("backfillRange(%q) error = %v", tt.from, err)
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("backfillRange(%q) = %v, %v, want %v, %v", tt.from, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseBackfillGranularity(t *testing.T) {
+	tests := []struct {
+		in          string
+		wantMonthly bool
+		wantDaily   bool
+		wantErr     bool
+	}{
+		{"both", true, true, false},
+		{"monthly", true, false, false},
+		{"daily", false, true, false},
+		{"weekly", false, false, true},
+	}
+
+	for _, tt := range tests {
+		monthly, daily, err := parseBackfillGranularity(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseBackfillGranularity(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if monthly != tt.wantMonthly || daily != tt.wantDaily {
+			t.Errorf("parseBackfillGranularity(%q) = %v, %v, want %v, %v", tt.in, monthly, daily, tt.wantMonthly, tt.wantDaily)
+		}
+	}
+}
+
+func TestMonthRanges(t *testing.T) {
+	month := func(y int, m time.Month) time.Time { return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		months []time.Time
+		want   string
+	}{
+		{"none", nil, "-"},
+		{"single", []time.Time{month(2024, 3)}, "2024-03"},
+		{"run across year", []time.Time{month(2023, 11), month(2023, 12), month(2024, 1)}, "2023-11..2024-01"},
+		{"gap", []time.Time{month(2024, 1), month(2024, 2), month(2024, 5)}, "2024-01..2024-02, 2024-05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := monthRanges(tt.months); got != tt.want {
+				t.Errorf("monthRanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
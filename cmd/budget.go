@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/budget"
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Compare spend with monthly budgets",
+	Long: `Compare spend for the current month (or specified period) with the monthly
+budgets in a YAML or JSON budgets file.
+
+Every budgeted item is listed with its budget, actual spend so far, spend
+projected to the end of the period at the current daily run rate, and the
+variance between projection and budget. Items already over budget are
+highlighted; items projected to go over are marked at risk.
+
+Budgets are read from --budget, $COSTDIFF_BUDGETS, or costdiff/budgets.yaml in
+the user config directory. Periods other than a whole month get the monthly
+budgets prorated by day.
+
+Examples:
+  costdiff budget                            # Budgets by service this month
+  costdiff budget -g tag --tag team          # Budgets by team
+  costdiff budget --budget ./budgets.yaml --from 2024-10
+  costdiff budget -o csv`,
+	PreRunE: setupFiscalCalendar,
+	RunE:    runBudget,
+}
+
+func init() {
+	budgetCmd.Flags().StringVar(&budgetPath, "budget", "", "Budgets file, YAML or JSON (default: costdiff/budgets.yaml in the user config directory)")
+	rootCmd.AddCommand(budgetCmd)
+}
+
+func runBudget(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	// Parse time period
+	period, err := parseTopPeriod(fromPeriod)
+	if err != nil {
+		return fmt.Errorf("invalid date: %w", err)
+	}
+
+	debugf("Period: %s to %s", period.Start, period.End)
+
+	// Validate grouping
+	groupTypes, dimensions, err := parseGrouping(groupBy, tagKey)
+	if err != nil {
+		return err
+	}
+
+	// Load budgets before spending any queries
+	budgets, err := loadBudgets(budgetPath)
+	if err != nil {
+		return err
+	}
+	periodBudgets, err := budgetsFor(budgets, dimensions, period)
+	if err != nil {
+		return err
+	}
+
+	// Get metric
+	metric, err := getAWSMetric()
+	if err != nil {
+		return err
+	}
+	debugf("Using metric: %s", metric)
+
+	// Build cost filter
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+
+	// Initialize cost data source
+	client, err := newCostFetcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Fetch cost data with spinner
+	costs, err := withSpinner("Fetching cost data...", func() (map[string]float64, error) {
+		return client.GetCosts(ctx, period.Start, period.End, groupTypes, metric, costFilter)
+	})
+	if err != nil {
+		return handleFetchError(err)
+	}
+
+	// Compare with budgets, projecting a period still in progress
+	now := time.Now()
+	result := diff.CompareBudgets(costs, periodBudgets, period, diff.ProjectionFactor(period, now))
+	result.Dimension = dimensions[0]
+	result.ElapsedDays = diff.ElapsedDays(period, now)
+	result.Fiscal = fiscalCalendar
+
+	// Output
+	return outputBudgetResult(result, outputFmt)
+}
+
+// loadBudgets loads the budgets file at path, or the default one when path is empty
+func loadBudgets(path string) (*budget.Budgets, error) {
+	if path != "" {
+		debugf("Using budgets: %s", path)
+		return budget.Load(path)
+	}
+
+	path, err := budget.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := budget.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no budgets file found at %s (use --budget FILE)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	debugf("Using budgets: %s", path)
+	return budgets, nil
+}
+
+// budgetsFor returns the budgets of a single-dimension grouping for a period
+func budgetsFor(budgets *budget.Budgets, dimensions []string, period diff.Period) (diff.Budgets, error) {
+	if len(dimensions) > 1 {
+		return diff.Budgets{}, fmt.Errorf("budgets cannot be combined with two-level grouping")
+	}
+	return budgets.For(dimensions[0], period)
+}
+
+func outputBudgetResult(result *diff.BudgetResult, format string) error {
+	switch format {
+	case "table":
+		return output.RenderBudgetTable(result)
+	case "json":
+		return output.RenderBudgetJSON(result)
+	case "csv":
+		return output.RenderBudgetCSV(result)
+	default:
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+	}
+}
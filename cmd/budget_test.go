@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+func TestLoadBudgets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "budgets.yaml")
+	if err := os.WriteFile(path, []byte("budgets:\n  service:\n    Amazon EC2: 100\n"), 0o644); err != nil {
+		t.Fatalf("failed to write budgets: %v", err)
+	}
+
+	// An explicit path wins over the default
+	t.Setenv("COSTDIFF_BUDGETS", filepath.Join(dir, "missing.yaml"))
+	if budgets, err := loadBudgets(path); err != nil || budgets.Budgets["service"]["Amazon EC2"] != 100 {
+		t.Errorf("loadBudgets(path) = %v, %v", budgets, err)
+	}
+
+	// A missing default file is an error that points at --budget
+	if _, err := loadBudgets(""); err == nil || !strings.Contains(err.Error(), "--budget") {
+		t.Errorf("loadBudgets(\"\") error = %v, want a hint about --budget", err)
+	}
+
+	t.Setenv("COSTDIFF_BUDGETS", path)
+	if _, err := loadBudgets(""); err != nil {
+		t.Errorf("loadBudgets(\"\") with COSTDIFF_BUDGETS error = %v", err)
+	}
+}
+
+func TestBudgetsFor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budgets.yaml")
+	if err := os.WriteFile(path, []byte("budgets:\n  service:\n    Amazon EC2: 310\n"), 0o644); err != nil {
+		t.Fatalf("failed to write budgets: %v", err)
+	}
+	budgets, err := loadBudgets(path)
+	if err != nil {
+		t.Fatalf("loadBudgets() error = %v", err)
+	}
+
+	oct := diff.Period{
+		Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC),
+	}
+	got, err := budgetsFor(budgets, []string{"service"}, oct)
+	if err != nil || got.Items["Amazon EC2"] != 100 {
+		t.Errorf("budgetsFor(service) = %v, %v, want EC2 prorated to 100", got, err)
+	}
+
+	if _, err := budgetsFor(budgets, []string{"service", "region"}, oct); err == nil {
+		t.Error("budgetsFor() should reject two-level grouping")
+	}
+	if _, err := budgetsFor(budgets, []string{"region"}, oct); err == nil {
+		t.Error("budgetsFor() should fail for a dimension without budgets")
+	}
+}
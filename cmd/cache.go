@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/cache"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the local query cache",
+	Long: `Inspect or clear the local Cost Explorer query cache.
+
+Results for closed months are cached permanently; results that include the
+current month are reused for a short time. Set COSTDIFF_CACHE_DIR to move the cache.
+
+Examples:
+  costdiff cache stats   # Show cache location and size
+  costdiff cache clear   # Delete all cached results`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache location and size",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheStats,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached results",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClear,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	store, err := openCacheStore()
+	if err != nil {
+		return err
+	}
+
+	stats, err := store.Stats()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cache directory: %s\n", stats.Dir)
+	fmt.Printf("  Entries:   %d\n", stats.Entries)
+	fmt.Printf("  Finalized: %d\n", stats.Finalized)
+	fmt.Printf("  Open:      %d\n", stats.Open)
+	fmt.Printf("  Expired:   %d\n", stats.Expired)
+	fmt.Printf("  Size:      %s\n", formatBytes(stats.Bytes))
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	store, err := openCacheStore()
+	if err != nil {
+		return err
+	}
+
+	removed, err := store.Clear()
+	if err != nil {
+		return err
+	}
+
+	if !quiet {
+		fmt.Println(output.Success(fmt.Sprintf("Removed %d cached results from %s", removed, store.Dir())))
+	}
+	return nil
+}
+
+func openCacheStore() (*cache.Store, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return cache.NewStore(dir), nil
+}
+
+// formatBytes formats a byte count using binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
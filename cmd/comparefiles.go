@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+	"github.com/hserkanyilmaz/costdiff/internal/report"
+)
+
+var compareFilesCmd = &cobra.Command{
+	Use:   "compare-files <old.json> <new.json>",
+	Short: "Compare two saved JSON reports without querying AWS",
+	Long: `Compare two reports saved with -o json, without any AWS access.
+
+Two costdiff top reports are compared like a diff of their periods. Two
+costdiff reports are compared change by change: how each item's change in the
+new report differs from its change in the old one. Use - to read a report
+from stdin.
+
+Examples:
+  costdiff compare-files top-2024-09.json top-2024-10.json  # Sep vs Oct
+  costdiff compare-files diff-2024-10.json diff-2024-11.json # Oct's changes vs Nov's
+  aws s3 cp s3://reports/top-2024-09.json - | costdiff compare-files - top-2024-10.json`,
+	Args:    cobra.ExactArgs(2),
+	PreRunE: setupFiscalCalendar,
+	RunE:    runCompareFiles,
+}
+
+func init() {
+	rootCmd.AddCommand(compareFilesCmd)
+}
+
+func runCompareFiles(cmd *cobra.Command, args []string) error {
+	if args[0] == "-" && args[1] == "-" {
+		return fmt.Errorf("only one report can be read from stdin")
+	}
+
+	older, err := report.Load(args[0])
+	if err != nil {
+		return err
+	}
+	newer, err := report.Load(args[1])
+	if err != nil {
+		return err
+	}
+	debugf("Comparing %s report %s with %s report %s", older.Kind, older.Path, newer.Kind, newer.Path)
+
+	if older.Kind != newer.Kind {
+		return fmt.Errorf("cannot compare a %s report with a %s report; pass two top or two diff reports", older.Kind, newer.Kind)
+	}
+
+	switch older.Kind {
+	case report.KindTop:
+		if err := checkSameGrouping(older.Top.GroupBy, newer.Top.GroupBy); err != nil {
+			return err
+		}
+		return compareTopReports(older.Top, newer.Top)
+	default:
+		if err := checkSameGrouping(older.Diff.GroupBy, newer.Diff.GroupBy); err != nil {
+			return err
+		}
+		if older.Diff.PerDay != newer.Diff.PerDay {
+			return fmt.Errorf("cannot compare a per-day report with a total one (--normalize)")
+		}
+		return compareDiffReports(older.Diff, newer.Diff)
+	}
+}
+
+// checkSameGrouping fails when two reports are grouped by different dimensions
+func checkSameGrouping(older, newer []string) error {
+	if strings.Join(older, ",") != strings.Join(newer, ",") {
+		return fmt.Errorf("cannot compare reports grouped by %s and %s", groupingName(older), groupingName(newer))
+	}
+	return nil
+}
+
+// groupingName returns the -g value a report was grouped by
+func groupingName(groupBy []string) string {
+	if len(groupBy) == 0 {
+		return "service"
+	}
+	return strings.Join(groupBy, ",")
+}
+
+// compareTopReports diffs two top reports as if their costs had been fetched
+// by costdiff, applying the same sorting, filters and limit
+func compareTopReports(older, newer *diff.TopResult) error {
+	result := diff.CompareTop(older, newer)
+	result.SetGroupBy(newer.GroupBy)
+	result.Fiscal = fiscalCalendar
+	all := result.Items
+
+	applySorting(result.Items, sortBy)
+	if threshold > 0 {
+		result = filterByThreshold(result, threshold)
+	}
+	if minCost > 0 {
+		result.Items = diff.FilterByMinCost(result.Items, minCost)
+	}
+	if len(result.Items) > topN {
+		result.Items = result.Items[:topN]
+	}
+	if other, ok := diff.OtherItem(all, result.Items); ok {
+		result.Items = append(result.Items, other)
+	}
+
+	return outputResult(result, outputFmt)
+}
+
+// compareDiffReports compares the changes of two diff reports. --threshold
+// applies to the delta between them; items are sorted by delta unless
+// --sort name is given.
+func compareDiffReports(older, newer *diff.Result) error {
+	// Saved reports are labeled with the current fiscal calendar
+	older.Fiscal, newer.Fiscal = fiscalCalendar, fiscalCalendar
+	result := diff.CompareChanges(older, newer)
+	all := result.Items
+
+	if sortBy == "name" {
+		diff.SortChangesByName(result.Items)
+	}
+	if threshold > 0 {
+		filtered := make([]diff.ChangeItem, 0)
+		for _, item := range result.Items {
+			if math.Abs(item.Delta) >= threshold {
+				filtered = append(filtered, item)
+			}
+		}
+		result.Items = filtered
+	}
+	if len(result.Items) > topN {
+		result.Items = result.Items[:topN]
+	}
+	if other, ok := diff.OtherChangeItem(all, result.Items); ok {
+		result.Items = append(result.Items, other)
+	}
+
+	return outputChangeResult(result, outputFmt)
+}
+
+func outputChangeResult(result *diff.ChangeResult, format string) error {
+	switch format {
+	case "table":
+		return output.RenderChangeTable(result)
+	case "json":
+		return output.RenderChangeJSON(result)
+	case "csv":
+		return output.RenderChangeCSV(result)
+	default:
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+	}
+}
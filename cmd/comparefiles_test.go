@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCheckSameGrouping(t *testing.T) {
+	if err := checkSameGrouping([]string{"service", "region"}, []string{"service", "region"}); err != nil {
+		t.Errorf("checkSameGrouping() error = %v", err)
+	}
+
+	err := checkSameGrouping(nil, []string{"region"})
+	if err == nil || !strings.Contains(err.Error(), "grouped by service and region") {
+		t.Errorf("checkSameGrouping() error = %v, want a grouping mismatch", err)
+	}
+}
+
+func TestRunCompareFiles_MixedKinds(t *testing.T) {
+	dir := t.TempDir()
+	top := filepath.Join(dir, "top.json")
+	diffReport := filepath.Join(dir, "diff.json")
+	files := map[string]string{
+		top:        `{"period": {"start": "2024-09-01", "end": "2024-10-01"}, "total": 10, "items": []}`,
+		diffReport: `{"from_period": {"start": "2024-09-01", "end": "2024-10-01"}, "to_period": {"start": "2024-10-01", "end": "2024-11-01"}, "items": []}`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := runCompareFiles(&cobra.Command{}, []string{top, diffReport})
+	if err == nil || !strings.Contains(err.Error(), "cannot compare a top report with a diff report") {
+		t.Errorf("runCompareFiles() error = %v, want a kind mismatch", err)
+	}
+
+	err = runCompareFiles(&cobra.Command{}, []string{"-", "-"})
+	if err == nil || !strings.Contains(err.Error(), "stdin") {
+		t.Errorf("runCompareFiles() error = %v, want a stdin error", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,30 +16,91 @@ import (
 	"github.com/hserkanyilmaz/costdiff/internal/aws"
 	"github.com/hserkanyilmaz/costdiff/internal/diff"
 	"github.com/hserkanyilmaz/costdiff/internal/output"
+	"github.com/hserkanyilmaz/costdiff/internal/snapshot"
 )
 
 // Default timeout for AWS API calls
 const defaultAPITimeout = 2 * time.Minute
 
+// Normalization modes accepted by --normalize
+const (
+	normalizeNone  = "none"
+	normalizeDaily = "daily"
+)
+
+// baselinePrev is the default --baseline: the single --from period
+const baselinePrev = "prev"
+
+// averagePattern matches --baseline avg-N, with an optional m as in avg-3m
+var averagePattern = regexp.MustCompile(`^avg-(\d+)m?$`)
+
 func runDiff(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
 	defer cancel()
 
+	// A snapshot replaces the --from period and sets what to fetch, so load it first
+	var snap *snapshot.Snapshot
+	if againstSnapshot != "" {
+		var err error
+		if snap, err = loadAgainstSnapshot(cmd, againstSnapshot); err != nil {
+			return err
+		}
+	}
+
 	// Parse time periods
-	from, to, err := parsePeriods(fromPeriod, toPeriod)
+	average, err := parseBaseline(baselineMode)
+	if err != nil {
+		return err
+	}
+	var baseline []diff.Period
+	var to diff.Period
+	if snap != nil {
+		baseline, to, err = parseSnapshotPeriods(snap, toPeriod)
+	} else {
+		baseline, to, err = parseBaselinePeriods(fromPeriod, toPeriod, compareYoY, average, alignMTD)
+	}
 	if err != nil {
 		return fmt.Errorf("invalid date range: %w", err)
 	}
+	from := diff.Span(baseline)
 
-	debugf("From period: %s to %s", from.Start, from.End)
+	for _, period := range baseline {
+		debugf("From period: %s to %s", period.Start, period.End)
+	}
 	debugf("To period: %s to %s", to.Start, to.End)
 
+	if normalizeMode != normalizeNone && normalizeMode != normalizeDaily {
+		return fmt.Errorf("invalid normalize mode: %s (must be none|daily)", normalizeMode)
+	}
+	if paretoPct < 0 || paretoPct > 100 {
+		return fmt.Errorf("invalid --pareto: %g (must be between 0 and 100)", paretoPct)
+	}
+	conditions, err := diff.ParseConditions(failOn)
+	if err != nil {
+		return fmt.Errorf("invalid --fail-on: %w", err)
+	}
+
 	// Validate grouping
-	groupType, err := parseGroupBy(groupBy, tagKey)
+	groupTypes, dimensions, err := parseGrouping(groupBy, tagKey)
 	if err != nil {
 		return err
 	}
 
+	// Load budgets before spending any queries
+	var budgets diff.Budgets
+	if budgetPath != "" {
+		if normalizeMode == normalizeDaily {
+			return fmt.Errorf("--budget cannot be combined with --normalize daily")
+		}
+		file, err := loadBudgets(budgetPath)
+		if err != nil {
+			return err
+		}
+		if budgets, err = budgetsFor(file, dimensions, to); err != nil {
+			return err
+		}
+	}
+
 	// Get metric
 	metric, err := getAWSMetric()
 	if err != nil {
@@ -45,34 +108,104 @@ func runDiff(cmd *cobra.Command, args []string) error {
 	}
 	debugf("Using metric: %s", metric)
 
-	// Initialize AWS client
-	client, err := aws.NewCostExplorerClient(ctx, awsProfile, awsRegion)
+	// Build cost filter
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+
+	// The charge breakdown uses the second Cost Explorer group slot for RECORD_TYPE
+	fetchGroups := groupTypes
+	if showCharges {
+		if len(groupTypes) >= aws.MaxGroupBy {
+			return fmt.Errorf("--charges cannot be combined with two-level grouping")
+		}
+		fetchGroups = append(groupTypes, aws.GroupByRecordType)
+	}
+
+	// Initialize cost data source
+	client, err := newCostFetcher(ctx)
 	if err != nil {
-		return handleAWSError(err)
+		return err
 	}
-	client.SetLogger(cliLogger{})
 
 	// Fetch cost data for both periods with spinner
 	spin := newProgressSpinner("Fetching cost data...")
 	defer spin.Stop()
 
-	fromCosts, err := client.GetCosts(ctx, from.Start, from.End, groupType, metric, serviceFilter)
-	if err != nil {
-		return handleAWSError(err)
+	baselineCosts := make([]map[string]float64, len(baseline))
+	for i, period := range baseline {
+		if snap != nil {
+			baselineCosts[i] = snap.Costs()
+			continue
+		}
+		baselineCosts[i], err = client.GetCosts(ctx, period.Start, period.End, fetchGroups, metric, costFilter)
+		if err != nil {
+			return handleFetchError(err)
+		}
 	}
 
-	toCosts, err := client.GetCosts(ctx, to.Start, to.End, groupType, metric, serviceFilter)
+	toCosts, err := client.GetCosts(ctx, to.Start, to.End, fetchGroups, metric, costFilter)
 	if err != nil {
-		return handleAWSError(err)
+		return handleFetchError(err)
 	}
 
 	spin.Stop()
 
-	// Calculate diff
+	// Convert totals to per-day run rates so periods of different length compare fairly
+	if normalizeMode == normalizeDaily {
+		for i, period := range baseline {
+			baselineCosts[i] = diff.PerDay(baselineCosts[i], period)
+		}
+		toCosts = diff.PerDay(toCosts, to)
+	}
+
+	// A seasonal baseline compares with the average of its periods
+	fromCosts := baselineCosts[0]
+	if len(baseline) > 1 {
+		fromCosts = diff.Average(baselineCosts)
+	}
+
+	// Calculate diff, splitting off the charge type first when requested
+	var fromCharges, toCharges map[string]map[string]float64
+	if showCharges {
+		fromCosts, fromCharges = diff.SplitCharges(fromCosts)
+		toCosts, toCharges = diff.SplitCharges(toCosts)
+	}
+
 	result := diff.Compare(fromCosts, toCosts, from, to)
+	result.SetGroupBy(dimensions)
+	result.Fiscal = fiscalCalendar
+	if len(baseline) > 1 {
+		result.Baseline = baseline
+	}
+	if snap != nil {
+		result.Snapshot = snap.Name
+	}
+	result.PerDay = normalizeMode == normalizeDaily
+	if showCharges {
+		result.SetCharges(fromCharges, toCharges)
+	}
+	if budgetPath != "" {
+		result.SetBudgets(budgets, diff.ProjectionFactor(to, time.Now()))
+	}
+
+	// Check the gate against every item, before any are filtered out
+	if len(conditions) > 0 {
+		result.Evaluate(conditions)
+	}
+	all := result.Items
+
+	// Keep only the items that explain most of the change
+	if paretoPct > 0 {
+		result.Items = diff.Pareto(result.Items, paretoPct)
+		result.Pareto = paretoPct
+	}
 
-	// Apply sorting
-	applySorting(result.Items, sortBy)
+	// Apply sorting; Pareto results stay in contribution order unless --sort is given
+	if paretoPct == 0 || cmd.Flags().Changed("sort") {
+		applySorting(result.Items, sortBy)
+	}
 
 	// Apply filters
 	if threshold > 0 {
@@ -87,23 +220,27 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		result.Items = result.Items[:topN]
 	}
 
-	// Output
-	return outputResult(result, outputFmt)
+	// Roll up everything left out so the rows still add up to the totals
+	if other, ok := diff.OtherItem(all, result.Items); ok {
+		result.Items = append(result.Items, other)
+	}
+
+	// Output, then fail if the gate did
+	if err := outputResult(result, outputFmt); err != nil {
+		return err
+	}
+	return gateError(cmd, result.Gate)
 }
 
-func parsePeriods(from, to string) (diff.Period, diff.Period, error) {
+// parsePeriods parses --from and --to into periods. With mtd set, both periods are
+// cut down to the days already elapsed in the to period for a like-for-like comparison.
+func parsePeriods(from, to string, mtd bool) (diff.Period, diff.Period, error) {
 	now := time.Now()
 
-	var fromPeriod, toPeriod diff.Period
+	// Default: last month vs current month, or fiscal periods with a fiscal calendar
+	fromPeriod, toPeriod := currentPeriods(now)
 
-	if from == "" {
-		// Default: last month
-		lastMonth := now.AddDate(0, -1, 0)
-		fromPeriod = diff.Period{
-			Start: time.Date(lastMonth.Year(), lastMonth.Month(), 1, 0, 0, 0, 0, time.UTC),
-			End:   time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
-		}
-	} else {
+	if from != "" {
 		var err error
 		fromPeriod, err = parseDate(from)
 		if err != nil {
@@ -111,14 +248,7 @@ func parsePeriods(from, to string) (diff.Period, diff.Period, error) {
 		}
 	}
 
-	if to == "" {
-		// Default: current month
-		nextMonth := now.AddDate(0, 1, 0)
-		toPeriod = diff.Period{
-			Start: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
-			End:   time.Date(nextMonth.Year(), nextMonth.Month(), 1, 0, 0, 0, 0, time.UTC),
-		}
-	} else {
+	if to != "" {
 		var err error
 		toPeriod, err = parseDate(to)
 		if err != nil {
@@ -132,27 +262,157 @@ func parsePeriods(from, to string) (diff.Period, diff.Period, error) {
 			fromPeriod.Start.Format("2006-01-02"), toPeriod.Start.Format("2006-01-02"))
 	}
 
+	if mtd {
+		return alignMonthToDate(fromPeriod, toPeriod, now)
+	}
+
 	return fromPeriod, toPeriod, nil
 }
 
+// parseBaseline parses --baseline, returning the number of periods to average,
+// or 0 for the single --from period
+func parseBaseline(mode string) (int, error) {
+	if mode == baselinePrev {
+		return 0, nil
+	}
+	if m := averagePattern.FindStringSubmatch(mode); m != nil {
+		if count, err := strconv.Atoi(m[1]); err == nil && count >= 1 {
+			return count, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid baseline: %s (must be %s or avg-N, e.g. avg-3m)", mode, baselinePrev)
+}
+
+// parseBaselinePeriods returns the periods to compare the to period with,
+// oldest first. By default that is the --from period. With yoy it is the same
+// period a year earlier, and with average set the average of that many
+// equivalent periods: the ones just before the to period, or with yoy the same
+// period in each of the previous years. With mtd, every period is cut down to
+// the days already elapsed in the to period.
+func parseBaselinePeriods(from, to string, yoy bool, average int, mtd bool) ([]diff.Period, diff.Period, error) {
+	if !yoy && average == 0 {
+		fromPeriod, toPeriod, err := parsePeriods(from, to, mtd)
+		return []diff.Period{fromPeriod}, toPeriod, err
+	}
+
+	if from != "" {
+		return nil, diff.Period{}, fmt.Errorf("--from cannot be combined with --yoy or --baseline avg-N; they derive it from --to")
+	}
+
+	now := time.Now()
+	_, toPeriod := currentPeriods(now)
+	if to != "" {
+		var err error
+		toPeriod, err = parseDate(to)
+		if err != nil {
+			return nil, diff.Period{}, fmt.Errorf("invalid --to date: %w", err)
+		}
+	}
+
+	count := max(average, 1)
+	baseline := toPeriod.Preceding(count, fiscalCalendar)
+	if yoy {
+		for i := range baseline {
+			baseline[i] = toPeriod.YearsEarlier(count-i, fiscalCalendar)
+		}
+	}
+
+	if mtd {
+		aligned := toPeriod
+		for i := range baseline {
+			var err error
+			baseline[i], aligned, err = alignMonthToDate(baseline[i], toPeriod, now)
+			if err != nil {
+				return nil, diff.Period{}, err
+			}
+		}
+		toPeriod = aligned
+	}
+
+	return baseline, toPeriod, nil
+}
+
+// parseSnapshotPeriods returns the snapshot's period and the --to period,
+// which defaults to the current month
+func parseSnapshotPeriods(snap *snapshot.Snapshot, to string) ([]diff.Period, diff.Period, error) {
+	from, err := snap.Period()
+	if err != nil {
+		return nil, diff.Period{}, err
+	}
+
+	_, toPeriod := currentPeriods(time.Now())
+	if to != "" {
+		if toPeriod, err = parseDate(to); err != nil {
+			return nil, diff.Period{}, fmt.Errorf("invalid --to date: %w", err)
+		}
+	}
+	return []diff.Period{from}, toPeriod, nil
+}
+
+// alignMonthToDate truncates both periods to the number of complete days elapsed
+// in the to period, so a partial current month is compared with the same number
+// of days of the from period (e.g. Oct 1-14 vs Sep 1-14)
+func alignMonthToDate(from, to diff.Period, now time.Time) (diff.Period, diff.Period, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	elapsed := to.End
+	if today.Before(elapsed) {
+		elapsed = today
+	}
+	days := int(elapsed.Sub(to.Start).Hours() / 24)
+	if days < 1 {
+		return diff.Period{}, diff.Period{}, fmt.Errorf("--mtd needs at least one complete day in the --to period (starts %s)",
+			to.Start.Format("2006-01-02"))
+	}
+
+	return truncatePeriod(from, days), truncatePeriod(to, days), nil
+}
+
+// truncatePeriod shortens a period to at most the given number of days
+func truncatePeriod(p diff.Period, days int) diff.Period {
+	if end := p.Start.AddDate(0, 0, days); end.Before(p.End) {
+		p.End = end
+	}
+	return p
+}
+
+// parseDate parses a period expression such as 2024-10, 2024-Q3 or last-30d
 func parseDate(s string) (diff.Period, error) {
-	// Try YYYY-MM-DD format
-	if t, err := time.Parse("2006-01-02", s); err == nil {
-		return diff.Period{
-			Start: t,
-			End:   t.AddDate(0, 0, 1),
-		}, nil
+	return diff.ParsePeriod(s, time.Now(), fiscalCalendar)
+}
+
+// parseGrouping parses a comma-separated -g value such as "service,region" into
+// group types and their dimension labels. Cost Explorer accepts at most two.
+func parseGrouping(group, tag string) ([]aws.GroupType, []string, error) {
+	parts := strings.Split(group, ",")
+	if len(parts) > aws.MaxGroupBy {
+		return nil, nil, fmt.Errorf("invalid group: %s (at most %d comma-separated groups are supported)", group, aws.MaxGroupBy)
 	}
 
-	// Try YYYY-MM format (full month)
-	if t, err := time.Parse("2006-01", s); err == nil {
-		return diff.Period{
-			Start: t,
-			End:   t.AddDate(0, 1, 0),
-		}, nil
+	groupTypes := make([]aws.GroupType, 0, len(parts))
+	dimensions := make([]string, 0, len(parts))
+	seen := make(map[string]bool)
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if seen[part] {
+			return nil, nil, fmt.Errorf("invalid group: %s (%s is listed twice)", group, part)
+		}
+		seen[part] = true
+
+		groupType, err := parseGroupBy(part, tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		groupTypes = append(groupTypes, groupType)
+
+		if part == "tag" {
+			part = "tag:" + tag
+		}
+		dimensions = append(dimensions, part)
 	}
 
-	return diff.Period{}, fmt.Errorf("date must be YYYY-MM or YYYY-MM-DD format")
+	return groupTypes, dimensions, nil
 }
 
 func parseGroupBy(group, tag string) (aws.GroupType, error) {
@@ -179,9 +439,17 @@ func filterByThreshold(result *diff.Result, threshold float64) *diff.Result {
 	filtered := &diff.Result{
 		FromPeriod: result.FromPeriod,
 		ToPeriod:   result.ToPeriod,
+		Baseline:   result.Baseline,
+		Snapshot:   result.Snapshot,
+		GroupBy:    result.GroupBy,
+		PerDay:     result.PerDay,
+		Pareto:     result.Pareto,
 		FromTotal:  result.FromTotal,
 		ToTotal:    result.ToTotal,
+		Budget:     result.Budget,
+		Gate:       result.Gate,
 		Items:      make([]diff.Item, 0),
+		Fiscal:     result.Fiscal,
 	}
 
 	for _, item := range result.Items {
@@ -201,8 +469,12 @@ func outputResult(result *diff.Result, format string) error {
 		return output.RenderJSON(result)
 	case "csv":
 		return output.RenderCSV(result)
+	case "markdown":
+		return output.RenderMarkdown(result)
+	case "html":
+		return output.RenderHTML(result)
 	default:
-		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv|markdown|html)", format)
 	}
 }
 
@@ -241,6 +513,8 @@ func handleAWSError(err error) error {
 			return fmt.Errorf("AWS Cost Explorer is not enabled for this account.\n\nTo enable it:\n  1. Go to AWS Console > Billing > Cost Explorer\n  2. Click 'Enable Cost Explorer'\n  3. Wait up to 24 hours for data to be available")
 		case "InvalidParameterValue", "ValidationException":
 			return fmt.Errorf("invalid parameter: %s\n\nCheck your date range and grouping options", apiErr.ErrorMessage())
+		case "DataUnavailableException":
+			return fmt.Errorf("not enough cost history for this request. Cost Explorer needs more historical data before it can forecast")
 		case "ThrottlingException", "RequestLimitExceeded":
 			return fmt.Errorf("AWS API rate limit exceeded. Please wait a moment and try again")
 		}
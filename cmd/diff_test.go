@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hserkanyilmaz/costdiff/internal/aws"
 	"github.com/hserkanyilmaz/costdiff/internal/diff"
@@ -9,7 +11,7 @@ import (
 
 func TestParsePeriods_Defaults(t *testing.T) {
 	// Test with empty inputs (defaults)
-	from, to, err := parsePeriods("", "")
+	from, to, err := parsePeriods("", "", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -31,7 +33,7 @@ func TestParsePeriods_Defaults(t *testing.T) {
 }
 
 func TestParsePeriods_SpecificMonths(t *testing.T) {
-	from, to, err := parsePeriods("2024-10", "2024-12")
+	from, to, err := parsePeriods("2024-10", "2024-12", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -45,7 +47,7 @@ func TestParsePeriods_SpecificMonths(t *testing.T) {
 }
 
 func TestParsePeriods_SpecificDays(t *testing.T) {
-	from, to, err := parsePeriods("2024-10-15", "2024-12-20")
+	from, to, err := parsePeriods("2024-10-15", "2024-12-20", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -59,33 +61,182 @@ func TestParsePeriods_SpecificDays(t *testing.T) {
 }
 
 func TestParsePeriods_InvalidFrom(t *testing.T) {
-	_, _, err := parsePeriods("invalid", "2024-12")
+	_, _, err := parsePeriods("invalid", "2024-12", false)
 	if err == nil {
 		t.Error("expected error for invalid from date")
 	}
 }
 
 func TestParsePeriods_InvalidTo(t *testing.T) {
-	_, _, err := parsePeriods("2024-10", "invalid")
+	_, _, err := parsePeriods("2024-10", "invalid", false)
 	if err == nil {
 		t.Error("expected error for invalid to date")
 	}
 }
 
 func TestParsePeriods_FromAfterTo(t *testing.T) {
-	_, _, err := parsePeriods("2024-12", "2024-10")
+	_, _, err := parsePeriods("2024-12", "2024-10", false)
 	if err == nil {
 		t.Error("expected error when from date is after to date")
 	}
 }
 
 func TestParsePeriods_SameDates(t *testing.T) {
-	_, _, err := parsePeriods("2024-10", "2024-10")
+	_, _, err := parsePeriods("2024-10", "2024-10", false)
 	if err == nil {
 		t.Error("expected error when from and to dates are the same")
 	}
 }
 
+func TestParseBaseline(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    int
+		wantErr bool
+	}{
+		{mode: "prev", want: 0},
+		{mode: "avg-3m", want: 3},
+		{mode: "avg-6", want: 6},
+		{mode: "avg-0m", wantErr: true},
+		{mode: "avg-3w", wantErr: true},
+		{mode: "median-3m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := parseBaseline(tt.mode)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseBaseline() = %d, %v, want %d (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBaselinePeriods(t *testing.T) {
+	tests := []struct {
+		name     string
+		to       string
+		yoy      bool
+		average  int
+		wantFrom []string
+		wantTo   string
+	}{
+		{name: "yoy", to: "2024-10", yoy: true, wantFrom: []string{"Oct 2023"}, wantTo: "Oct 2024"},
+		{name: "average", to: "2024-10", average: 3, wantFrom: []string{"Jul 2024", "Aug 2024", "Sep 2024"}, wantTo: "Oct 2024"},
+		{name: "seasonal average", to: "2024-Q4", yoy: true, average: 2, wantFrom: []string{"Q4 2022", "Q4 2023"}, wantTo: "Q4 2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline, to, err := parseBaselinePeriods("", tt.to, tt.yoy, tt.average, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if to.Label(nil) != tt.wantTo {
+				t.Errorf("to = %s, want %s", to.Label(nil), tt.wantTo)
+			}
+			if len(baseline) != len(tt.wantFrom) {
+				t.Fatalf("baseline = %v, want %v", baseline, tt.wantFrom)
+			}
+			for i, p := range baseline {
+				if p.Label(nil) != tt.wantFrom[i] {
+					t.Errorf("baseline[%d] = %s, want %s", i, p.Label(nil), tt.wantFrom[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseBaselinePeriods_Default(t *testing.T) {
+	baseline, to, err := parseBaselinePeriods("2024-09", "2024-10", false, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(baseline) != 1 || baseline[0].Label(nil) != "Sep 2024" || to.Label(nil) != "Oct 2024" {
+		t.Errorf("got %v vs %s, want Sep 2024 vs Oct 2024", baseline, to.Label(nil))
+	}
+}
+
+func TestParseBaselinePeriods_FromConflict(t *testing.T) {
+	_, _, err := parseBaselinePeriods("2024-09", "2024-10", true, 0, false)
+	if err == nil || !strings.Contains(err.Error(), "--from cannot be combined") {
+		t.Errorf("error = %v, want a --from conflict", err)
+	}
+}
+
+func TestAlignMonthToDate(t *testing.T) {
+	month := func(y int, m time.Month) diff.Period {
+		start := time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+		return diff.Period{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+
+	tests := []struct {
+		name     string
+		from, to diff.Period
+		now      time.Time
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{
+			name:     "mid month",
+			from:     month(2024, 9),
+			to:       month(2024, 10),
+			now:      time.Date(2024, 10, 15, 9, 30, 0, 0, time.UTC),
+			wantFrom: "2024-09-15",
+			wantTo:   "2024-10-15",
+		},
+		{
+			name:     "shorter from month is not extended",
+			from:     month(2024, 2),
+			to:       month(2024, 3),
+			now:      time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+			wantFrom: "2024-03-01",
+			wantTo:   "2024-03-31",
+		},
+		{
+			name:     "completed to period",
+			from:     month(2024, 9),
+			to:       month(2024, 10),
+			now:      time.Date(2024, 12, 3, 0, 0, 0, 0, time.UTC),
+			wantFrom: "2024-10-01",
+			wantTo:   "2024-11-01",
+		},
+		{
+			name:    "first day of month",
+			from:    month(2024, 9),
+			to:      month(2024, 10),
+			now:     time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := alignMonthToDate(tt.from, tt.to, tt.now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := from.End.Format("2006-01-02"); got != tt.wantFrom {
+				t.Errorf("from.End = %s, want %s", got, tt.wantFrom)
+			}
+			if got := to.End.Format("2006-01-02"); got != tt.wantTo {
+				t.Errorf("to.End = %s, want %s", got, tt.wantTo)
+			}
+			if !from.Start.Equal(tt.from.Start) || !to.Start.Equal(tt.to.Start) {
+				t.Error("period starts should not change")
+			}
+		})
+	}
+}
+
 func TestParseDate(t *testing.T) {
 	tests := []struct {
 		input     string
@@ -113,8 +264,19 @@ func TestParseDate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			input:   "2024",
-			wantErr: true,
+			input:     "2024",
+			wantStart: "2024-01-01",
+			wantEnd:   "2025-01-01",
+		},
+		{
+			input:     "2024-Q3",
+			wantStart: "2024-07-01",
+			wantEnd:   "2024-10-01",
+		},
+		{
+			input:     "2024-01-05..2024-02-10",
+			wantStart: "2024-01-05",
+			wantEnd:   "2024-02-11",
 		},
 		{
 			input:   "12-2024",
@@ -252,6 +414,31 @@ func TestFilterByThreshold_PreservesPeriods(t *testing.T) {
 	}
 }
 
+func TestFilterByThreshold_PreservesPerDay(t *testing.T) {
+	result := &diff.Result{PerDay: true, Items: []diff.Item{{Name: "A", Diff: 50}}}
+
+	if !filterByThreshold(result, 10).PerDay {
+		t.Error("PerDay not preserved")
+	}
+}
+
+func TestFilterByThreshold_PreservesPareto(t *testing.T) {
+	result := &diff.Result{Pareto: 80, Items: []diff.Item{{Name: "A", Diff: 50}}}
+
+	if got := filterByThreshold(result, 10).Pareto; got != 80 {
+		t.Errorf("Pareto = %v, want 80", got)
+	}
+}
+
+func TestFilterByThreshold_PreservesBudget(t *testing.T) {
+	status := &diff.BudgetStatus{Budget: 100}
+	result := &diff.Result{Budget: status, Items: []diff.Item{{Name: "A", Diff: 50}}}
+
+	if got := filterByThreshold(result, 10).Budget; got != status {
+		t.Errorf("Budget = %v, want the overall budget", got)
+	}
+}
+
 func TestGetAWSMetric(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -316,3 +503,73 @@ func TestGroupTypeConstants(t *testing.T) {
 		t.Errorf("GroupByAccount = %+v, want DIMENSION/LINKED_ACCOUNT", aws.GroupByAccount)
 	}
 }
+
+func TestParseGrouping(t *testing.T) {
+	tests := []struct {
+		group    string
+		tag      string
+		wantKeys []string
+		wantDims []string
+		wantErr  bool
+	}{
+		{group: "service", wantKeys: []string{"SERVICE"}, wantDims: []string{"service"}},
+		{group: "service,region", wantKeys: []string{"SERVICE", "REGION"}, wantDims: []string{"service", "region"}},
+		{group: "account, service", wantKeys: []string{"LINKED_ACCOUNT", "SERVICE"}, wantDims: []string{"account", "service"}},
+		{group: "service,tag", tag: "team", wantKeys: []string{"SERVICE", "team"}, wantDims: []string{"service", "tag:team"}},
+		{group: "service,service", wantErr: true},
+		{group: "service,region,account", wantErr: true},
+		{group: "service,tag", wantErr: true},
+		{group: "service,bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			groupTypes, dims, err := parseGrouping(tt.group, tt.tag)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(groupTypes) != len(tt.wantKeys) {
+				t.Fatalf("got %d group types, want %d", len(groupTypes), len(tt.wantKeys))
+			}
+			for i, key := range tt.wantKeys {
+				if groupTypes[i].Key != key {
+					t.Errorf("groupTypes[%d].Key = %q, want %q", i, groupTypes[i].Key, key)
+				}
+				if dims[i] != tt.wantDims[i] {
+					t.Errorf("dims[%d] = %q, want %q", i, dims[i], tt.wantDims[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFilter(t *testing.T) {
+	defer func() { serviceFilter, filterExpr = "", "" }()
+
+	serviceFilter, filterExpr = "", ""
+	if expr, err := buildFilter(); err != nil || expr != nil {
+		t.Errorf("buildFilter() = %v, %v; want nil, nil", expr, err)
+	}
+
+	serviceFilter, filterExpr = "Amazon EC2", "tag:env=prod"
+	expr, err := buildFilter()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(expr.And) != 2 || expr.And[0].Dimensions == nil || expr.And[1].Tags == nil {
+		t.Errorf("buildFilter() = %+v, want service and tag combined with And", expr)
+	}
+
+	serviceFilter, filterExpr = "", "region in (us-east-1"
+	if _, err := buildFilter(); err == nil || !strings.Contains(err.Error(), "invalid --filter") {
+		t.Errorf("buildFilter() error = %v, want invalid --filter error", err)
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/filter"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+// defaultExplainBreadth is how many contributors explain shows at each level unless -n is given
+const defaultExplainBreadth = 3
+
+// explainDimension is a dimension explain can split a change by
+type explainDimension struct {
+	name  string
+	group aws.GroupType
+	key   types.Dimension
+}
+
+// explainDimensions are tried at every level; ties go to the first listed
+var explainDimensions = []explainDimension{
+	{"usage-type", aws.GroupByUsageType, types.DimensionUsageType},
+	{"region", aws.GroupByRegion, types.DimensionRegion},
+	{"account", aws.GroupByAccount, types.DimensionLinkedAccount},
+	{"operation", aws.GroupByOperation, types.DimensionOperation},
+}
+
+var (
+	explainDepth int
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain SERVICE",
+	Short: "Explain a service's cost change by drilling down automatically",
+	Long: `Explain what drove a service's change between two periods.
+
+The change is broken down by usage type, region, account and operation. The
+dimension where the change is most concentrated in a single value is picked,
+its largest movers are shown, and each of them is broken down again by the
+remaining dimensions, down to --depth levels.
+
+Every level costs two queries per dimension tried, so deeper trees take longer
+on the Cost Explorer API; repeated runs are served from the query cache.
+
+Examples:
+  costdiff explain "Amazon Elastic Compute Cloud - Compute"
+  costdiff explain "Amazon EC2" --from 2024-10 --to 2024-11
+  costdiff explain "Amazon EC2" --depth 3 -n 5
+  costdiff explain "Amazon Simple Storage Service" -o json`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: setupFiscalCalendar,
+	RunE:    runExplain,
+}
+
+func init() {
+	explainCmd.Flags().IntVar(&explainDepth, "depth", 2, "Number of levels to drill down")
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	service := args[0]
+	if explainDepth < 1 || explainDepth > len(explainDimensions) {
+		return fmt.Errorf("invalid --depth: %d (must be between 1 and %d)", explainDepth, len(explainDimensions))
+	}
+	breadth := defaultExplainBreadth
+	if cmd.Flags().Changed("top") {
+		breadth = topN
+	}
+
+	// Parse time periods
+	from, to, err := parsePeriods(fromPeriod, toPeriod, false)
+	if err != nil {
+		return fmt.Errorf("invalid date range: %w", err)
+	}
+
+	debugf("From period: %s to %s", from.Start, from.End)
+	debugf("To period: %s to %s", to.Start, to.End)
+
+	// Get metric
+	metric, err := getAWSMetric()
+	if err != nil {
+		return err
+	}
+	debugf("Using metric: %s", metric)
+
+	// Build cost filter
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+	costFilter = filter.And(costFilter, filter.Service(service))
+
+	// Initialize cost data source. Contributors become filters for the next
+	// level down, so they keep their original names rather than aliases.
+	client, err := newSourceFetcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Drill down with spinner
+	spin := newProgressSpinner("Explaining cost change...")
+	defer spin.Stop()
+
+	e := &explainer{ctx: ctx, client: client, metric: metric, from: from, to: to, breadth: breadth}
+
+	total, err := e.compare(aws.GroupByService, costFilter)
+	if err != nil {
+		return handleFetchError(err)
+	}
+	if len(total.Items) == 0 {
+		spin.Stop()
+		return fmt.Errorf("no cost data found for service %q in either period", service)
+	}
+
+	root := diff.NewExplainNode("service", service, total)
+	if err := e.explain(&root, costFilter, explainDimensions, explainDepth); err != nil {
+		return handleFetchError(err)
+	}
+
+	spin.Stop()
+	debugf("Explain used %d queries", e.queries)
+
+	result := &diff.ExplainResult{FromPeriod: from, ToPeriod: to, Root: root, Fiscal: fiscalCalendar}
+
+	// Output
+	return outputExplainResult(result, outputFmt)
+}
+
+// explainer builds an explain tree by comparing both periods one dimension at a time
+type explainer struct {
+	ctx     context.Context
+	client  aws.CostFetcher
+	metric  string
+	from    diff.Period
+	to      diff.Period
+	breadth int
+	queries int
+}
+
+// compare fetches both periods grouped by a single dimension and compares them
+func (e *explainer) compare(group aws.GroupType, costFilter *types.Expression) (*diff.Result, error) {
+	groupBy := []aws.GroupType{group}
+
+	fromCosts, err := e.client.GetCosts(e.ctx, e.from.Start, e.from.End, groupBy, e.metric, costFilter)
+	if err != nil {
+		return nil, err
+	}
+	toCosts, err := e.client.GetCosts(e.ctx, e.to.Start, e.to.End, groupBy, e.metric, costFilter)
+	if err != nil {
+		return nil, err
+	}
+	e.queries += 2
+
+	return diff.Compare(fromCosts, toCosts, e.from, e.to), nil
+}
+
+// explain splits node by whichever of dims best explains its change, then
+// explains each child with the remaining dimensions
+func (e *explainer) explain(node *diff.ExplainNode, costFilter *types.Expression, dims []explainDimension, depth int) error {
+	if depth == 0 || len(dims) == 0 || node.Diff == 0 {
+		return nil
+	}
+
+	scores := make([]diff.DimensionScore, 0, len(dims))
+	breakdowns := make(map[string]*diff.Result, len(dims))
+	for _, dim := range dims {
+		result, err := e.compare(dim.group, costFilter)
+		if err != nil {
+			return err
+		}
+		breakdowns[dim.name] = result
+		scores = append(scores, diff.ScoreDimension(dim.name, result.Items))
+	}
+
+	diff.RankDimensions(scores)
+	best := scores[0].Dimension
+	node.Split(scores, breakdowns[best].Items, e.breadth)
+
+	var bestDim explainDimension
+	remaining := make([]explainDimension, 0, len(dims)-1)
+	for _, dim := range dims {
+		if dim.name == best {
+			bestDim = dim
+		} else {
+			remaining = append(remaining, dim)
+		}
+	}
+
+	for i := range node.Children {
+		child := &node.Children[i]
+		// Sources report costs without a value for the dimension as "Other",
+		// which cannot be filtered on
+		if child.Name == "Other" {
+			continue
+		}
+		childFilter := filter.And(costFilter, filter.Dimension(bestDim.key, child.Name))
+		if err := e.explain(child, childFilter, remaining, depth-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func outputExplainResult(result *diff.ExplainResult, format string) error {
+	switch format {
+	case "table":
+		return output.RenderExplainTable(result)
+	case "json":
+		return output.RenderExplainJSON(result)
+	case "csv":
+		return output.RenderExplainCSV(result)
+	default:
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+	}
+}
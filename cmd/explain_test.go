@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/filter"
+)
+
+// memoryRecord is a single cost in a given month with its dimension values
+type memoryRecord struct {
+	month time.Time
+	dims  map[string]string
+	cost  float64
+}
+
+func (r memoryRecord) Dimension(key string) string { return r.dims[key] }
+func (r memoryRecord) Tag(key string) string       { return "" }
+
+// memoryFetcher serves GetCosts from in-memory records, applying filters and grouping
+type memoryFetcher struct {
+	records []memoryRecord
+	calls   int
+}
+
+func (f *memoryFetcher) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, costFilter *types.Expression) (map[string]float64, error) {
+	f.calls++
+	costs := make(map[string]float64)
+	for _, r := range f.records {
+		if r.month.Before(start) || !r.month.Before(end) || !filter.Match(costFilter, r) {
+			continue
+		}
+		// Like the sources, costs without a value are grouped as "Other"
+		name := r.dims[groupBy[0].Key]
+		if name == "" {
+			name = "Other"
+		}
+		costs[name] += r.cost
+	}
+	return costs, nil
+}
+
+func (f *memoryFetcher) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, costFilter *types.Expression) ([]aws.MonthlyCosts, error) {
+	return nil, nil
+}
+
+func (f *memoryFetcher) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, costFilter *types.Expression) ([]aws.DailyCost, error) {
+	return nil, nil
+}
+
+func (f *memoryFetcher) SetLogger(logger aws.Logger) {}
+
+func TestExplainer_Explain(t *testing.T) {
+	sep := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	oct := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	record := func(month time.Time, region, account, usageType string, cost float64) memoryRecord {
+		return memoryRecord{month: month, cost: cost, dims: map[string]string{
+			"SERVICE":        "Amazon EC2",
+			"REGION":         region,
+			"LINKED_ACCOUNT": account,
+			"USAGE_TYPE":     usageType,
+			"OPERATION":      "RunInstances",
+		}}
+	}
+	fetcher := &memoryFetcher{records: []memoryRecord{
+		record(sep, "us-east-1", "111", "BoxUsage", 100),
+		record(oct, "us-east-1", "111", "BoxUsage", 180),
+		record(sep, "us-east-1", "222", "EBS", 100),
+		record(oct, "us-east-1", "222", "EBS", 170),
+		record(sep, "eu-west-1", "111", "EBS", 100),
+		record(oct, "eu-west-1", "111", "EBS", 110),
+		record(sep, "eu-west-1", "222", "BoxUsage", 100),
+		record(oct, "eu-west-1", "222", "BoxUsage", 100),
+	}}
+
+	e := &explainer{
+		ctx:     context.Background(),
+		client:  fetcher,
+		from:    diff.Period{Start: sep, End: oct},
+		to:      diff.Period{Start: oct, End: oct.AddDate(0, 1, 0)},
+		breadth: 3,
+	}
+	serviceFilter := filter.Service("Amazon EC2")
+
+	total, err := e.compare(aws.GroupByService, serviceFilter)
+	if err != nil {
+		t.Fatalf("compare() error = %v", err)
+	}
+	root := diff.NewExplainNode("service", "Amazon EC2", total)
+	if err := e.explain(&root, serviceFilter, explainDimensions, 2); err != nil {
+		t.Fatalf("explain() error = %v", err)
+	}
+
+	if root.Diff != 160 {
+		t.Errorf("root Diff = %v, want 160", root.Diff)
+	}
+
+	// us-east-1 holds 150 of the 160 change, while accounts and usage types
+	// split it almost evenly. The single operation explains nothing.
+	if root.Scores[0].Dimension != "region" || root.Scores[len(root.Scores)-1].Dimension != "operation" {
+		t.Errorf("ranking = %+v, want region first and operation last", root.Scores)
+	}
+	if len(root.Children) != 2 || root.Children[0].Name != "us-east-1" {
+		t.Fatalf("children = %+v, want us-east-1 then eu-west-1", root.Children)
+	}
+
+	// us-east-1 is split by the remaining dimensions only
+	east := root.Children[0]
+	for _, score := range east.Scores {
+		if score.Dimension == "region" {
+			t.Error("a child should not be split by its parent's dimension again")
+		}
+	}
+	// Usage type and account tie within us-east-1; usage type is listed first
+	if len(east.Children) != 2 || east.Children[0].Dimension != "usage-type" || east.Children[0].Name != "BoxUsage" {
+		t.Fatalf("us-east-1 children = %+v, want usage types BoxUsage then EBS", east.Children)
+	}
+	if east.Children[0].Diff != 80 {
+		t.Errorf("BoxUsage in us-east-1 Diff = %v, want 80", east.Children[0].Diff)
+	}
+
+	// Depth 2 stops below the usage types
+	if east.Children[0].Children != nil {
+		t.Error("nodes at the last level should not be split")
+	}
+
+	// Service totals, then four dimensions at the root and three under each region
+	if want := 2 + 2*4 + 2*2*3; fetcher.calls != want {
+		t.Errorf("GetCosts calls = %d, want %d", fetcher.calls, want)
+	}
+}
+
+func TestExplainer_ExplainSkipsMissingValues(t *testing.T) {
+	sep := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	oct := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	record := func(month time.Time, region, usageType string, cost float64) memoryRecord {
+		return memoryRecord{month: month, cost: cost, dims: map[string]string{
+			"SERVICE":    "Amazon EC2",
+			"REGION":     region,
+			"USAGE_TYPE": usageType,
+		}}
+	}
+	// Global usage has no region
+	fetcher := &memoryFetcher{records: []memoryRecord{
+		record(sep, "us-east-1", "BoxUsage", 100),
+		record(oct, "us-east-1", "BoxUsage", 150),
+		record(sep, "", "BoxUsage", 100),
+		record(oct, "", "BoxUsage", 130),
+		record(sep, "", "EBS", 100),
+		record(oct, "", "EBS", 170),
+	}}
+
+	e := &explainer{
+		ctx:     context.Background(),
+		client:  fetcher,
+		from:    diff.Period{Start: sep, End: oct},
+		to:      diff.Period{Start: oct, End: oct.AddDate(0, 1, 0)},
+		breadth: 3,
+	}
+	serviceFilter := filter.Service("Amazon EC2")
+
+	total, err := e.compare(aws.GroupByService, serviceFilter)
+	if err != nil {
+		t.Fatalf("compare() error = %v", err)
+	}
+	root := diff.NewExplainNode("service", "Amazon EC2", total)
+	if err := e.explain(&root, serviceFilter, explainDimensions[:2], 2); err != nil {
+		t.Fatalf("explain() error = %v", err)
+	}
+
+	if len(root.Children) != 2 || root.Children[0].Dimension != "region" || root.Children[0].Name != "Other" {
+		t.Fatalf("children = %+v, want regions Other then us-east-1", root.Children)
+	}
+	if other := root.Children[0]; other.Scores != nil || other.Children != nil {
+		t.Errorf("Other should not be split, got %+v", other)
+	}
+	if east := root.Children[1]; len(east.Scores) != 1 || east.Scores[0].Dimension != "usage-type" {
+		t.Errorf("us-east-1 scores = %+v, want usage-type", east.Scores)
+	}
+
+	// Service totals, two dimensions at the root and one under us-east-1 only
+	if want := 2 + 2*2 + 2; fetcher.calls != want {
+		t.Errorf("GetCosts calls = %d, want %d", fetcher.calls, want)
+	}
+}
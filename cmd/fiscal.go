@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/fiscal"
+)
+
+// fiscalCalendar is the calendar loaded by setupFiscalCalendar, passed to
+// period parsing and set on results for their labels; nil when none is configured
+var fiscalCalendar *diff.FiscalCalendar
+
+// setupFiscalCalendar loads the fiscal calendar before a command parses its
+// periods. Only the commands that parse periods or label them run it, so a
+// broken calendar file does not stop the others.
+func setupFiscalCalendar(cmd *cobra.Command, args []string) error {
+	// The arguments were valid, so a calendar error is not a usage error
+	cmd.SilenceUsage = true
+
+	calendar, err := loadFiscalCalendar()
+	if err != nil {
+		return err
+	}
+
+	fiscalCalendar = calendar
+	return nil
+}
+
+// loadFiscalCalendar loads the --fiscal file, or the default one when it exists.
+// Returns a nil calendar when fiscal periods are off.
+func loadFiscalCalendar() (*diff.FiscalCalendar, error) {
+	if noFiscal {
+		return nil, nil
+	}
+
+	if fiscalPath != "" {
+		debugf("Using fiscal calendar: %s", fiscalPath)
+		return fiscal.Load(fiscalPath)
+	}
+
+	calendar, path, err := fiscal.LoadDefault()
+	if calendar != nil {
+		debugf("Using fiscal calendar: %s", path)
+	}
+	return calendar, err
+}
+
+// currentPeriods returns the period containing now and the one before it:
+// fiscal periods with a fiscal calendar, calendar months otherwise
+func currentPeriods(now time.Time) (diff.Period, diff.Period) {
+	if fiscalCalendar != nil {
+		periods := fiscalCalendar.Periods(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), 2)
+		return periods[0], periods[1]
+	}
+
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	previous := diff.Period{Start: thisMonth.AddDate(0, -1, 0), End: thisMonth}
+	current := diff.Period{Start: thisMonth, End: thisMonth.AddDate(0, 1, 0)}
+	return previous, current
+}
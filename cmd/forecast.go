@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+// maxForecastMonths is how far ahead Cost Explorer forecasts at monthly granularity
+const maxForecastMonths = 12
+
+var (
+	forecastInterval int
+)
+
+var forecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Forecast end-of-month spend",
+	Long: `Forecast spend for the current month (or through --to) with prediction
+intervals, compared against last month's actual total.
+
+Projected totals combine the costs already incurred this month with the
+Cost Explorer forecast for the remaining days.
+
+Examples:
+  costdiff forecast                  # Projected end-of-month spend
+  costdiff forecast --to 2025-03     # Forecast each month through March 2025
+  costdiff forecast --interval 95    # Wider 95% prediction interval
+  costdiff forecast --service "Amazon Simple Storage Service"`,
+	PreRunE: setupFiscalCalendar,
+	RunE:    runForecast,
+}
+
+func init() {
+	forecastCmd.Flags().IntVar(&forecastInterval, "interval", aws.DefaultPredictionInterval, "Prediction interval level in percent (51-99)")
+	rootCmd.AddCommand(forecastCmd)
+}
+
+func runForecast(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	if forecastInterval < 51 || forecastInterval > 99 {
+		return fmt.Errorf("invalid --interval: %d (must be between 51 and 99)", forecastInterval)
+	}
+
+	// Work out the forecast horizon
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	horizonEnd, err := parseForecastHorizon(toPeriod, today)
+	if err != nil {
+		return err
+	}
+
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	baseline := diff.Period{Start: monthStart.AddDate(0, -1, 0), End: monthStart}
+
+	debugf("Forecast period: %s to %s", today.Format("2006-01-02"), horizonEnd.Format("2006-01-02"))
+
+	// Get metric
+	metric, err := getAWSMetric()
+	if err != nil {
+		return err
+	}
+	debugf("Using metric: %s", metric)
+
+	// Build cost filter
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+
+	// Initialize data sources
+	forecaster, err := newForecaster(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := newCostFetcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Fetch actuals and forecast with spinner
+	spin := newProgressSpinner("Fetching cost forecast...")
+	defer spin.Stop()
+
+	baselineTotal, err := fetchTotal(ctx, client, baseline.Start, baseline.End, metric, costFilter)
+	if err != nil {
+		return handleFetchError(err)
+	}
+
+	// Month-to-date actuals; nothing has been incurred yet on the 1st
+	var monthToDate float64
+	if today.After(monthStart) {
+		monthToDate, err = fetchTotal(ctx, client, monthStart, today, metric, costFilter)
+		if err != nil {
+			return handleFetchError(err)
+		}
+	}
+
+	forecasts, err := forecaster.GetCostForecast(ctx, today, horizonEnd, metric, forecastInterval, costFilter)
+	if err != nil {
+		return handleFetchError(err)
+	}
+
+	spin.Stop()
+
+	months := buildForecastMonths(forecasts, monthToDate, monthStart, horizonEnd)
+	result := diff.CompareForecast(baseline, baselineTotal, months, forecastInterval)
+	result.Fiscal = fiscalCalendar
+
+	// Output
+	return outputForecastResult(result, outputFmt)
+}
+
+// parseForecastHorizon returns the exclusive end of the forecast.
+// An empty value forecasts through the end of the current month.
+func parseForecastHorizon(to string, today time.Time) (time.Time, error) {
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if to == "" {
+		return monthStart.AddDate(0, 1, 0), nil
+	}
+
+	period, err := parseDate(to)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --to date: %w", err)
+	}
+	if !period.End.After(today) {
+		return time.Time{}, fmt.Errorf("--to date (%s) must not be in the past", to)
+	}
+	if period.End.After(monthStart.AddDate(0, maxForecastMonths, 0)) {
+		return time.Time{}, fmt.Errorf("--to date (%s) is too far ahead (forecasts cover at most %d months)", to, maxForecastMonths)
+	}
+
+	return period.End, nil
+}
+
+// fetchTotal returns the total cost for a period
+func fetchTotal(ctx context.Context, client aws.CostFetcher, start, end time.Time, metric string, costFilter *types.Expression) (float64, error) {
+	costs, err := client.GetCosts(ctx, start, end, []aws.GroupType{aws.GroupByService}, metric, costFilter)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, cost := range costs {
+		total += cost
+	}
+	return total, nil
+}
+
+// buildForecastMonths combines month-to-date actuals with forecasts into one
+// projection per calendar month in [monthStart, horizonEnd)
+func buildForecastMonths(forecasts []aws.CostForecast, monthToDate float64, monthStart, horizonEnd time.Time) []diff.ForecastMonth {
+	var months []diff.ForecastMonth
+
+	for start := monthStart; start.Before(horizonEnd); start = start.AddDate(0, 1, 0) {
+		end := start.AddDate(0, 1, 0)
+		if end.After(horizonEnd) {
+			end = horizonEnd
+		}
+
+		month := diff.ForecastMonth{Period: diff.Period{Start: start, End: end}}
+		if start.Equal(monthStart) {
+			month.Actual = monthToDate
+		}
+
+		for _, forecast := range forecasts {
+			if !forecast.Start.Before(start) && forecast.Start.Before(end) {
+				month.Forecast += forecast.Mean
+				month.Lower += forecast.Lower
+				month.Upper += forecast.Upper
+			}
+		}
+
+		month.Total = month.Actual + month.Forecast
+		month.Lower += month.Actual
+		month.Upper += month.Actual
+		months = append(months, month)
+	}
+
+	return months
+}
+
+func outputForecastResult(result *diff.ForecastResult, format string) error {
+	switch format {
+	case "table":
+		return output.RenderForecastTable(result)
+	case "json":
+		return output.RenderForecastJSON(result)
+	case "csv":
+		return output.RenderForecastCSV(result)
+	default:
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+	}
+}
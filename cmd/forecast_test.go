@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+func TestParseForecastHorizon(t *testing.T) {
+	today := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		to      string
+		want    time.Time
+		wantErr bool
+	}{
+		{to: "", want: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{to: "2024-10", want: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{to: "2025-03", want: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{to: "2024-10-20", want: time.Date(2024, 10, 21, 0, 0, 0, 0, time.UTC)},
+		{to: "2024-09", wantErr: true},
+		{to: "2024-10-14", wantErr: true},
+		{to: "2025-10", wantErr: true},
+		{to: "next-month", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.to, func(t *testing.T) {
+			got, err := parseForecastHorizon(tt.to, today)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseForecastHorizon(%q) = %v, want %v", tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildForecastMonths(t *testing.T) {
+	oct := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	nov := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	dec := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	forecasts := []aws.CostForecast{
+		{Start: time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC), End: nov, Mean: 500, Lower: 400, Upper: 650},
+		{Start: nov, End: dec, Mean: 1000, Lower: 800, Upper: 1300},
+	}
+
+	months := buildForecastMonths(forecasts, 450, oct, dec)
+	if len(months) != 2 {
+		t.Fatalf("got %d months, want 2", len(months))
+	}
+
+	october := months[0]
+	if october.Period.Label(nil) != "Oct 2024" {
+		t.Errorf("Period = %s, want Oct 2024", october.Period.Label(nil))
+	}
+	if october.Actual != 450 || october.Forecast != 500 || october.Total != 950 {
+		t.Errorf("October = %+v, want actual 450 + forecast 500 = 950", october)
+	}
+	if october.Lower != 850 || october.Upper != 1100 {
+		t.Errorf("October bounds = %v..%v, want 850..1100", october.Lower, october.Upper)
+	}
+
+	november := months[1]
+	if november.Actual != 0 || november.Total != 1000 || november.Lower != 800 || november.Upper != 1300 {
+		t.Errorf("November = %+v, want forecast only", november)
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+// Exit codes for --fail-on violations. Other errors exit with 1. When
+// conditions of several kinds fail, the code of the first kind listed wins.
+const (
+	ExitTotalViolation = 2 // a total condition failed
+	ExitItemViolation  = 3 // an item condition failed
+	ExitNewViolation   = 4 // a new item condition failed
+)
+
+// ExitError is an error that should end the process with a specific exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// violationExitCodes maps each condition kind to its exit code
+var violationExitCodes = map[diff.ConditionKind]int{
+	diff.ConditionTotal: ExitTotalViolation,
+	diff.ConditionItem:  ExitItemViolation,
+	diff.ConditionNew:   ExitNewViolation,
+}
+
+// gateError returns an ExitError describing the violations of a failed gate,
+// or nil when there is no gate or it passed
+func gateError(cmd *cobra.Command, gate *diff.Gate) error {
+	if gate == nil || gate.Passed {
+		return nil
+	}
+
+	// The output already shows the results; usage would only bury the violations
+	cmd.SilenceUsage = true
+
+	descriptions := make([]string, len(gate.Violations))
+	for i, v := range gate.Violations {
+		descriptions[i] = fmt.Sprintf("%s (%s)", v.Condition, output.FormatViolation(v))
+	}
+
+	kinds := gate.Kinds()
+	return &ExitError{
+		Code: violationExitCodes[kinds[0]],
+		Err:  fmt.Errorf("cost policy violated: %s", strings.Join(descriptions, "; ")),
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+func TestGateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		gate     *diff.Gate
+		wantCode int
+	}{
+		{"no gate", nil, 0},
+		{"passed", &diff.Gate{Passed: true}, 0},
+		{"item", &diff.Gate{Violations: []diff.Violation{
+			{Condition: "item>$100", Kind: diff.ConditionItem, Name: "EC2", Value: 180},
+		}}, ExitItemViolation},
+		{"new and total", &diff.Gate{Violations: []diff.Violation{
+			{Condition: "new>$10", Kind: diff.ConditionNew, Name: "SQS", Value: 60},
+			{Condition: "total>10%", Kind: diff.ConditionTotal, Value: 15, Unit: diff.UnitPercent},
+		}}, ExitTotalViolation},
+		{"new", &diff.Gate{Violations: []diff.Violation{
+			{Condition: "new>$10", Kind: diff.ConditionNew, Name: "SQS", Value: 60},
+		}}, ExitNewViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := gateError(&cobra.Command{}, tt.gate)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Errorf("gateError() = %v, want nil", err)
+				}
+				return
+			}
+
+			var exitErr *ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("gateError() = %v, want an ExitError", err)
+			}
+			if exitErr.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", exitErr.Code, tt.wantCode)
+			}
+			for _, v := range tt.gate.Violations {
+				if !strings.Contains(err.Error(), v.Condition) {
+					t.Errorf("error %q should mention %s", err, v.Condition)
+				}
+			}
+		})
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 	"github.com/briandowns/spinner"
 	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/filter"
 )
 
 var (
@@ -16,10 +19,30 @@ var (
 	groupBy       string
 	tagKey        string
 	serviceFilter string
+	filterExpr    string
+	excludeTypes  []string
+	showCharges   bool
+	alignMTD      bool
+	compareYoY    bool
+	baselineMode  string
+	normalizeMode string
+	paretoPct     float64
+	failOn        []string
 	topN          int
 	outputFmt     string
 	awsProfile    string
 	awsRegion     string
+	dataSource    string
+	recordDir     string
+	replayDir     string
+	noCache       bool
+	noHistory     bool
+	refreshCache  bool
+	aliasesPath   string
+	noAliases     bool
+	budgetPath    string
+	fiscalPath    string
+	noFiscal      bool
 	threshold     float64
 	minCost       float64
 	costMetric    string
@@ -50,10 +73,30 @@ in your AWS spending using the Cost Explorer API.
 Examples:
   costdiff                              # Compare last month vs current month
   costdiff --from 2024-10 --to 2024-12  # Compare specific months
+  costdiff --from 2024-Q2 --to 2024-Q3  # Compare quarters
   costdiff -g tag --tag team            # Group by tag
+  costdiff -g service,region            # Group by service and region
+  costdiff --filter 'tag:env=prod'      # Only include matching costs
+  costdiff --charges                    # Show which charge type drove each change
+  costdiff --mtd                        # Month-to-date vs the same days last month
+  costdiff --yoy                        # This month vs the same month last year
+  costdiff --baseline avg-3m            # This month vs the average of the last 3
+  costdiff --against-snapshot NAME      # This month vs a saved snapshot
+  costdiff --pareto 80                  # Items explaining 80% of the net change
+  costdiff --budget budgets.yaml        # Compare this month's spend with budgets
+  costdiff --fail-on 'total>10%'        # Fail a CI job when spend grows over 10%
+  costdiff -o html > report.html        # Write a self-contained HTML report
   costdiff top                          # Show top cost drivers
-  costdiff watch                        # Show daily cost trend`,
-	RunE: runDiff,
+  costdiff watch                        # Show daily cost trend
+  costdiff trend                        # Show monthly cost trend per service
+  costdiff explain "Amazon EC2"         # Drill into what changed within a service
+  costdiff budget                       # Show spend against monthly budgets
+  costdiff snapshot save NAME           # Save last month's costs as a reference point
+  costdiff compare-files OLD.json NEW.json  # Compare two saved -o json reports offline
+  costdiff backfill                     # Keep closed months beyond Cost Explorer's retention
+  costdiff --source cur:./cur-export    # Use exported CUR files offline`,
+	PreRunE: setupFiscalCalendar,
+	RunE:    runDiff,
 }
 
 func Execute() error {
@@ -62,24 +105,73 @@ func Execute() error {
 
 func init() {
 	// Time period flags
-	rootCmd.PersistentFlags().StringVarP(&fromPeriod, "from", "f", "", "Start period (YYYY-MM or YYYY-MM-DD)")
-	rootCmd.PersistentFlags().StringVarP(&toPeriod, "to", "t", "", "End period (YYYY-MM or YYYY-MM-DD)")
+	rootCmd.PersistentFlags().StringVarP(&fromPeriod, "from", "f", "", "Start period (YYYY-MM, YYYY-MM-DD, YYYY-Qn, YYYY-Wnn, FYnn-Pnn, last-Nd, prev-month, ytd, FROM..TO, ...)")
+	rootCmd.PersistentFlags().StringVarP(&toPeriod, "to", "t", "", "End period (same forms as --from)")
 
 	// Grouping flags
-	rootCmd.PersistentFlags().StringVarP(&groupBy, "group", "g", "service", "Group by: service|usage-type|tag|region|account")
+	rootCmd.PersistentFlags().StringVarP(&groupBy, "group", "g", "service", "Group by: service|usage-type|tag|region|account (comma-separate two for nested grouping, e.g. service,region)")
 	rootCmd.PersistentFlags().StringVar(&tagKey, "tag", "", "Tag key when grouping by tag")
 
 	// Service filter flag
 	rootCmd.PersistentFlags().StringVar(&serviceFilter, "service", "", "Filter by AWS service name (use with -g usage-type for drill-down)")
+	rootCmd.PersistentFlags().StringSliceVar(&excludeTypes, "exclude-record-types", nil, "Exclude charge types, e.g. credit,refund (usage|credit|refund|tax|support|ri-fee|sp-fee|...)")
+	rootCmd.PersistentFlags().StringVar(&filterExpr, "filter", "", `Filter expression, e.g. 'region in (us-east-1,eu-west-1) and tag:env=prod and not service="Tax"'`)
+
+	// Charge breakdown flag (diff only)
+	rootCmd.Flags().BoolVar(&showCharges, "charges", false, "Break each item down by charge type (usage, credit, refund, tax, ...)")
+
+	// Period alignment flags (diff only)
+	rootCmd.Flags().BoolVar(&alignMTD, "mtd", false, "Compare only the days elapsed so far in the --to period (month-to-date)")
+	rootCmd.Flags().StringVar(&normalizeMode, "normalize", normalizeNone, "Normalize costs: none|daily (per-day run rate for periods of unequal length)")
+
+	// Baseline flags (diff only)
+	rootCmd.Flags().BoolVar(&compareYoY, "yoy", false, "Compare the --to period with the same period one year earlier")
+	rootCmd.Flags().StringVar(&baselineMode, "baseline", baselinePrev, "Compare with: prev (the --from period) or avg-N, the average of the N previous equivalent periods, e.g. avg-3m (with --yoy, of the same period in the N previous years)")
+
+	// Snapshot flag (diff only)
+	rootCmd.Flags().StringVar(&againstSnapshot, "against-snapshot", "", "Compare the --to period with a saved snapshot instead of the --from period")
+
+	// Contribution analysis flag (diff only)
+	rootCmd.Flags().Float64Var(&paretoPct, "pareto", 0, "Only show the items that together explain this percentage of the net change, e.g. 80")
+
+	// CI gate flag (diff only)
+	rootCmd.Flags().StringSliceVar(&failOn, "fail-on", nil, "Exit with a non-zero code when a condition is met: total>PCT%|total>$USD|item>$USD|new>$USD")
+
+	// Budget flag (diff only; top and budget register their own)
+	rootCmd.Flags().StringVar(&budgetPath, "budget", "", "Budgets file (YAML or JSON) to compare each item's --to spend with")
 
 	// Output flags
 	rootCmd.PersistentFlags().IntVarP(&topN, "top", "n", 10, "Number of results to show")
-	rootCmd.PersistentFlags().StringVarP(&outputFmt, "format", "o", "table", "Output format: table|json|csv")
+	rootCmd.PersistentFlags().StringVarP(&outputFmt, "format", "o", "table", "Output format: table|json|csv, or markdown|html for diff, top and watch")
 
 	// AWS flags
 	rootCmd.PersistentFlags().StringVarP(&awsProfile, "profile", "p", "", "AWS profile name")
 	rootCmd.PersistentFlags().StringVarP(&awsRegion, "region", "r", "", "AWS region")
 
+	// Data source flag
+	rootCmd.PersistentFlags().StringVar(&dataSource, "source", "", "Cost data source: ce (Cost Explorer API) or cur:/path/to/export")
+
+	// Record/replay flags
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Save every Cost Explorer response to this directory")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Serve Cost Explorer responses from a --record directory instead of AWS")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+
+	// Cache flags
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the local query cache")
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false, "Do not read costs older than Cost Explorer's retention from the local history")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Ignore cached results and re-query Cost Explorer")
+	rootCmd.MarkFlagsMutuallyExclusive("no-cache", "refresh")
+
+	// Alias flags
+	rootCmd.PersistentFlags().StringVar(&aliasesPath, "aliases", "", "Rules file that renames and merges group names (default: costdiff/aliases.json in the user config directory, if present)")
+	rootCmd.PersistentFlags().BoolVar(&noAliases, "no-aliases", false, "Show the original Cost Explorer names, ignoring any aliases file")
+	rootCmd.MarkFlagsMutuallyExclusive("aliases", "no-aliases")
+
+	// Fiscal calendar flags
+	rootCmd.PersistentFlags().StringVar(&fiscalPath, "fiscal", "", "Fiscal calendar file for FY25-P03 style periods and labels (default: costdiff/fiscal.yaml in the user config directory, if present)")
+	rootCmd.PersistentFlags().BoolVar(&noFiscal, "no-fiscal", false, "Use calendar months and labels, ignoring any fiscal calendar file")
+	rootCmd.MarkFlagsMutuallyExclusive("fiscal", "no-fiscal")
+
 	// Filter flags
 	rootCmd.PersistentFlags().Float64Var(&threshold, "threshold", 0, "Only show changes above $X")
 	rootCmd.PersistentFlags().Float64Var(&minCost, "min-cost", 0, "Only show items where from or to cost >= $X")
@@ -126,6 +218,26 @@ func getAWSMetric() (string, error) {
 	return "", fmt.Errorf("invalid metric: %s (valid options: net-amortized, amortized, unblended, blended, net-unblended)", costMetric)
 }
 
+// buildFilter combines --service, --exclude-record-types and --filter into a single
+// Cost Explorer expression. Returns nil when none of them is set.
+func buildFilter() (*types.Expression, error) {
+	expr, err := filter.Parse(filterExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --filter: %w", err)
+	}
+
+	exclude, err := filter.ExcludeRecordTypes(excludeTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	combined := filter.And(filter.Service(serviceFilter), exclude, expr)
+	if combined != nil {
+		debugf("Using filter: %s", filter.Format(combined))
+	}
+	return combined, nil
+}
+
 // progressSpinner manages a spinner for long-running operations
 type progressSpinner struct {
 	spinner *spinner.Spinner
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+	"github.com/hserkanyilmaz/costdiff/internal/snapshot"
+)
+
+var (
+	snapshotForce   bool
+	againstSnapshot string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save cost results as reference points to compare against",
+	Long: `Save the cost of every group over a period under a name, and compare
+later periods against it with costdiff --against-snapshot <name>.
+
+A snapshot remembers the metric, grouping and filters it was taken with, and
+comparisons reuse them. Snapshots are stored in costdiff/snapshots in the user
+config directory; set COSTDIFF_SNAPSHOT_DIR to move them.
+
+Examples:
+  costdiff snapshot save after-migration --from 2024-09  # Pin September's costs
+  costdiff snapshot save prod-regions -g region --filter 'tag:env=prod'
+  costdiff snapshot list                                 # Show saved snapshots
+  costdiff snapshot show after-migration                 # Show a snapshot's costs
+  costdiff snapshot delete after-migration
+  costdiff --against-snapshot after-migration            # This month vs the snapshot`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:     "save <name>",
+	Short:   "Save the costs of a period (last month by default)",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: setupFiscalCalendar,
+	RunE:    runSnapshotSave,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots",
+	Args:  cobra.NoArgs,
+	RunE:  runSnapshotList,
+}
+
+var snapshotShowCmd = &cobra.Command{
+	Use:     "show <name>",
+	Short:   "Show the costs saved in a snapshot",
+	Args:    cobra.ExactArgs(1),
+	PreRunE: setupFiscalCalendar,
+	RunE:    runSnapshotShow,
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a snapshot",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSnapshotDelete,
+}
+
+func init() {
+	snapshotSaveCmd.Flags().BoolVar(&snapshotForce, "force", false, "Replace an existing snapshot of the same name")
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotShowCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshotSave(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	name := args[0]
+	if err := snapshot.ValidateName(name); err != nil {
+		return err
+	}
+	store, err := openSnapshotStore()
+	if err != nil {
+		return err
+	}
+
+	// Default to the last complete month (or fiscal period) rather than a partial one
+	period, _ := currentPeriods(time.Now())
+	if fromPeriod != "" {
+		if period, err = parseDate(fromPeriod); err != nil {
+			return fmt.Errorf("invalid date: %w", err)
+		}
+	}
+
+	debugf("Snapshot period: %s to %s", period.Start, period.End)
+
+	// Validate grouping
+	groupTypes, dimensions, err := parseGrouping(groupBy, tagKey)
+	if err != nil {
+		return err
+	}
+
+	// Get metric
+	metric, err := getAWSMetric()
+	if err != nil {
+		return err
+	}
+	debugf("Using metric: %s", metric)
+
+	// Build cost filter
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+
+	// Initialize cost data source
+	client, err := newCostFetcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Fetch cost data with spinner
+	costs, err := withSpinner("Fetching cost data...", func() (map[string]float64, error) {
+		return client.GetCosts(ctx, period.Start, period.End, groupTypes, metric, costFilter)
+	})
+	if err != nil {
+		return handleFetchError(err)
+	}
+
+	result := buildTopResult(costs, period)
+	result.SetGroupBy(dimensions)
+
+	snap := snapshot.New(name, currentQuery(), result, time.Now())
+	if err := store.Save(snap, snapshotForce); err != nil {
+		return err
+	}
+
+	if !quiet {
+		fmt.Println(output.Success(fmt.Sprintf("Saved snapshot %s: %s, %d items, %s total",
+			name, period.Label(fiscalCalendar), len(result.Items), output.FormatCurrency(result.Total))))
+	}
+	return nil
+}
+
+func runSnapshotList(cmd *cobra.Command, args []string) error {
+	store, err := openSnapshotStore()
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Println(output.Muted(fmt.Sprintf("No snapshots in %s", store.Dir())))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPERIOD\tMETRIC\tGROUP\tTOTAL\tSAVED")
+	for _, snap := range snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			snap.Name,
+			snap.Result.Period.Label,
+			snap.Query.Metric,
+			strings.Join(snap.Result.GroupBy, ","),
+			output.FormatCurrency(snap.Result.Total),
+			snap.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func runSnapshotShow(cmd *cobra.Command, args []string) error {
+	store, err := openSnapshotStore()
+	if err != nil {
+		return err
+	}
+
+	snap, err := store.Load(args[0])
+	if err != nil {
+		return err
+	}
+	period, err := snap.Period()
+	if err != nil {
+		return err
+	}
+
+	result := &diff.TopResult{
+		Period:  period,
+		GroupBy: snap.Result.GroupBy,
+		Total:   snap.Result.Total,
+		Items:   snap.Result.Items,
+		Fiscal:  fiscalCalendar,
+	}
+	all := result.Items
+
+	// Limit results
+	if len(result.Items) > topN {
+		result.Items = result.Items[:topN]
+	}
+
+	// Roll up everything left out so the rows still add up to the total
+	if other, ok := diff.OtherTopItem(all, result.Items); ok {
+		result.Items = append(result.Items, other)
+	}
+
+	return outputTopResult(result, outputFmt)
+}
+
+func runSnapshotDelete(cmd *cobra.Command, args []string) error {
+	store, err := openSnapshotStore()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Delete(args[0]); err != nil {
+		return err
+	}
+
+	if !quiet {
+		fmt.Println(output.Success(fmt.Sprintf("Deleted snapshot %s", args[0])))
+	}
+	return nil
+}
+
+// openSnapshotStore opens the snapshot directory
+func openSnapshotStore() (*snapshot.Store, error) {
+	dir, err := snapshot.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.NewStore(dir), nil
+}
+
+// currentQuery returns the flags of this run that decide which costs are fetched
+func currentQuery() snapshot.Query {
+	return snapshot.Query{
+		Metric:             costMetric,
+		Group:              groupBy,
+		Tag:                tagKey,
+		Service:            serviceFilter,
+		Filter:             filterExpr,
+		ExcludeRecordTypes: excludeTypes,
+	}
+}
+
+// loadAgainstSnapshot loads the --against-snapshot snapshot and makes this
+// run fetch the same costs it holds. Flags that were set explicitly must
+// match the snapshot.
+func loadAgainstSnapshot(cmd *cobra.Command, name string) (*snapshot.Snapshot, error) {
+	for _, flag := range []string{"from", "yoy", "baseline", "mtd", "charges"} {
+		if cmd.Flags().Changed(flag) {
+			return nil, fmt.Errorf("--against-snapshot cannot be combined with --%s", flag)
+		}
+	}
+
+	store, err := openSnapshotStore()
+	if err != nil {
+		return nil, err
+	}
+	snap, err := store.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	debugf("Using snapshot: %s", name)
+
+	if err := useSnapshotQuery(cmd, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// useSnapshotQuery sets the query flags to the snapshot's, failing when one
+// that was set explicitly asks for something else
+func useSnapshotQuery(cmd *cobra.Command, snap *snapshot.Snapshot) error {
+	flags := []struct {
+		name  string
+		value *string
+		saved string
+	}{
+		{"metric", &costMetric, snap.Query.Metric},
+		{"group", &groupBy, snap.Query.Group},
+		{"tag", &tagKey, snap.Query.Tag},
+		{"service", &serviceFilter, snap.Query.Service},
+		{"filter", &filterExpr, snap.Query.Filter},
+	}
+	for _, f := range flags {
+		if cmd.Flags().Changed(f.name) && *f.value != f.saved {
+			return fmt.Errorf("--%s %q does not match snapshot %s, which was saved with %q", f.name, *f.value, snap.Name, f.saved)
+		}
+		*f.value = f.saved
+	}
+
+	saved := strings.Join(snap.Query.ExcludeRecordTypes, ",")
+	if cmd.Flags().Changed("exclude-record-types") && strings.Join(excludeTypes, ",") != saved {
+		return fmt.Errorf("--exclude-record-types %q does not match snapshot %s, which was saved with %q",
+			strings.Join(excludeTypes, ","), snap.Name, saved)
+	}
+	excludeTypes = snap.Query.ExcludeRecordTypes
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/snapshot"
+)
+
+// queryFlagsCommand returns a command with the query flags bound to their
+// globals, restoring the globals when the test ends
+func queryFlagsCommand(t *testing.T) *cobra.Command {
+	t.Helper()
+	saved := currentQuery()
+	t.Cleanup(func() {
+		costMetric, groupBy, tagKey = saved.Metric, saved.Group, saved.Tag
+		serviceFilter, filterExpr, excludeTypes = saved.Service, saved.Filter, saved.ExcludeRecordTypes
+	})
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&costMetric, "metric", "net-amortized", "")
+	cmd.Flags().StringVar(&groupBy, "group", "service", "")
+	cmd.Flags().StringVar(&tagKey, "tag", "", "")
+	cmd.Flags().StringVar(&serviceFilter, "service", "", "")
+	cmd.Flags().StringVar(&filterExpr, "filter", "", "")
+	cmd.Flags().StringSliceVar(&excludeTypes, "exclude-record-types", nil, "")
+	return cmd
+}
+
+func TestUseSnapshotQuery(t *testing.T) {
+	snap := &snapshot.Snapshot{
+		Name: "sep",
+		Query: snapshot.Query{
+			Metric:             "unblended",
+			Group:              "region",
+			Filter:             "tag:env=prod",
+			ExcludeRecordTypes: []string{"credit"},
+		},
+	}
+
+	cmd := queryFlagsCommand(t)
+	if err := cmd.Flags().Set("metric", "unblended"); err != nil {
+		t.Fatal(err)
+	}
+	if err := useSnapshotQuery(cmd, snap); err != nil {
+		t.Fatalf("useSnapshotQuery() error = %v", err)
+	}
+	if costMetric != "unblended" || groupBy != "region" || filterExpr != "tag:env=prod" || strings.Join(excludeTypes, ",") != "credit" {
+		t.Errorf("query = %+v, want the snapshot's", currentQuery())
+	}
+}
+
+func TestUseSnapshotQuery_Conflict(t *testing.T) {
+	snap := &snapshot.Snapshot{Name: "sep", Query: snapshot.Query{Metric: "unblended", Group: "service"}}
+
+	tests := []struct {
+		flag  string
+		value string
+	}{
+		{"group", "region"},
+		{"metric", "amortized"},
+		{"filter", "region=us-east-1"},
+		{"exclude-record-types", "credit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			cmd := queryFlagsCommand(t)
+			if err := cmd.Flags().Set(tt.flag, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			err := useSnapshotQuery(cmd, snap)
+			if err == nil || !strings.Contains(err.Error(), "--"+tt.flag) {
+				t.Errorf("useSnapshotQuery() error = %v, want a --%s mismatch", err, tt.flag)
+			}
+		})
+	}
+}
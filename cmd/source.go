@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/alias"
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/cache"
+	"github.com/hserkanyilmaz/costdiff/internal/cur"
+	"github.com/hserkanyilmaz/costdiff/internal/history"
+)
+
+// Data source kinds accepted by --source
+const (
+	sourceCostExplorer = "ce"
+	sourceCUR          = "cur"
+)
+
+// parseSource splits a --source value into its kind and location.
+// An empty value selects the Cost Explorer API.
+func parseSource(source string) (string, string, error) {
+	if source == "" || source == sourceCostExplorer {
+		return sourceCostExplorer, "", nil
+	}
+
+	kind, location, found := strings.Cut(source, ":")
+	if !found || kind != sourceCUR {
+		return "", "", fmt.Errorf("invalid source: %s (must be ce or cur:/path/to/export)", source)
+	}
+	if location == "" {
+		return "", "", fmt.Errorf("--source cur: requires a path, e.g. cur:/path/to/export")
+	}
+
+	return kind, location, nil
+}
+
+// newCostFetcher creates the cost data backend selected by --source, renaming
+// group keys with the aliases file when there is one
+func newCostFetcher(ctx context.Context) (aws.CostFetcher, error) {
+	fetcher, err := newSourceFetcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return fetcher, nil
+	}
+	return alias.NewFetcher(fetcher, rules), nil
+}
+
+// loadAliases loads the --aliases file, or the default one when it exists.
+// Returns nil rules when aliasing is off.
+func loadAliases() (*alias.Rules, error) {
+	if noAliases {
+		return nil, nil
+	}
+
+	if aliasesPath != "" {
+		debugf("Using aliases: %s", aliasesPath)
+		return alias.Load(aliasesPath)
+	}
+
+	rules, path, err := alias.LoadDefault()
+	if rules != nil {
+		debugf("Using aliases: %s", path)
+	}
+	return rules, err
+}
+
+// newSourceFetcher creates the cost data backend selected by --source, with
+// group keys exactly as the source reports them. Cost Explorer queries that
+// reach back beyond its retention are served from the local history.
+func newSourceFetcher(ctx context.Context) (aws.CostFetcher, error) {
+	fetcher, err := newQueryFetcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recordings hold the requests exactly as made, so they are never split
+	// between the history and Cost Explorer
+	kind, _, _ := parseSource(dataSource)
+	if kind != sourceCostExplorer || noHistory || replayDir != "" || recordDir != "" {
+		return fetcher, nil
+	}
+
+	dir, err := history.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	debugf("Using history: %s (before %s)", dir, history.Horizon(time.Now()).Format("2006-01-02"))
+
+	fetcher = history.NewFetcher(fetcher, history.NewStore(dir), currentProfile())
+	fetcher.SetLogger(cliLogger{})
+	return fetcher, nil
+}
+
+// newQueryFetcher creates the cost data backend selected by --source, which
+// only returns what the source itself still has
+func newQueryFetcher(ctx context.Context) (aws.CostFetcher, error) {
+	kind, location, err := parseSource(dataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == sourceCUR && (recordDir != "" || replayDir != "") {
+		return nil, fmt.Errorf("--record and --replay only apply to the Cost Explorer source")
+	}
+
+	var fetcher aws.CostFetcher
+	switch {
+	case kind == sourceCUR:
+		debugf("Using CUR source: %s", location)
+		fetcher, err = cur.NewClient(location)
+		if err != nil {
+			return nil, err
+		}
+	case replayDir != "":
+		debugf("Replaying Cost Explorer responses from %s", replayDir)
+		fetcher, err = aws.NewReplayClient(replayDir)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		client, err := aws.NewCostExplorerClient(ctx, awsProfile, awsRegion)
+		if err != nil {
+			return nil, handleAWSError(err)
+		}
+		fetcher = client
+		if recordDir != "" {
+			// Skip the cache so every response actually gets recorded
+			debugf("Recording Cost Explorer responses to %s", recordDir)
+			if err := client.Record(recordDir); err != nil {
+				return nil, err
+			}
+		} else if !noCache {
+			fetcher, err = newCachingFetcher(client)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	fetcher.SetLogger(cliLogger{})
+	return fetcher, nil
+}
+
+// newForecaster creates the Cost Explorer client used for forecasts.
+// Forecasts change daily, so they are never cached, and CUR files cannot forecast.
+func newForecaster(ctx context.Context) (aws.Forecaster, error) {
+	kind, _, err := parseSource(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	if kind == sourceCUR {
+		return nil, fmt.Errorf("forecasts require the Cost Explorer API and are not available with --source cur")
+	}
+
+	var client *aws.CostExplorerClient
+	if replayDir != "" {
+		client, err = aws.NewReplayClient(replayDir)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		client, err = aws.NewCostExplorerClient(ctx, awsProfile, awsRegion)
+		if err != nil {
+			return nil, handleAWSError(err)
+		}
+		if recordDir != "" {
+			if err := client.Record(recordDir); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	client.SetLogger(cliLogger{})
+	return client, nil
+}
+
+// newCachingFetcher wraps a Cost Explorer client with the local query cache
+func newCachingFetcher(client aws.CostFetcher) (aws.CostFetcher, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	debugf("Using query cache: %s", dir)
+
+	fetcher := cache.NewFetcher(client, cache.NewStore(dir), currentProfile())
+	fetcher.SetRefresh(refreshCache)
+	return fetcher, nil
+}
+
+// currentProfile returns the AWS profile costs are fetched with, which scopes
+// cached and stored costs
+func currentProfile() string {
+	if awsProfile != "" {
+		return awsProfile
+	}
+	return os.Getenv("AWS_PROFILE")
+}
+
+// usesLiveAPI reports whether cost data comes from live Cost Explorer calls
+func usesLiveAPI() bool {
+	kind, _, _ := parseSource(dataSource)
+	return kind == sourceCostExplorer && replayDir == ""
+}
+
+// handleFetchError translates errors returned by the cost data backend.
+// Only live Cost Explorer errors need AWS-specific guidance.
+func handleFetchError(err error) error {
+	if !usesLiveAPI() {
+		return err
+	}
+	return handleAWSError(err)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/history"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantKind     string
+		wantLocation string
+		wantErr      bool
+	}{
+		{input: "", wantKind: "ce"},
+		{input: "ce", wantKind: "ce"},
+		{input: "cur:/data/cur", wantKind: "cur", wantLocation: "/data/cur"},
+		{input: "cur:./export/2024.parquet", wantKind: "cur", wantLocation: "./export/2024.parquet"},
+		{input: "cur:", wantErr: true},
+		{input: "cur", wantErr: true},
+		{input: "s3://bucket/cur", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			kind, location, err := parseSource(tt.input)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", kind, tt.wantKind)
+			}
+			if location != tt.wantLocation {
+				t.Errorf("location = %q, want %q", location, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestNewSourceFetcher_ReplayBeyondHorizon(t *testing.T) {
+	savedSource, savedReplay, savedHistory := dataSource, replayDir, noHistory
+	t.Cleanup(func() { dataSource, replayDir, noHistory = savedSource, savedReplay, savedHistory })
+	t.Setenv("COSTDIFF_HISTORY_DIR", t.TempDir())
+
+	// A recording made long enough ago that its months passed the horizon
+	start := history.Horizon(time.Now()).AddDate(0, -3, 0)
+	end := start.AddDate(0, 2, 0)
+	dataSource, replayDir, noHistory = "", t.TempDir(), false
+	groupBy := []aws.GroupType{aws.GroupByService}
+	if err := aws.RecordCosts(replayDir, start, end, groupBy, "UnblendedCost", nil, map[string]float64{"Amazon EC2": 42.5}); err != nil {
+		t.Fatalf("RecordCosts() error = %v", err)
+	}
+
+	fetcher, err := newSourceFetcher(context.Background())
+	if err != nil {
+		t.Fatalf("newSourceFetcher() error = %v", err)
+	}
+	costs, err := fetcher.GetCosts(context.Background(), start, end, groupBy, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if costs["Amazon EC2"] != 42.5 {
+		t.Errorf("costs = %v, want the recorded Amazon EC2 cost", costs)
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/hserkanyilmaz/costdiff/internal/aws"
 	"github.com/hserkanyilmaz/costdiff/internal/diff"
 	"github.com/hserkanyilmaz/costdiff/internal/output"
 )
@@ -19,14 +18,20 @@ var topCmd = &cobra.Command{
 	Long: `Show the top cost drivers for the current month (or specified period).
 
 Examples:
-  costdiff top                # Top 10 services this month
-  costdiff top -n 20          # Top 20 services
-  costdiff top -g region      # Top costs by region
-  costdiff top --from 2024-10 # Top costs for October 2024`,
-	RunE: runTop,
+  costdiff top                   # Top 10 services this month
+  costdiff top -n 20             # Top 20 services
+  costdiff top -g region         # Top costs by region
+  costdiff top -g service,region # Top costs by service and region
+  costdiff top --from 2024-10    # Top costs for October 2024
+  costdiff top --from last-30d   # Top costs for the last 30 days
+  costdiff top --from 2024-01-05..2024-02-10 # Top costs for a custom range
+  costdiff top --budget budgets.yaml # Top costs against their budgets`,
+	PreRunE: setupFiscalCalendar,
+	RunE:    runTop,
 }
 
 func init() {
+	topCmd.Flags().StringVar(&budgetPath, "budget", "", "Budgets file (YAML or JSON) to compare each item's spend with")
 	rootCmd.AddCommand(topCmd)
 }
 
@@ -43,11 +48,23 @@ func runTop(cmd *cobra.Command, args []string) error {
 	debugf("Period: %s to %s", period.Start, period.End)
 
 	// Validate grouping
-	groupType, err := parseGroupBy(groupBy, tagKey)
+	groupTypes, dimensions, err := parseGrouping(groupBy, tagKey)
 	if err != nil {
 		return err
 	}
 
+	// Load budgets before spending any queries
+	var budgets diff.Budgets
+	if budgetPath != "" {
+		file, err := loadBudgets(budgetPath)
+		if err != nil {
+			return err
+		}
+		if budgets, err = budgetsFor(file, dimensions, period); err != nil {
+			return err
+		}
+	}
+
 	// Get metric
 	metric, err := getAWSMetric()
 	if err != nil {
@@ -55,23 +72,34 @@ func runTop(cmd *cobra.Command, args []string) error {
 	}
 	debugf("Using metric: %s", metric)
 
-	// Initialize AWS client
-	client, err := aws.NewCostExplorerClient(ctx, awsProfile, awsRegion)
+	// Build cost filter
+	costFilter, err := buildFilter()
 	if err != nil {
-		return handleAWSError(err)
+		return err
+	}
+
+	// Initialize cost data source
+	client, err := newCostFetcher(ctx)
+	if err != nil {
+		return err
 	}
-	client.SetLogger(cliLogger{})
 
 	// Fetch cost data with spinner
 	costs, err := withSpinner("Fetching cost data...", func() (map[string]float64, error) {
-		return client.GetCosts(ctx, period.Start, period.End, groupType, metric, serviceFilter)
+		return client.GetCosts(ctx, period.Start, period.End, groupTypes, metric, costFilter)
 	})
 	if err != nil {
-		return handleAWSError(err)
+		return handleFetchError(err)
 	}
 
 	// Build result
 	result := buildTopResult(costs, period)
+	result.SetGroupBy(dimensions)
+	result.Fiscal = fiscalCalendar
+	if budgetPath != "" {
+		result.SetBudgets(budgets, diff.ProjectionFactor(period, time.Now()))
+	}
+	all := result.Items
 
 	// Apply threshold filter
 	if threshold > 0 {
@@ -83,20 +111,20 @@ func runTop(cmd *cobra.Command, args []string) error {
 		result.Items = result.Items[:topN]
 	}
 
+	// Roll up everything left out so the rows still add up to the total
+	if other, ok := diff.OtherTopItem(all, result.Items); ok {
+		result.Items = append(result.Items, other)
+	}
+
 	// Output
 	return outputTopResult(result, outputFmt)
 }
 
 func parseTopPeriod(from string) (diff.Period, error) {
-	now := time.Now()
-
 	if from == "" {
-		// Default: current month
-		nextMonth := now.AddDate(0, 1, 0)
-		return diff.Period{
-			Start: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
-			End:   time.Date(nextMonth.Year(), nextMonth.Month(), 1, 0, 0, 0, 0, time.UTC),
-		}, nil
+		// Default: current month, or fiscal period with a fiscal calendar
+		_, current := currentPeriods(time.Now())
+		return current, nil
 	}
 
 	return parseDate(from)
@@ -135,9 +163,12 @@ func buildTopResult(costs map[string]float64, period diff.Period) *diff.TopResul
 
 func filterTopByThreshold(result *diff.TopResult, threshold float64) *diff.TopResult {
 	filtered := &diff.TopResult{
-		Period: result.Period,
-		Total:  result.Total,
-		Items:  make([]diff.TopItem, 0),
+		Period:  result.Period,
+		GroupBy: result.GroupBy,
+		Total:   result.Total,
+		Budget:  result.Budget,
+		Items:   make([]diff.TopItem, 0),
+		Fiscal:  result.Fiscal,
 	}
 
 	for _, item := range result.Items {
@@ -157,7 +188,11 @@ func outputTopResult(result *diff.TopResult, format string) error {
 		return output.RenderTopJSON(result)
 	case "csv":
 		return output.RenderTopCSV(result)
+	case "markdown":
+		return output.RenderTopMarkdown(result)
+	case "html":
+		return output.RenderTopHTML(result)
 	default:
-		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv|markdown|html)", format)
 	}
 }
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+var (
+	trendMonths int
+	trendLayout string
+)
+
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Show monthly cost trend per group",
+	Long: `Show monthly costs per group over several months, with a sparkline,
+the change from the first to the last month, and the compound annual growth
+rate (CAGR) for each row.
+
+The range ends with the last complete month, or with the --to month.
+With a fiscal calendar, each month is a fiscal period instead.
+Rows are ordered by total spend unless --sort is given.
+
+Examples:
+  costdiff trend                     # Last 12 complete months by service
+  costdiff trend --months 6          # Last 6 complete months
+  costdiff trend --to 2024-12        # 12 months ending December 2024
+  costdiff trend --to FY25-P12       # Fiscal year 2025 by fiscal period
+  costdiff trend -g service,region   # Trend by service and region
+  costdiff trend -o csv --layout long`,
+	PreRunE: setupFiscalCalendar,
+	RunE:    runTrend,
+}
+
+func init() {
+	trendCmd.Flags().IntVar(&trendMonths, "months", 12, "Number of months to show")
+	trendCmd.Flags().StringVar(&trendLayout, "layout", output.LayoutWide, "JSON/CSV layout: wide (one row per group) or long (one row per group and month)")
+	rootCmd.AddCommand(trendCmd)
+}
+
+func runTrend(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	if trendLayout != output.LayoutWide && trendLayout != output.LayoutLong {
+		return fmt.Errorf("invalid layout: %s (must be wide|long)", trendLayout)
+	}
+
+	// Work out the months to show
+	months, err := parseTrendMonths(toPeriod, trendMonths, time.Now())
+	if err != nil {
+		return err
+	}
+	start, end := months[0].Start, months[len(months)-1].End
+
+	debugf("Trend period: %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+	// Validate grouping
+	groupTypes, dimensions, err := parseGrouping(groupBy, tagKey)
+	if err != nil {
+		return err
+	}
+
+	// Get metric
+	metric, err := getAWSMetric()
+	if err != nil {
+		return err
+	}
+	debugf("Using metric: %s", metric)
+
+	// Build cost filter
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+
+	// Initialize cost data source
+	client, err := newCostFetcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Fetch all months in one query with spinner. Week-based fiscal periods
+	// do not follow calendar months, so they are summed from daily costs.
+	var costs []map[string]float64
+	if isCalendarMonths(months) {
+		monthlyCosts, err := withSpinner("Fetching monthly cost data...", func() ([]aws.MonthlyCosts, error) {
+			return client.GetMonthlyCosts(ctx, start, end, groupTypes, metric, costFilter)
+		})
+		if err != nil {
+			return handleFetchError(err)
+		}
+		costs = alignMonthlyCosts(monthlyCosts, months)
+	} else {
+		dailyCosts, err := withSpinner("Fetching daily cost data...", func() ([]aws.DailyCost, error) {
+			return client.GetDailyCosts(ctx, start, end, groupTypes, metric, costFilter)
+		})
+		if err != nil {
+			return handleFetchError(err)
+		}
+		costs = sumDailyCosts(dailyCosts, months)
+	}
+
+	// Build result
+	result := diff.BuildTrend(months, costs)
+	result.SetGroupBy(dimensions)
+	result.Fiscal = fiscalCalendar
+
+	// Apply sorting
+	if cmd.Flags().Changed("sort") {
+		applyTrendSorting(result.Items, sortBy)
+	}
+
+	// Limit results
+	if len(result.Items) > topN {
+		result.Items = result.Items[:topN]
+	}
+
+	// Output
+	return outputTrendResult(result, outputFmt, trendLayout)
+}
+
+// parseTrendMonths returns the calendar months of the trend, oldest first, or
+// fiscal periods with a fiscal calendar. The last month is the one the --to
+// period ends in, or the last complete month when to is empty.
+func parseTrendMonths(to string, count int, now time.Time) ([]diff.Period, error) {
+	if count < 2 {
+		return nil, fmt.Errorf("invalid --months: %d (a trend needs at least 2 months)", count)
+	}
+
+	previous, _ := currentPeriods(now)
+	lastDay := previous.End.AddDate(0, 0, -1)
+	if to != "" {
+		period, err := parseDate(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --to date: %w", err)
+		}
+		lastDay = period.End.AddDate(0, 0, -1)
+	}
+
+	if fiscalCalendar != nil {
+		return fiscalCalendar.Periods(lastDay, count), nil
+	}
+	last := time.Date(lastDay.Year(), lastDay.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	months := make([]diff.Period, count)
+	for i := range months {
+		start := last.AddDate(0, i-count+1, 0)
+		months[i] = diff.Period{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+	return months, nil
+}
+
+// isCalendarMonths reports whether every period is a single calendar month
+func isCalendarMonths(periods []diff.Period) bool {
+	for _, p := range periods {
+		if p.Start.Day() != 1 || !p.Start.AddDate(0, 1, 0).Equal(p.End) {
+			return false
+		}
+	}
+	return true
+}
+
+// sumDailyCosts returns one cost map per period, summing the grouped costs of
+// the days in each period. Periods without costs get an empty map.
+func sumDailyCosts(dailyCosts []aws.DailyCost, periods []diff.Period) []map[string]float64 {
+	costs := make([]map[string]float64, len(periods))
+	for i, period := range periods {
+		costs[i] = map[string]float64{}
+		for _, day := range dailyCosts {
+			if day.Date.Before(period.Start) || !day.Date.Before(period.End) {
+				continue
+			}
+			for name, cost := range day.Groups {
+				costs[i][name] += cost
+			}
+		}
+	}
+	return costs
+}
+
+// alignMonthlyCosts returns one cost map per month, matching fetched results
+// to months by start date. Months without results get an empty map.
+func alignMonthlyCosts(monthlyCosts []aws.MonthlyCosts, months []diff.Period) []map[string]float64 {
+	costs := make([]map[string]float64, len(months))
+	for i, month := range months {
+		costs[i] = map[string]float64{}
+		for _, mc := range monthlyCosts {
+			if mc.Month.Equal(month.Start) {
+				costs[i] = mc.Costs
+			}
+		}
+	}
+	return costs
+}
+
+func applyTrendSorting(items []diff.TrendItem, sortBy string) {
+	switch sortBy {
+	case "diff":
+		diff.SortTrendByChange(items)
+	case "diff-pct":
+		diff.SortTrendByChangePercent(items)
+	case "cost":
+		diff.SortTrendByCost(items)
+	case "name":
+		diff.SortTrendByName(items)
+	default:
+		// Default to sorting by change
+		diff.SortTrendByChange(items)
+	}
+}
+
+func outputTrendResult(result *diff.TrendResult, format, layout string) error {
+	switch format {
+	case "table":
+		return output.RenderTrendTable(result)
+	case "json":
+		return output.RenderTrendJSON(result, layout)
+	case "csv":
+		return output.RenderTrendCSV(result, layout)
+	default:
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+	}
+}
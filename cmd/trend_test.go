@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+func TestParseTrendMonths(t *testing.T) {
+	now := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		to        string
+		count     int
+		wantFirst string
+		wantLast  string
+		wantErr   bool
+	}{
+		{to: "", count: 12, wantFirst: "2023-10", wantLast: "2024-09"},
+		{to: "", count: 3, wantFirst: "2024-07", wantLast: "2024-09"},
+		{to: "2024-10", count: 2, wantFirst: "2024-09", wantLast: "2024-10"},
+		{to: "2024-03-15", count: 4, wantFirst: "2023-12", wantLast: "2024-03"},
+		{to: "2024-Q2", count: 3, wantFirst: "2024-04", wantLast: "2024-06"},
+		{to: "", count: 1, wantErr: true},
+		{to: "last-month", count: 12, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.to, func(t *testing.T) {
+			months, err := parseTrendMonths(tt.to, tt.count, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(months) != tt.count {
+				t.Fatalf("got %d months, want %d", len(months), tt.count)
+			}
+			if got := months[0].Start.Format("2006-01"); got != tt.wantFirst {
+				t.Errorf("first month = %s, want %s", got, tt.wantFirst)
+			}
+			if got := months[len(months)-1].Start.Format("2006-01"); got != tt.wantLast {
+				t.Errorf("last month = %s, want %s", got, tt.wantLast)
+			}
+			for _, m := range months {
+				if m.Label(nil) != m.Start.Format("Jan 2006") {
+					t.Errorf("month %v is not a full calendar month", m)
+				}
+			}
+		})
+	}
+}
+
+func TestAlignMonthlyCosts(t *testing.T) {
+	months, err := parseTrendMonths("2024-03", 3, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	costs := alignMonthlyCosts([]aws.MonthlyCosts{
+		{Month: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Costs: map[string]float64{"EC2": 30}},
+		{Month: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Costs: map[string]float64{"EC2": 10}},
+	}, months)
+
+	if len(costs) != 3 {
+		t.Fatalf("got %d months, want 3", len(costs))
+	}
+	if costs[0]["EC2"] != 10 || costs[2]["EC2"] != 30 {
+		t.Errorf("costs = %v, want Jan=10 Mar=30", costs)
+	}
+	if costs[1] == nil || len(costs[1]) != 0 {
+		t.Errorf("missing month = %v, want empty map", costs[1])
+	}
+}
+
+func TestApplyTrendSorting(t *testing.T) {
+	items := []diff.TrendItem{
+		{Name: "B", Costs: []float64{10, 50}, Change: 40, ChangePct: 400},
+		{Name: "A", Costs: []float64{100, 40}, Change: -60, ChangePct: -60},
+	}
+
+	applyTrendSorting(items, "diff")
+	if items[0].Name != "A" {
+		t.Errorf("diff: first = %s, want A", items[0].Name)
+	}
+
+	applyTrendSorting(items, "diff-pct")
+	if items[0].Name != "B" {
+		t.Errorf("diff-pct: first = %s, want B", items[0].Name)
+	}
+
+	applyTrendSorting(items, "name")
+	if items[0].Name != "A" {
+		t.Errorf("name: first = %s, want A", items[0].Name)
+	}
+
+	applyTrendSorting(items, "cost")
+	if items[0].Name != "B" {
+		t.Errorf("cost: first = %s, want B", items[0].Name)
+	}
+}
+
+func TestParseTrendMonths_Fiscal(t *testing.T) {
+	fiscalCalendar = &diff.FiscalCalendar{StartMonth: time.February, Weeks: []int{4, 4, 5}, WeekStart: time.Sunday}
+	defer func() { fiscalCalendar = nil }()
+
+	// The last complete fiscal period on Nov 13, 2024 is FY25-P09
+	periods, err := parseTrendMonths("", 3, time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var labels []string
+	for _, p := range periods {
+		labels = append(labels, p.Label(fiscalCalendar))
+	}
+	if strings.Join(labels, ",") != "FY25-P07,FY25-P08,FY25-P09" {
+		t.Errorf("periods = %v, want FY25-P07 to FY25-P09", labels)
+	}
+	if isCalendarMonths(periods) {
+		t.Error("isCalendarMonths() = true for 4-4-5 periods")
+	}
+}
+
+func TestSumDailyCosts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	periods := []diff.Period{
+		{Start: day(3), End: day(10)},
+		{Start: day(10), End: day(17)},
+	}
+
+	costs := sumDailyCosts([]aws.DailyCost{
+		{Date: day(2), Groups: map[string]float64{"EC2": 1000}},
+		{Date: day(3), Groups: map[string]float64{"EC2": 10, "S3": 1}},
+		{Date: day(9), Groups: map[string]float64{"EC2": 20}},
+		{Date: day(10), Groups: map[string]float64{"S3": 5}},
+	}, periods)
+
+	if costs[0]["EC2"] != 30 || costs[0]["S3"] != 1 {
+		t.Errorf("first period = %v, want EC2=30 S3=1", costs[0])
+	}
+	if costs[1]["S3"] != 5 || len(costs[1]) != 1 {
+		t.Errorf("second period = %v, want S3=5", costs[1])
+	}
+}
@@ -21,10 +21,14 @@ var watchCmd = &cobra.Command{
 	Short: "Show daily cost trend",
 	Long: `Show daily cost trend over a period of time.
 
+Days are checked for anomalies against the four weeks before them, using the
+same weekday where possible, and flagged with a severity of low, medium or high.
+
 Examples:
-  costdiff watch              # Last 7 days
-  costdiff watch --days 30    # Last 30 days
-  costdiff watch -g service   # Daily breakdown by service`,
+  costdiff watch                 # Last 7 days
+  costdiff watch --days 30       # Last 30 days
+  costdiff watch -g service      # Daily breakdown by service
+  costdiff watch -g region -n 5  # Top 5 regions, the rest summed as Other`,
 	RunE: runWatch,
 }
 
@@ -44,6 +48,17 @@ func runWatch(cmd *cobra.Command, args []string) error {
 
 	debugf("Watch period: %s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
 
+	// Daily totals unless a grouping is requested explicitly
+	var groupTypes []aws.GroupType
+	var dimensions []string
+	if cmd.Flags().Changed("group") {
+		var err error
+		groupTypes, dimensions, err = parseGrouping(groupBy, tagKey)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get metric
 	metric, err := getAWSMetric()
 	if err != nil {
@@ -51,40 +66,67 @@ func runWatch(cmd *cobra.Command, args []string) error {
 	}
 	debugf("Using metric: %s", metric)
 
-	// Initialize AWS client
-	client, err := aws.NewCostExplorerClient(ctx, awsProfile, awsRegion)
+	// Build cost filter
+	costFilter, err := buildFilter()
+	if err != nil {
+		return err
+	}
+
+	// Initialize cost data source
+	client, err := newCostFetcher(ctx)
 	if err != nil {
-		return handleAWSError(err)
+		return err
 	}
-	client.SetLogger(cliLogger{})
 
-	// Fetch daily cost data with spinner
+	// Fetch daily cost data with spinner, including earlier days as the
+	// baseline for anomaly detection
+	historyStart := startDate.AddDate(0, 0, -diff.AnomalyWindow)
 	dailyCosts, err := withSpinner("Fetching daily cost data...", func() ([]aws.DailyCost, error) {
-		return client.GetDailyCosts(ctx, startDate, endDate, metric)
+		return client.GetDailyCosts(ctx, historyStart, endDate, groupTypes, metric, costFilter)
 	})
 	if err != nil {
-		return handleAWSError(err)
+		return handleFetchError(err)
 	}
 
 	// Build result
 	result := buildWatchResult(dailyCosts, startDate, endDate)
+	if len(groupTypes) > 0 {
+		result.SetGroups(dimensions, topN)
+	}
 
 	// Output
 	return outputWatchResult(result, outputFmt)
 }
 
+// buildWatchResult builds the result for days in [start, end). Earlier days in
+// dailyCosts only serve as the baseline for anomaly detection. The last day is
+// not scored: its costs are still coming in, so it would always look like a drop.
 func buildWatchResult(dailyCosts []aws.DailyCost, start, end time.Time) *diff.WatchResult {
-	var total float64
-	var items []diff.DayItem
-
+	var history []diff.DayItem
 	for _, dc := range dailyCosts {
-		total += dc.Cost
-		items = append(items, diff.DayItem{
-			Date: dc.Date,
-			Cost: dc.Cost,
+		history = append(history, diff.DayItem{
+			Date:   dc.Date,
+			Cost:   dc.Cost,
+			Groups: dc.Groups,
 		})
 	}
 
+	scored := history
+	if n := len(history); n > 0 && !history[n-1].Date.Before(end.AddDate(0, 0, -1)) {
+		scored = history[:n-1]
+	}
+	diff.DetectAnomalies(scored)
+
+	var total float64
+	var items []diff.DayItem
+	for _, item := range history {
+		if item.Date.Before(start) {
+			continue
+		}
+		total += item.Cost
+		items = append(items, item)
+	}
+
 	// Calculate day-over-day changes
 	for i := 1; i < len(items); i++ {
 		prev := items[i-1].Cost
@@ -106,6 +148,7 @@ func buildWatchResult(dailyCosts []aws.DailyCost, start, end time.Time) *diff.Wa
 		EndDate:   end,
 		Total:     total,
 		Average:   avg,
+		Anomalies: diff.CountAnomalies(items),
 		Days:      items,
 	}
 }
@@ -118,7 +161,11 @@ func outputWatchResult(result *diff.WatchResult, format string) error {
 		return output.RenderWatchJSON(result)
 	case "csv":
 		return output.RenderWatchCSV(result)
+	case "markdown":
+		return output.RenderWatchMarkdown(result)
+	case "html":
+		return output.RenderWatchHTML(result)
 	default:
-		return fmt.Errorf("invalid output format: %s (must be table|json|csv)", format)
+		return fmt.Errorf("invalid output format: %s (must be table|json|csv|markdown|html)", format)
 	}
 }
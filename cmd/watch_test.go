@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+func TestBuildWatchResult_UsesHistoryForAnomalies(t *testing.T) {
+	historyStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := historyStart.AddDate(0, 0, 14)
+	end := start.AddDate(0, 0, 3)
+
+	// A spike on the second day, then a last day whose costs are incomplete
+	var dailyCosts []aws.DailyCost
+	for day := historyStart; day.Before(end); day = day.AddDate(0, 0, 1) {
+		cost := 100.0
+		switch {
+		case day.Equal(start.AddDate(0, 0, 1)):
+			cost = 400
+		case day.Equal(end.AddDate(0, 0, -1)):
+			cost = 10
+		}
+		dailyCosts = append(dailyCosts, aws.DailyCost{Date: day, Cost: cost})
+	}
+
+	result := buildWatchResult(dailyCosts, start, end)
+
+	if len(result.Days) != 3 {
+		t.Fatalf("got %d days, want 3", len(result.Days))
+	}
+	if !result.Days[0].Date.Equal(start) {
+		t.Errorf("first day = %v, want %v", result.Days[0].Date, start)
+	}
+	if result.Total != 510 || result.Average != 170 {
+		t.Errorf("total = %v, average = %v; want 510, 170", result.Total, result.Average)
+	}
+	if result.Days[0].Change != 0 {
+		t.Errorf("first day change = %v, want 0", result.Days[0].Change)
+	}
+
+	for i, day := range result.Days[:2] {
+		if !day.Scored {
+			t.Errorf("day %d should be scored from history", i)
+		}
+	}
+	if result.Days[2].Scored || result.Days[2].Anomaly {
+		t.Errorf("last day scored = %t, anomaly = %t; want an unscored incomplete day", result.Days[2].Scored, result.Days[2].Anomaly)
+	}
+	if result.Anomalies != 1 || !result.Days[1].Anomaly {
+		t.Errorf("anomalies = %d, spike anomaly = %t; want 1, true", result.Anomalies, result.Days[1].Anomaly)
+	}
+}
@@ -0,0 +1,164 @@
+// Package alias renames and merges cost group keys using regular expression rules
+package alias
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/filter"
+	"github.com/hserkanyilmaz/costdiff/internal/fsutil"
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
+)
+
+// Rule renames every group key matching Match to Name. Name may refer to
+// submatches of Match, e.g. "$1". An empty Dimension applies the rule to
+// every dimension; otherwise only to keys of that dimension, using the
+// names accepted by -g and --filter (service, region, tag:team, ...).
+type Rule struct {
+	Dimension string `json:"dimension,omitempty"`
+	Match     string `json:"match"`
+	Name      string `json:"name"`
+
+	re *regexp.Regexp
+}
+
+// Rules is an ordered list of rules; the first matching rule wins
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// DefaultPath returns the rules file used when --aliases is not given,
+// honoring COSTDIFF_ALIASES
+func DefaultPath() (string, error) {
+	return fsutil.ConfigDir("COSTDIFF_ALIASES", "aliases.json")
+}
+
+// Load reads and compiles a rules file
+func Load(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aliases file: %w", err)
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse aliases file %s: %w", path, err)
+	}
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("invalid aliases file %s: %w", path, err)
+	}
+
+	return &rules, nil
+}
+
+// LoadDefault loads the rules file at DefaultPath, returning nil rules when it does not exist
+func LoadDefault() (*Rules, string, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	rules, err := Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, path, nil
+	}
+	return rules, path, err
+}
+
+// compile validates every rule and compiles its pattern
+func (r *Rules) compile() error {
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+
+		if rule.Match == "" {
+			return fmt.Errorf("rule %d: match is empty", i+1)
+		}
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return fmt.Errorf("rule %d: invalid match: %w", i+1, err)
+		}
+		rule.re = re
+
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("rule %d: name is empty", i+1)
+		}
+		if strings.Contains(rule.Name, groupkey.Separator) {
+			return fmt.Errorf("rule %d: name %q must not contain %q", i+1, rule.Name, groupkey.Separator)
+		}
+
+		if rule.Dimension != "" && !strings.HasPrefix(rule.Dimension, "tag:") {
+			if _, ok := filter.Dimensions[rule.Dimension]; !ok {
+				return fmt.Errorf("rule %d: unknown dimension %q", i+1, rule.Dimension)
+			}
+		}
+	}
+	return nil
+}
+
+// Name returns the canonical name for a key of the given dimension.
+// Keys no rule matches are returned unchanged.
+func (r *Rules) Name(dimension, key string) string {
+	if r == nil {
+		return key
+	}
+
+	for _, rule := range r.Rules {
+		if rule.Dimension != "" && rule.Dimension != dimension {
+			continue
+		}
+		if match := rule.re.FindStringSubmatchIndex(key); match != nil {
+			return string(rule.re.ExpandString(nil, rule.Name, key, match))
+		}
+	}
+	return key
+}
+
+// Apply renames the keys of costs grouped by the given dimensions, summing the
+// costs of keys that end up with the same name. Composite keys are renamed part
+// by part, each with its own dimension's rules.
+func (r *Rules) Apply(costs map[string]float64, dimensions []string) map[string]float64 {
+	if r == nil || len(r.Rules) == 0 || costs == nil {
+		return costs
+	}
+
+	renamed := make(map[string]float64, len(costs))
+	for key, cost := range costs {
+		renamed[r.key(key, dimensions)] += cost
+	}
+	return renamed
+}
+
+// key renames each part of a composite key
+func (r *Rules) key(key string, dimensions []string) string {
+	if len(dimensions) <= 1 {
+		dimension := ""
+		if len(dimensions) == 1 {
+			dimension = dimensions[0]
+		}
+		return r.Name(dimension, key)
+	}
+
+	parts := groupkey.Split(key, len(dimensions))
+	for i := range parts {
+		parts[i] = r.Name(dimensions[i], parts[i])
+	}
+	return groupkey.Join(parts)
+}
+
+// Dimension returns the rule dimension name of a Cost Explorer group type
+func Dimension(group aws.GroupType) string {
+	if group.Type == "TAG" {
+		return "tag:" + group.Key
+	}
+	for name, dim := range filter.Dimensions {
+		if string(dim) == group.Key {
+			return name
+		}
+	}
+	return strings.ToLower(group.Key)
+}
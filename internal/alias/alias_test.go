@@ -0,0 +1,154 @@
+package alias
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+	return path
+}
+
+func testRules(t *testing.T) *Rules {
+	t.Helper()
+	rules, err := Load(writeRules(t, `{
+		"rules": [
+			{"match": "^(Amazon Elastic Compute Cloud - Compute|EC2 - Other)$", "name": "EC2"},
+			{"dimension": "service", "match": "^Amazon (.+?)( Service)?$", "name": "$1"},
+			{"dimension": "region", "match": "^us-", "name": "US"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return rules
+}
+
+func TestRules_Name(t *testing.T) {
+	rules := testRules(t)
+
+	tests := []struct {
+		dimension string
+		key       string
+		want      string
+	}{
+		{"service", "Amazon Elastic Compute Cloud - Compute", "EC2"},
+		{"service", "EC2 - Other", "EC2"},
+		{"service", "Amazon Relational Database Service", "Relational Database"},
+		{"service", "AWS Lambda", "AWS Lambda"},
+		{"region", "us-east-1", "US"},
+		{"region", "eu-west-1", "eu-west-1"},
+		// Dimension-scoped rules only apply to their dimension
+		{"usage-type", "Amazon Thing", "Amazon Thing"},
+		{"service", "us-east-1", "us-east-1"},
+	}
+
+	for _, tt := range tests {
+		if got := rules.Name(tt.dimension, tt.key); got != tt.want {
+			t.Errorf("Name(%q, %q) = %q, want %q", tt.dimension, tt.key, got, tt.want)
+		}
+	}
+
+	var none *Rules
+	if got := none.Name("service", "EC2 - Other"); got != "EC2 - Other" {
+		t.Errorf("nil rules renamed %q", got)
+	}
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules := testRules(t)
+
+	costs := rules.Apply(map[string]float64{
+		"Amazon Elastic Compute Cloud - Compute": 100,
+		"EC2 - Other":                            25,
+		"AWS Lambda":                             5,
+	}, []string{"service"})
+
+	if len(costs) != 2 || costs["EC2"] != 125 || costs["AWS Lambda"] != 5 {
+		t.Errorf("Apply() = %v, want EC2=125 and AWS Lambda=5", costs)
+	}
+}
+
+func TestRules_ApplyMultiLevel(t *testing.T) {
+	rules := testRules(t)
+
+	costs := rules.Apply(map[string]float64{
+		"EC2 - Other / us-east-1": 10,
+		"EC2 - Other / us-west-2": 20,
+		"EC2 - Other / eu-west-1": 5,
+	}, []string{"service", "region"})
+
+	if len(costs) != 2 || costs["EC2 / US"] != 30 || costs["EC2 / eu-west-1"] != 5 {
+		t.Errorf("Apply() = %v, want EC2 / US=30 and EC2 / eu-west-1=5", costs)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid JSON", `{"rules": [`, "failed to parse"},
+		{"bad regex", `{"rules": [{"match": "(", "name": "x"}]}`, "rule 1: invalid match"},
+		{"empty match", `{"rules": [{"match": "", "name": "x"}]}`, "rule 1: match is empty"},
+		{"empty name", `{"rules": [{"match": "a"}, {"match": "b", "name": " "}]}`, "rule 1: name is empty"},
+		{"separator in name", `{"rules": [{"match": "a", "name": "EC2 / Other"}]}`, "must not contain"},
+		{"unknown dimension", `{"rules": [{"dimension": "planet", "match": "a", "name": "b"}]}`, `unknown dimension "planet"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeRules(t, tt.content))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadDefault(t *testing.T) {
+	t.Setenv("COSTDIFF_ALIASES", filepath.Join(t.TempDir(), "missing.json"))
+
+	rules, _, err := LoadDefault()
+	if err != nil || rules != nil {
+		t.Errorf("LoadDefault() = %v, %v; want nil rules and no error for a missing file", rules, err)
+	}
+
+	t.Setenv("COSTDIFF_ALIASES", writeRules(t, `{"rules": [{"match": "a", "name": "b"}]}`))
+	rules, _, err = LoadDefault()
+	if err != nil || rules == nil || len(rules.Rules) != 1 {
+		t.Errorf("LoadDefault() = %v, %v; want one rule", rules, err)
+	}
+}
+
+func TestDimension(t *testing.T) {
+	tests := []struct {
+		group aws.GroupType
+		want  string
+	}{
+		{aws.GroupByService, "service"},
+		{aws.GroupByAccount, "account"},
+		{aws.GroupByUsageType, "usage-type"},
+		{aws.GroupByRecordType, "record-type"},
+		{aws.GroupType{Type: "TAG", Key: "team"}, "tag:team"},
+	}
+
+	for _, tt := range tests {
+		if got := Dimension(tt.group); got != tt.want {
+			t.Errorf("Dimension(%v) = %q, want %q", tt.group, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package alias
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+// Ensure Fetcher implements aws.CostFetcher
+var _ aws.CostFetcher = (*Fetcher)(nil)
+
+// Fetcher wraps a CostFetcher and renames the group keys of every result.
+// Filters are passed through untouched, so they still use the original names.
+type Fetcher struct {
+	inner aws.CostFetcher
+	rules *Rules
+}
+
+// NewFetcher creates an aliasing wrapper around inner
+func NewFetcher(inner aws.CostFetcher, rules *Rules) *Fetcher {
+	return &Fetcher{inner: inner, rules: rules}
+}
+
+// SetLogger sets the logger for the wrapped client
+func (f *Fetcher) SetLogger(logger aws.Logger) {
+	f.inner.SetLogger(logger)
+}
+
+// GetCosts returns grouped costs with renamed and merged keys
+func (f *Fetcher) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
+	costs, err := f.inner.GetCosts(ctx, start, end, groupBy, metric, filter)
+	if err != nil {
+		return nil, err
+	}
+	return f.rules.Apply(costs, dimensions(groupBy)), nil
+}
+
+// GetMonthlyCosts returns grouped costs per month with renamed and merged keys
+func (f *Fetcher) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.MonthlyCosts, error) {
+	months, err := f.inner.GetMonthlyCosts(ctx, start, end, groupBy, metric, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	dims := dimensions(groupBy)
+	for i := range months {
+		months[i].Costs = f.rules.Apply(months[i].Costs, dims)
+	}
+	return months, nil
+}
+
+// GetDailyCosts returns daily costs with renamed and merged group keys
+func (f *Fetcher) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.DailyCost, error) {
+	days, err := f.inner.GetDailyCosts(ctx, start, end, groupBy, metric, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	dims := dimensions(groupBy)
+	for i := range days {
+		days[i].Groups = f.rules.Apply(days[i].Groups, dims)
+	}
+	return days, nil
+}
+
+// dimensions returns the rule dimension names of group types
+func dimensions(groupBy []aws.GroupType) []string {
+	dims := make([]string, len(groupBy))
+	for i, group := range groupBy {
+		dims[i] = Dimension(group)
+	}
+	return dims
+}
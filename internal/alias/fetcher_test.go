@@ -0,0 +1,68 @@
+package alias
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+// stubFetcher returns the same grouped costs from every method
+type stubFetcher struct {
+	costs map[string]float64
+}
+
+func (f *stubFetcher) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
+	return f.costs, nil
+}
+
+func (f *stubFetcher) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.MonthlyCosts, error) {
+	return []aws.MonthlyCosts{{Month: start, Costs: f.costs}}, nil
+}
+
+func (f *stubFetcher) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.DailyCost, error) {
+	return []aws.DailyCost{{Date: start, Cost: 130, Groups: f.costs}}, nil
+}
+
+func (f *stubFetcher) SetLogger(logger aws.Logger) {}
+
+func TestFetcher(t *testing.T) {
+	inner := &stubFetcher{costs: map[string]float64{
+		"Amazon Elastic Compute Cloud - Compute": 100,
+		"EC2 - Other":                            25,
+		"AWS Lambda":                             5,
+	}}
+	fetcher := NewFetcher(inner, testRules(t))
+
+	ctx := context.Background()
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	groupBy := []aws.GroupType{aws.GroupByService}
+
+	costs, err := fetcher.GetCosts(ctx, start, end, groupBy, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if costs["EC2"] != 125 {
+		t.Errorf("GetCosts() EC2 = %v, want 125", costs["EC2"])
+	}
+
+	months, err := fetcher.GetMonthlyCosts(ctx, start, end, groupBy, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetMonthlyCosts() error = %v", err)
+	}
+	if months[0].Costs["EC2"] != 125 {
+		t.Errorf("GetMonthlyCosts() EC2 = %v, want 125", months[0].Costs["EC2"])
+	}
+
+	days, err := fetcher.GetDailyCosts(ctx, start, end, groupBy, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetDailyCosts() error = %v", err)
+	}
+	if days[0].Groups["EC2"] != 125 || days[0].Cost != 130 {
+		t.Errorf("GetDailyCosts() = %+v, want EC2=125 and an unchanged total", days[0])
+	}
+}
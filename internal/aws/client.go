@@ -7,17 +7,26 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 )
 
 // CostFetcher defines the interface for fetching AWS cost data.
 // This interface allows for easy mocking in tests.
 type CostFetcher interface {
-	// GetCosts fetches cost data for a given period grouped by the specified type.
-	// serviceFilter is optional - pass empty string to include all services.
-	GetCosts(ctx context.Context, start, end time.Time, groupBy GroupType, metric string, serviceFilter string) (map[string]float64, error)
+	// GetCosts fetches cost data for a given period grouped by one or two group types.
+	// With two group types, keys are composite names built with groupkey.Join.
+	// filter is optional - pass nil to include all costs.
+	GetCosts(ctx context.Context, start, end time.Time, groupBy []GroupType, metric string, filter *types.Expression) (map[string]float64, error)
 
-	// GetDailyCosts fetches daily cost data for a given period.
-	GetDailyCosts(ctx context.Context, start, end time.Time, metric string) ([]DailyCost, error)
+	// GetMonthlyCosts fetches grouped costs for each calendar month in [start, end)
+	// with a single paginated query. Months are returned oldest first.
+	// filter is optional - pass nil to include all costs.
+	GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []GroupType, metric string, filter *types.Expression) ([]MonthlyCosts, error)
+
+	// GetDailyCosts fetches daily cost data for a given period, optionally grouped
+	// by one or two group types - pass nil for daily totals only.
+	// filter is optional - pass nil to include all costs.
+	GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []GroupType, metric string, filter *types.Expression) ([]DailyCost, error)
 
 	// SetLogger sets the logger for the client.
 	SetLogger(logger Logger)
@@ -40,7 +49,7 @@ func (noopLogger) Warnf(format string, args ...interface{})  {}
 
 // CostExplorerClient wraps the AWS Cost Explorer client
 type CostExplorerClient struct {
-	client *costexplorer.Client
+	client costExplorerAPI
 	logger Logger
 }
 
@@ -87,10 +96,15 @@ type GroupType struct {
 	Key  string // SERVICE, REGION, LINKED_ACCOUNT, or tag key
 }
 
+// MaxGroupBy is the maximum number of group types Cost Explorer accepts per query
+const MaxGroupBy = 2
+
 // Predefined group types
 var (
-	GroupByService   = GroupType{Type: "DIMENSION", Key: "SERVICE"}
-	GroupByRegion    = GroupType{Type: "DIMENSION", Key: "REGION"}
-	GroupByAccount   = GroupType{Type: "DIMENSION", Key: "LINKED_ACCOUNT"}
-	GroupByUsageType = GroupType{Type: "DIMENSION", Key: "USAGE_TYPE"}
+	GroupByService    = GroupType{Type: "DIMENSION", Key: "SERVICE"}
+	GroupByRegion     = GroupType{Type: "DIMENSION", Key: "REGION"}
+	GroupByAccount    = GroupType{Type: "DIMENSION", Key: "LINKED_ACCOUNT"}
+	GroupByUsageType  = GroupType{Type: "DIMENSION", Key: "USAGE_TYPE"}
+	GroupByOperation  = GroupType{Type: "DIMENSION", Key: "OPERATION"}
+	GroupByRecordType = GroupType{Type: "DIMENSION", Key: "RECORD_TYPE"}
 )
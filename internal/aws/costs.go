@@ -3,24 +3,34 @@ package aws
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
 )
 
 // DailyCost represents cost for a single day
 type DailyCost struct {
-	Date time.Time
-	Cost float64
+	Date   time.Time
+	Cost   float64
+	Groups map[string]float64 // cost per group; nil when not grouped
+}
+
+// MonthlyCosts represents grouped costs for a single calendar month
+type MonthlyCosts struct {
+	Month time.Time
+	Costs map[string]float64
 }
 
-// GetCosts fetches cost data for a given period grouped by the specified type
+// GetCosts fetches cost data for a given period grouped by the specified types
 // Handles pagination automatically to retrieve all results
-// serviceFilter is optional - pass empty string to include all services
-func (c *CostExplorerClient) GetCosts(ctx context.Context, start, end time.Time, groupBy GroupType, metric string, serviceFilter string) (map[string]float64, error) {
+// filter is optional - pass nil to include all costs
+func (c *CostExplorerClient) GetCosts(ctx context.Context, start, end time.Time, groupBy []GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
 	costs := make(map[string]float64)
 	var nextPageToken *string
 
@@ -33,19 +43,10 @@ func (c *CostExplorerClient) GetCosts(ctx context.Context, start, end time.Time,
 			Granularity:   types.GranularityMonthly,
 			Metrics:       []string{metric},
 			GroupBy:       buildGroupDefinition(groupBy),
+			Filter:        filter,
 			NextPageToken: nextPageToken,
 		}
 
-		// Apply service filter if specified
-		if serviceFilter != "" {
-			input.Filter = &types.Expression{
-				Dimensions: &types.DimensionValues{
-					Key:    types.DimensionService,
-					Values: []string{serviceFilter},
-				},
-			}
-		}
-
 		result, err := c.client.GetCostAndUsage(ctx, input)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get cost data: %w", err)
@@ -69,10 +70,72 @@ func (c *CostExplorerClient) GetCosts(ctx context.Context, start, end time.Time,
 	return costs, nil
 }
 
-// GetDailyCosts fetches daily cost data for a given period
-// Handles pagination automatically to retrieve all results
-func (c *CostExplorerClient) GetDailyCosts(ctx context.Context, start, end time.Time, metric string) ([]DailyCost, error) {
+// GetMonthlyCosts fetches grouped cost data for each month of a given period
+// Handles pagination automatically; groups of one month may span several pages
+// filter is optional - pass nil to include all costs
+func (c *CostExplorerClient) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []GroupType, metric string, filter *types.Expression) ([]MonthlyCosts, error) {
+	byMonth := make(map[time.Time]map[string]float64)
+	var nextPageToken *string
+
+	for {
+		input := &costexplorer.GetCostAndUsageInput{
+			TimePeriod: &types.DateInterval{
+				Start: aws.String(start.Format("2006-01-02")),
+				End:   aws.String(end.Format("2006-01-02")),
+			},
+			Granularity:   types.GranularityMonthly,
+			Metrics:       []string{metric},
+			GroupBy:       buildGroupDefinition(groupBy),
+			Filter:        filter,
+			NextPageToken: nextPageToken,
+		}
+
+		result, err := c.client.GetCostAndUsage(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get monthly cost data: %w", err)
+		}
+
+		for _, resultByTime := range result.ResultsByTime {
+			month, err := time.Parse("2006-01-02", *resultByTime.TimePeriod.Start)
+			if err != nil {
+				c.logger.Warnf("failed to parse date %q: %v, skipping entry", *resultByTime.TimePeriod.Start, err)
+				continue
+			}
+
+			costs, ok := byMonth[month]
+			if !ok {
+				costs = make(map[string]float64)
+				byMonth[month] = costs
+			}
+			for _, group := range resultByTime.Groups {
+				costs[getGroupName(group.Keys)] += parseAmount(group.Metrics[metric])
+			}
+		}
+
+		// Check for more pages
+		if result.NextPageToken == nil || *result.NextPageToken == "" {
+			break
+		}
+		nextPageToken = result.NextPageToken
+	}
+
+	monthlyCosts := make([]MonthlyCosts, 0, len(byMonth))
+	for month, costs := range byMonth {
+		monthlyCosts = append(monthlyCosts, MonthlyCosts{Month: month, Costs: costs})
+	}
+	sort.Slice(monthlyCosts, func(i, j int) bool {
+		return monthlyCosts[i].Month.Before(monthlyCosts[j].Month)
+	})
+
+	return monthlyCosts, nil
+}
+
+// GetDailyCosts fetches daily cost data for a given period, grouped when groupBy is set
+// Handles pagination automatically; groups of one day may span several pages
+// filter is optional - pass nil to include all costs
+func (c *CostExplorerClient) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []GroupType, metric string, filter *types.Expression) ([]DailyCost, error) {
 	var dailyCosts []DailyCost
+	byDate := make(map[time.Time]int) // index into dailyCosts
 	var nextPageToken *string
 
 	for {
@@ -83,6 +146,8 @@ func (c *CostExplorerClient) GetDailyCosts(ctx context.Context, start, end time.
 			},
 			Granularity:   types.GranularityDaily,
 			Metrics:       []string{metric},
+			GroupBy:       buildGroupDefinition(groupBy),
+			Filter:        filter,
 			NextPageToken: nextPageToken,
 		}
 
@@ -98,19 +163,28 @@ func (c *CostExplorerClient) GetDailyCosts(ctx context.Context, start, end time.
 				continue
 			}
 
-			var totalCost float64
+			i, ok := byDate[date]
+			if !ok {
+				i = len(dailyCosts)
+				byDate[date] = i
+				dailyCosts = append(dailyCosts, DailyCost{Date: date})
+				if len(groupBy) > 0 {
+					dailyCosts[i].Groups = make(map[string]float64)
+				}
+			}
+			day := &dailyCosts[i]
+
 			if len(resultByTime.Groups) > 0 {
 				for _, group := range resultByTime.Groups {
-					totalCost += parseAmount(group.Metrics[metric])
+					amount := parseAmount(group.Metrics[metric])
+					day.Cost += amount
+					if day.Groups != nil {
+						day.Groups[getGroupName(group.Keys)] += amount
+					}
 				}
-			} else {
-				totalCost = parseAmount(resultByTime.Total[metric])
+			} else if len(groupBy) == 0 {
+				day.Cost = parseAmount(resultByTime.Total[metric])
 			}
-
-			dailyCosts = append(dailyCosts, DailyCost{
-				Date: date,
-				Cost: totalCost,
-			})
 		}
 
 		// Check for more pages
@@ -123,39 +197,46 @@ func (c *CostExplorerClient) GetDailyCosts(ctx context.Context, start, end time.
 	return dailyCosts, nil
 }
 
-// buildGroupDefinition creates the GroupBy definition for the API
-func buildGroupDefinition(groupBy GroupType) []types.GroupDefinition {
-	var groupType types.GroupDefinitionType
+// buildGroupDefinition creates the GroupBy definitions for the API
+func buildGroupDefinition(groupBy []GroupType) []types.GroupDefinition {
+	definitions := make([]types.GroupDefinition, 0, len(groupBy))
 
-	switch groupBy.Type {
-	case "TAG":
-		groupType = types.GroupDefinitionTypeTag
-	default:
-		groupType = types.GroupDefinitionTypeDimension
-	}
+	for _, group := range groupBy {
+		var groupType types.GroupDefinitionType
+
+		switch group.Type {
+		case "TAG":
+			groupType = types.GroupDefinitionTypeTag
+		default:
+			groupType = types.GroupDefinitionTypeDimension
+		}
 
-	return []types.GroupDefinition{
-		{
+		definitions = append(definitions, types.GroupDefinition{
 			Type: groupType,
-			Key:  aws.String(groupBy.Key),
-		},
+			Key:  aws.String(group.Key),
+		})
 	}
+
+	return definitions
 }
 
-// getGroupName extracts a readable name from group keys
+// getGroupName extracts a readable name from group keys.
+// Multiple keys (two-level grouping) are joined into a composite name.
 func getGroupName(keys []string) string {
 	if len(keys) == 0 {
 		return "Unknown"
 	}
 
-	name := keys[0]
-
-	// Clean up common AWS service name prefixes
-	if name == "" {
-		return "Other"
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		// Clean up common AWS service name prefixes
+		if key == "" {
+			key = "Other"
+		}
+		names[i] = key
 	}
 
-	return name
+	return groupkey.Join(names)
 }
 
 // parseAmount parses a MetricValue to float64
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+func TestGetDailyCosts_GroupedPagination(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	// Groups of one day split across two pages are merged into one entry
+	api := &fakeAPI{
+		pages: [][]types.Group{
+			{group("Amazon EC2", "10"), group("Amazon S3", "2")},
+			{group("Amazon EC2", "0.5")},
+		},
+	}
+	client := &CostExplorerClient{client: api, logger: noopLogger{}}
+
+	days, err := client.GetDailyCosts(context.Background(), start, end, []GroupType{GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetDailyCosts() error = %v", err)
+	}
+	if len(days) != 1 {
+		t.Fatalf("got %d days, want 1", len(days))
+	}
+	if days[0].Cost != 12.5 {
+		t.Errorf("Cost = %v, want 12.5", days[0].Cost)
+	}
+	if days[0].Groups["Amazon EC2"] != 10.5 || days[0].Groups["Amazon S3"] != 2 {
+		t.Errorf("Groups = %v, want EC2=10.5 S3=2", days[0].Groups)
+	}
+}
+
+func TestGetMonthlyCosts_Pagination(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	// Groups of one month split across two pages are merged
+	api := &fakeAPI{
+		pages: [][]types.Group{
+			{group("Amazon EC2", "100.50"), group("Amazon S3", "20")},
+			{group("Amazon EC2", "4.50")},
+		},
+	}
+	client := &CostExplorerClient{client: api, logger: noopLogger{}}
+
+	months, err := client.GetMonthlyCosts(context.Background(), start, end, []GroupType{GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetMonthlyCosts() error = %v", err)
+	}
+	if api.calls != 2 {
+		t.Errorf("API calls = %d, want 2", api.calls)
+	}
+	if len(months) != 1 {
+		t.Fatalf("got %d months, want 1", len(months))
+	}
+	if !months[0].Month.Equal(start) {
+		t.Errorf("Month = %v, want %v", months[0].Month, start)
+	}
+	if months[0].Costs["Amazon EC2"] != 105 || months[0].Costs["Amazon S3"] != 20 {
+		t.Errorf("Costs = %v, want EC2=105 S3=20", months[0].Costs)
+	}
+}
@@ -0,0 +1,110 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// DefaultPredictionInterval is the confidence level used for forecast bounds
+const DefaultPredictionInterval = 80
+
+// Forecaster defines the interface for fetching cost forecasts.
+// Only the Cost Explorer API can forecast; offline sources do not implement it.
+type Forecaster interface {
+	// GetCostForecast predicts costs per calendar month for [start, end).
+	// start must not be in the past. interval is the prediction interval
+	// level in percent (51-99).
+	GetCostForecast(ctx context.Context, start, end time.Time, metric string, interval int, filter *types.Expression) ([]CostForecast, error)
+}
+
+// Ensure CostExplorerClient implements Forecaster
+var _ Forecaster = (*CostExplorerClient)(nil)
+
+// CostForecast is the predicted cost for one forecast period
+type CostForecast struct {
+	Start time.Time
+	End   time.Time
+	Mean  float64
+	Lower float64
+	Upper float64
+}
+
+// forecastMetrics maps GetCostAndUsage metric names to GetCostForecast metrics
+var forecastMetrics = map[string]types.Metric{
+	"NetAmortizedCost": types.MetricNetAmortizedCost,
+	"AmortizedCost":    types.MetricAmortizedCost,
+	"UnblendedCost":    types.MetricUnblendedCost,
+	"BlendedCost":      types.MetricBlendedCost,
+	"NetUnblendedCost": types.MetricNetUnblendedCost,
+}
+
+// GetCostForecast fetches a monthly cost forecast for the given period
+func (c *CostExplorerClient) GetCostForecast(ctx context.Context, start, end time.Time, metric string, interval int, filter *types.Expression) ([]CostForecast, error) {
+	forecastMetric, ok := forecastMetrics[metric]
+	if !ok {
+		return nil, fmt.Errorf("forecasts are only available for cost metrics, not %s", metric)
+	}
+
+	input := &costexplorer.GetCostForecastInput{
+		TimePeriod: &types.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(end.Format("2006-01-02")),
+		},
+		Granularity:             types.GranularityMonthly,
+		Metric:                  forecastMetric,
+		PredictionIntervalLevel: aws.Int32(int32(interval)),
+		Filter:                  filter,
+	}
+
+	result, err := c.client.GetCostForecast(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost forecast: %w", err)
+	}
+
+	var forecasts []CostForecast
+	for _, byTime := range result.ForecastResultsByTime {
+		if byTime.TimePeriod == nil || byTime.TimePeriod.Start == nil || byTime.TimePeriod.End == nil {
+			continue
+		}
+		periodStart, err := time.Parse("2006-01-02", *byTime.TimePeriod.Start)
+		if err != nil {
+			c.logger.Warnf("failed to parse date %q: %v, skipping entry", *byTime.TimePeriod.Start, err)
+			continue
+		}
+		periodEnd, err := time.Parse("2006-01-02", *byTime.TimePeriod.End)
+		if err != nil {
+			c.logger.Warnf("failed to parse date %q: %v, skipping entry", *byTime.TimePeriod.End, err)
+			continue
+		}
+
+		forecasts = append(forecasts, CostForecast{
+			Start: periodStart,
+			End:   periodEnd,
+			Mean:  parseValue(byTime.MeanValue),
+			Lower: parseValue(byTime.PredictionIntervalLowerBound),
+			Upper: parseValue(byTime.PredictionIntervalUpperBound),
+		})
+	}
+
+	return forecasts, nil
+}
+
+// parseValue parses an optional numeric string to float64
+func parseValue(value *string) float64 {
+	if value == nil {
+		return 0
+	}
+
+	amount, err := strconv.ParseFloat(*value, 64)
+	if err != nil {
+		return 0
+	}
+
+	return amount
+}
@@ -0,0 +1,78 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+func forecastResult(start, end, mean, lower, upper string) types.ForecastResult {
+	return types.ForecastResult{
+		TimePeriod:                   &types.DateInterval{Start: aws.String(start), End: aws.String(end)},
+		MeanValue:                    aws.String(mean),
+		PredictionIntervalLowerBound: aws.String(lower),
+		PredictionIntervalUpperBound: aws.String(upper),
+	}
+}
+
+func TestGetCostForecast_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	start := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	api := &fakeAPI{
+		forecasts: []types.ForecastResult{
+			forecastResult("2024-10-15", "2024-11-01", "1700.5", "1500", "1900"),
+			forecastResult("2024-11-01", "2024-12-01", "3000", "2500", "3500"),
+		},
+	}
+	recorder := &CostExplorerClient{client: api, logger: noopLogger{}}
+	if err := recorder.Record(dir); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	recorded, err := recorder.GetCostForecast(ctx, start, end, "UnblendedCost", 80, nil)
+	if err != nil {
+		t.Fatalf("GetCostForecast() error = %v", err)
+	}
+	if len(recorded) != 2 {
+		t.Fatalf("got %d forecasts, want 2", len(recorded))
+	}
+	want := CostForecast{Start: start, End: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), Mean: 1700.5, Lower: 1500, Upper: 1900}
+	if recorded[0] != want {
+		t.Errorf("forecast[0] = %+v, want %+v", recorded[0], want)
+	}
+
+	replayer, err := NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("NewReplayClient() error = %v", err)
+	}
+	replayed, err := replayer.GetCostForecast(ctx, start, end, "UnblendedCost", 80, nil)
+	if err != nil {
+		t.Fatalf("GetCostForecast() while replaying error = %v", err)
+	}
+	if api.calls != 1 {
+		t.Errorf("API calls = %d, want 1", api.calls)
+	}
+	if len(replayed) != 2 || replayed[1].Mean != 3000 {
+		t.Errorf("replayed = %+v, want recorded forecasts", replayed)
+	}
+
+	// A different interval is a different request
+	if _, err := replayer.GetCostForecast(ctx, start, end, "UnblendedCost", 95, nil); err == nil {
+		t.Error("expected error for request without recording")
+	}
+}
+
+func TestGetCostForecast_UsageMetric(t *testing.T) {
+	client := &CostExplorerClient{client: &fakeAPI{}, logger: noopLogger{}}
+	start := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	if _, err := client.GetCostForecast(context.Background(), start, start.AddDate(0, 1, 0), "UsageQuantity", 80, nil); err == nil {
+		t.Error("expected error for usage metric")
+	}
+}
@@ -0,0 +1,252 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
+)
+
+// costExplorerAPI is the subset of the Cost Explorer SDK client used by costdiff.
+// It is satisfied by *costexplorer.Client and by the record/replay wrappers.
+type costExplorerAPI interface {
+	GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error)
+	GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error)
+}
+
+// Ensure the record/replay wrappers implement costExplorerAPI
+var (
+	_ costExplorerAPI = (*recordingAPI)(nil)
+	_ costExplorerAPI = (*replayAPI)(nil)
+)
+
+// recording is the on-disk format of a single recorded API call
+type recording struct {
+	Operation string          `json:"operation"`
+	Request   json.RawMessage `json:"request"`
+	Response  json.RawMessage `json:"response"`
+}
+
+// Record makes the client persist every Cost Explorer request/response pair to dir.
+// Recordings can be served back later with NewReplayClient.
+func (c *CostExplorerClient) Record(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	c.client = &recordingAPI{api: c.client, dir: dir, owner: c}
+	return nil
+}
+
+// NewReplayClient creates a client that answers requests from recordings in dir
+// instead of calling AWS. No credentials or network access are needed.
+func NewReplayClient(dir string) (*CostExplorerClient, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access replay directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("replay path %s is not a directory", dir)
+	}
+
+	c := &CostExplorerClient{logger: noopLogger{}}
+	c.client = &replayAPI{dir: dir, owner: c}
+	return c, nil
+}
+
+// RecordCosts writes the recording that a GetCosts call with the same
+// arguments replays as costs. It lets tests of other packages build replay
+// directories without depending on how recordings are named.
+func RecordCosts(dir string, start, end time.Time, groupBy []GroupType, metric string, filter *types.Expression, costs map[string]float64) error {
+	c := &CostExplorerClient{
+		client: &staticAPI{costs: costs, parts: len(groupBy), metric: metric},
+		logger: noopLogger{},
+	}
+	if err := c.Record(dir); err != nil {
+		return err
+	}
+
+	_, err := c.GetCosts(context.Background(), start, end, groupBy, metric, filter)
+	return err
+}
+
+// staticAPI answers every cost query with the same costs on a single page
+type staticAPI struct {
+	costs  map[string]float64
+	parts  int
+	metric string
+}
+
+func (s *staticAPI) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	names := make([]string, 0, len(s.costs))
+	for name := range s.costs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	groups := make([]types.Group, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, types.Group{
+			Keys: groupkey.Split(name, s.parts),
+			Metrics: map[string]types.MetricValue{
+				s.metric: {Amount: aws.String(strconv.FormatFloat(s.costs[name], 'f', -1, 64)), Unit: aws.String("USD")},
+			},
+		})
+	}
+
+	return &costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []types.ResultByTime{{TimePeriod: params.TimePeriod, Groups: groups}},
+	}, nil
+}
+
+func (s *staticAPI) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	return nil, errors.New("forecasts cannot be recorded from static costs")
+}
+
+// recordingAPI passes requests through to the real API and saves each response.
+// owner is the wrapping client, whose logger may be replaced after wrapping.
+type recordingAPI struct {
+	api   costExplorerAPI
+	dir   string
+	owner *CostExplorerClient
+}
+
+func (r *recordingAPI) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	output, err := r.api.GetCostAndUsage(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := saveRecording(r.dir, "GetCostAndUsage", params, output)
+	if err != nil {
+		return nil, err
+	}
+	r.owner.logger.Debugf("Recorded GetCostAndUsage response to %s", path)
+
+	return output, nil
+}
+
+func (r *recordingAPI) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	output, err := r.api.GetCostForecast(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := saveRecording(r.dir, "GetCostForecast", params, output)
+	if err != nil {
+		return nil, err
+	}
+	r.owner.logger.Debugf("Recorded GetCostForecast response to %s", path)
+
+	return output, nil
+}
+
+// replayAPI serves responses from recordings instead of calling the API
+type replayAPI struct {
+	dir   string
+	owner *CostExplorerClient
+}
+
+func (r *replayAPI) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	var output costexplorer.GetCostAndUsageOutput
+	path, err := loadRecording(r.dir, "GetCostAndUsage", params, &output)
+	if err != nil {
+		return nil, err
+	}
+	r.owner.logger.Debugf("Replayed GetCostAndUsage response from %s", path)
+
+	return &output, nil
+}
+
+func (r *replayAPI) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	var output costexplorer.GetCostForecastOutput
+	path, err := loadRecording(r.dir, "GetCostForecast", params, &output)
+	if err != nil {
+		return nil, err
+	}
+	r.owner.logger.Debugf("Replayed GetCostForecast response from %s", path)
+
+	return &output, nil
+}
+
+// recordingPath returns the file that holds the recording for a request.
+// Requests are identified by a hash of their JSON encoding, which includes the
+// page token, so every page of a paginated query gets its own file.
+func recordingPath(dir, operation string, params interface{}) (string, []byte, error) {
+	request, err := json.Marshal(params)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode %s request: %w", operation, err)
+	}
+
+	sum := sha256.Sum256(append([]byte(operation+"\n"), request...))
+	name := fmt.Sprintf("%s-%s.json", operation, hex.EncodeToString(sum[:])[:16])
+
+	return filepath.Join(dir, name), request, nil
+}
+
+// saveRecording writes a request/response pair to dir
+func saveRecording(dir, operation string, params, output interface{}) (string, error) {
+	path, request, err := recordingPath(dir, operation, params)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := json.Marshal(output)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s response: %w", operation, err)
+	}
+
+	data, err := json.MarshalIndent(recording{
+		Operation: operation,
+		Request:   request,
+		Response:  response,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode recording: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write recording: %w", err)
+	}
+
+	return path, nil
+}
+
+// loadRecording reads the recorded response for a request from dir into output
+func loadRecording(dir, operation string, params, output interface{}) (string, error) {
+	path, _, err := recordingPath(dir, operation, params)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no recorded %s response in %s for this request (%s); record it first with --record", operation, dir, filepath.Base(path))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return "", fmt.Errorf("invalid recording %s: %w", path, err)
+	}
+	if err := json.Unmarshal(rec.Response, output); err != nil {
+		return "", fmt.Errorf("invalid recording %s: %w", path, err)
+	}
+
+	return path, nil
+}
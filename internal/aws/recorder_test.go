@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// fakeAPI returns one page per entry in pages, linked by page tokens,
+// and forecasts as given
+type fakeAPI struct {
+	pages     [][]types.Group
+	forecasts []types.ForecastResult
+	calls     int
+}
+
+func (f *fakeAPI) GetCostAndUsage(ctx context.Context, params *costexplorer.GetCostAndUsageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostAndUsageOutput, error) {
+	f.calls++
+
+	page := 0
+	if params.NextPageToken != nil {
+		page = int((*params.NextPageToken)[0] - '0')
+	}
+
+	output := &costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []types.ResultByTime{
+			{
+				TimePeriod: params.TimePeriod,
+				Groups:     f.pages[page],
+			},
+		},
+	}
+	if page+1 < len(f.pages) {
+		output.NextPageToken = aws.String(string(rune('0' + page + 1)))
+	}
+	return output, nil
+}
+
+func (f *fakeAPI) GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	f.calls++
+	return &costexplorer.GetCostForecastOutput{ForecastResultsByTime: f.forecasts}, nil
+}
+
+func group(name, amount string) types.Group {
+	return types.Group{
+		Keys: []string{name},
+		Metrics: map[string]types.MetricValue{
+			"UnblendedCost": {Amount: aws.String(amount), Unit: aws.String("USD")},
+		},
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	api := &fakeAPI{
+		pages: [][]types.Group{
+			{group("Amazon EC2", "100.50"), group("Amazon S3", "20")},
+			{group("AWS Lambda", "3.25")},
+		},
+	}
+	recorder := &CostExplorerClient{client: api, logger: noopLogger{}}
+	if err := recorder.Record(dir); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	recorded, err := recorder.GetCosts(ctx, start, end, []GroupType{GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() while recording error = %v", err)
+	}
+	if api.calls != 2 {
+		t.Errorf("API calls = %d, want 2 (one per page)", api.calls)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "GetCostAndUsage-*.json"))
+	if len(files) != 2 {
+		t.Fatalf("got %d recording files, want 2", len(files))
+	}
+
+	replayer, err := NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("NewReplayClient() error = %v", err)
+	}
+
+	replayed, err := replayer.GetCosts(ctx, start, end, []GroupType{GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() while replaying error = %v", err)
+	}
+	if api.calls != 2 {
+		t.Errorf("replay called the API: calls = %d, want 2", api.calls)
+	}
+
+	if len(replayed) != len(recorded) {
+		t.Fatalf("replayed %d groups, recorded %d", len(replayed), len(recorded))
+	}
+	for name, cost := range recorded {
+		if replayed[name] != cost {
+			t.Errorf("replayed[%q] = %v, want %v", name, replayed[name], cost)
+		}
+	}
+	if replayed["AWS Lambda"] != 3.25 {
+		t.Errorf("replayed[AWS Lambda] = %v, want 3.25", replayed["AWS Lambda"])
+	}
+}
+
+func TestRecordCosts(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	groupBy := []GroupType{GroupByService, GroupByRegion}
+	costs := map[string]float64{"Amazon EC2 / us-east-1": 100.5, "Amazon S3 / eu-west-1": 20}
+
+	if err := RecordCosts(dir, start, start.AddDate(0, 1, 0), groupBy, "UnblendedCost", nil, costs); err != nil {
+		t.Fatalf("RecordCosts() error = %v", err)
+	}
+
+	replayer, err := NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("NewReplayClient() error = %v", err)
+	}
+	replayed, err := replayer.GetCosts(context.Background(), start, start.AddDate(0, 1, 0), groupBy, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("replay GetCosts() error = %v", err)
+	}
+	if len(replayed) != len(costs) {
+		t.Fatalf("replayed %d groups, want %d", len(replayed), len(costs))
+	}
+	for name, cost := range costs {
+		if replayed[name] != cost {
+			t.Errorf("replayed[%s] = %v, want %v", name, replayed[name], cost)
+		}
+	}
+}
+
+func TestReplay_MissingRecording(t *testing.T) {
+	replayer, err := NewReplayClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewReplayClient() error = %v", err)
+	}
+
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	_, err = replayer.GetCosts(context.Background(), start, start.AddDate(0, 1, 0), []GroupType{GroupByService}, "UnblendedCost", nil)
+	if err == nil {
+		t.Error("expected error for request without recording")
+	}
+}
+
+func TestNewReplayClient_InvalidDir(t *testing.T) {
+	if _, err := NewReplayClient(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+
+	file := filepath.Join(t.TempDir(), "file.json")
+	if err := os.WriteFile(file, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReplayClient(file); err == nil {
+		t.Error("expected error when replay path is a file")
+	}
+}
@@ -0,0 +1,155 @@
+// Package budget loads monthly cost budgets from a YAML or JSON file
+package budget
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/fsutil"
+)
+
+// dimensions are the groupings a budget can be set for, besides tag:<key>
+var dimensions = []string{"service", "usage-type", "region", "account"}
+
+// Budgets holds monthly budgets by grouping dimension and group name, using
+// the names accepted by -g (service, region, tag:team, ...), e.g.
+//
+//	total: 20000
+//	budgets:
+//	  service:
+//	    Amazon Elastic Compute Cloud - Compute: 8000
+//	  tag:team:
+//	    web: 5000
+//
+// JSON is valid YAML, so the same structure can be written as JSON.
+type Budgets struct {
+	Total   *float64                      `yaml:"total"`
+	Budgets map[string]map[string]float64 `yaml:"budgets"`
+}
+
+// DefaultPath returns the budgets file used when --budget is not given,
+// honoring COSTDIFF_BUDGETS
+func DefaultPath() (string, error) {
+	return fsutil.ConfigDir("COSTDIFF_BUDGETS", "budgets.yaml")
+}
+
+// Load reads and validates a budgets file
+func Load(path string) (*Budgets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budgets file: %w", err)
+	}
+
+	var budgets Budgets
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&budgets); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse budgets file %s: %w", path, err)
+	}
+	if err := budgets.validate(); err != nil {
+		return nil, fmt.Errorf("invalid budgets file %s: %w", path, err)
+	}
+
+	return &budgets, nil
+}
+
+// validate checks dimensions and amounts
+func (b *Budgets) validate() error {
+	if b.Total == nil && len(b.Budgets) == 0 {
+		return fmt.Errorf("no budgets defined")
+	}
+	if b.Total != nil && *b.Total < 0 {
+		return fmt.Errorf("total must not be negative")
+	}
+
+	for dimension, amounts := range b.Budgets {
+		if !isDimension(dimension) {
+			return fmt.Errorf("unknown dimension %q (must be %s or tag:<key>)", dimension, strings.Join(dimensions, "|"))
+		}
+		for name, amount := range amounts {
+			if amount < 0 {
+				return fmt.Errorf("%s %q: budget must not be negative", dimension, name)
+			}
+		}
+	}
+	return nil
+}
+
+// isDimension reports whether a budget can be set for a grouping dimension
+func isDimension(dimension string) bool {
+	if tag, ok := strings.CutPrefix(dimension, "tag:"); ok {
+		return tag != ""
+	}
+	for _, name := range dimensions {
+		if dimension == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Dimensions returns the dimensions that have budgets, sorted
+func (b *Budgets) Dimensions() []string {
+	dims := make([]string, 0, len(b.Budgets))
+	for dimension := range b.Budgets {
+		dims = append(dims, dimension)
+	}
+	sort.Strings(dims)
+	return dims
+}
+
+// For returns the budgets of a grouping dimension for a period, prorated from
+// the monthly amounts. Keys match the group keys of costs grouped by that
+// dimension. An error is returned when the file has neither budgets for the
+// dimension nor a total.
+func (b *Budgets) For(dimension string, period diff.Period) (diff.Budgets, error) {
+	amounts, ok := b.Budgets[dimension]
+	if !ok && b.Total == nil {
+		return diff.Budgets{}, fmt.Errorf("no budgets for %s (the budgets file has %s)", dimension, strings.Join(b.Dimensions(), ", "))
+	}
+
+	// Tag groups are keyed "<key>$<value>", as Cost Explorer returns them
+	prefix := ""
+	if tag, ok := strings.CutPrefix(dimension, "tag:"); ok {
+		prefix = tag + "$"
+	}
+
+	budgets := diff.Budgets{Items: make(map[string]float64, len(amounts))}
+	for name, amount := range amounts {
+		budgets.Items[prefix+name] = Prorate(amount, period)
+	}
+	if b.Total != nil {
+		total := Prorate(*b.Total, period)
+		budgets.Total = &total
+	}
+	return budgets, nil
+}
+
+// Prorate converts a monthly amount to the budget for a period, by the share
+// of each calendar month the period covers. A whole month gets the full amount.
+func Prorate(monthly float64, period diff.Period) float64 {
+	var budget float64
+	for start := period.Start; start.Before(period.End); {
+		monthStart := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+		monthEnd := monthStart.AddDate(0, 1, 0)
+		end := monthEnd
+		if period.End.Before(end) {
+			end = period.End
+		}
+
+		month := diff.Period{Start: monthStart, End: monthEnd}
+		covered := diff.Period{Start: start, End: end}
+		budget += monthly * float64(covered.Days()) / float64(month.Days())
+		start = end
+	}
+	return budget
+}
@@ -0,0 +1,159 @@
+package budget
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+func writeBudgets(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write budgets: %v", err)
+	}
+	return path
+}
+
+func month(year int, m time.Month) diff.Period {
+	start := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+	return diff.Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+func TestLoad(t *testing.T) {
+	yamlPath := writeBudgets(t, "budgets.yaml", `
+total: 20000
+budgets:
+  service:
+    Amazon Elastic Compute Cloud - Compute: 8000
+    Amazon Simple Storage Service: 1500.50
+  tag:team:
+    web: 5000
+`)
+	jsonPath := writeBudgets(t, "budgets.json", `{
+		"total": 20000,
+		"budgets": {
+			"service": {"Amazon Elastic Compute Cloud - Compute": 8000, "Amazon Simple Storage Service": 1500.50},
+			"tag:team": {"web": 5000}
+		}
+	}`)
+
+	for _, path := range []string{yamlPath, jsonPath} {
+		budgets, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", filepath.Base(path), err)
+		}
+		if budgets.Total == nil || *budgets.Total != 20000 {
+			t.Errorf("%s: Total = %v, want 20000", filepath.Base(path), budgets.Total)
+		}
+		if got := budgets.Budgets["service"]["Amazon Simple Storage Service"]; got != 1500.50 {
+			t.Errorf("%s: S3 budget = %v, want 1500.50", filepath.Base(path), got)
+		}
+		if got := budgets.Dimensions(); strings.Join(got, ",") != "service,tag:team" {
+			t.Errorf("%s: Dimensions() = %v", filepath.Base(path), got)
+		}
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty", ``, "no budgets defined"},
+		{"unknown dimension", "budgets:\n  instance-type:\n    m5.large: 10\n", `unknown dimension "instance-type"`},
+		{"empty tag key", "budgets:\n  'tag:':\n    web: 10\n", `unknown dimension "tag:"`},
+		{"negative", "budgets:\n  service:\n    Amazon EC2: -5\n", "budget must not be negative"},
+		{"negative total", "total: -1\n", "total must not be negative"},
+		{"unknown field", "totals: 100\n", "failed to parse"},
+		{"not a number", "budgets:\n  service:\n    Amazon EC2: lots\n", "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeBudgets(t, "budgets.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBudgets_For(t *testing.T) {
+	total := 3000.0
+	budgets := &Budgets{
+		Total: &total,
+		Budgets: map[string]map[string]float64{
+			"service":  {"Amazon EC2": 1000},
+			"tag:team": {"web": 500, "": 100},
+		},
+	}
+	oct := month(2024, time.October)
+
+	services, err := budgets.For("service", oct)
+	if err != nil {
+		t.Fatalf("For(service) error = %v", err)
+	}
+	if services.Items["Amazon EC2"] != 1000 || services.Total == nil || *services.Total != 3000 {
+		t.Errorf("For(service) = %+v, want EC2=1000 and total 3000", services)
+	}
+
+	// Tag budgets are keyed like Cost Explorer tag groups
+	teams, err := budgets.For("tag:team", oct)
+	if err != nil {
+		t.Fatalf("For(tag:team) error = %v", err)
+	}
+	if teams.Items["team$web"] != 500 || teams.Items["team$"] != 100 {
+		t.Errorf("For(tag:team) items = %v, want team$web=500 and team$=100", teams.Items)
+	}
+
+	// Dimensions without budgets still get the total
+	regions, err := budgets.For("region", oct)
+	if err != nil || len(regions.Items) != 0 || regions.Total == nil {
+		t.Errorf("For(region) = %+v, %v, want only the total", regions, err)
+	}
+
+	budgets.Total = nil
+	if _, err := budgets.For("region", oct); err == nil || !strings.Contains(err.Error(), "no budgets for region") {
+		t.Errorf("For(region) without total error = %v", err)
+	}
+}
+
+func TestProrate(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		period diff.Period
+		want   float64
+	}{
+		{"whole month", month(2024, time.October), 3100},
+		{"whole short month", month(2024, time.February), 3100},
+		{"first ten days", diff.Period{Start: day(2024, 10, 1), End: day(2024, 10, 11)}, 1000},
+		{"single day", diff.Period{Start: day(2024, 2, 10), End: day(2024, 2, 11)}, 3100.0 / 29},
+		{"across months", diff.Period{Start: day(2024, 9, 16), End: day(2024, 10, 16)}, 3100.0*15/30 + 1500},
+		{"quarter", diff.Period{Start: day(2024, 10, 1), End: day(2025, 1, 1)}, 9300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prorate(3100, tt.period); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Prorate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultPath_Env(t *testing.T) {
+	t.Setenv("COSTDIFF_BUDGETS", "/tmp/my-budgets.yaml")
+	path, err := DefaultPath()
+	if err != nil || path != "/tmp/my-budgets.yaml" {
+		t.Errorf("DefaultPath() = %q, %v, want the COSTDIFF_BUDGETS value", path, err)
+	}
+}
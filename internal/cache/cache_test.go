@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/filter"
+)
+
+// countingFetcher returns fixed data and counts how often it is called
+type countingFetcher struct {
+	calls int
+}
+
+func (f *countingFetcher) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
+	f.calls++
+	return map[string]float64{"EC2": 100, "S3": 25.5}, nil
+}
+
+func (f *countingFetcher) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.MonthlyCosts, error) {
+	f.calls++
+	return []aws.MonthlyCosts{{Month: start, Costs: map[string]float64{"EC2": 100}}}, nil
+}
+
+func (f *countingFetcher) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.DailyCost, error) {
+	f.calls++
+	return []aws.DailyCost{{Date: start, Cost: 10}, {Date: start.AddDate(0, 0, 1), Cost: 12}}, nil
+}
+
+func (f *countingFetcher) SetLogger(logger aws.Logger) {}
+
+var (
+	sep = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	oct = time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	nov = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newTestFetcher(t *testing.T, now time.Time) (*Fetcher, *countingFetcher, *Store) {
+	t.Helper()
+	inner := &countingFetcher{}
+	store := NewStore(t.TempDir())
+	store.now = func() time.Time { return now }
+	f := NewFetcher(inner, store, "default")
+	f.now = func() time.Time { return now }
+	return f, inner, store
+}
+
+func TestIsFinalized(t *testing.T) {
+	tests := []struct {
+		name string
+		end  time.Time
+		now  time.Time
+		want bool
+	}{
+		{"closed month", oct, time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC), true},
+		{"previous month still settling", oct, time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC), false},
+		{"previous month settled", oct, time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC), true},
+		{"current month", nov, time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC), false},
+		{"day in current month", time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC), time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsFinalized(tt.end, tt.now); got != tt.want {
+				t.Errorf("IsFinalized(%v, %v) = %v, want %v", tt.end, tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_CachesFinalizedPeriods(t *testing.T) {
+	now := time.Date(2024, 11, 20, 12, 0, 0, 0, time.UTC)
+	f, inner, store := newTestFetcher(t, now)
+	ctx := context.Background()
+
+	first, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+
+	// Much later, the finalized entry is still served from cache
+	store.now = func() time.Time { return now.AddDate(1, 0, 0) }
+	second, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("inner calls = %d, want 1", inner.calls)
+	}
+	if second["S3"] != first["S3"] || second["EC2"] != first["EC2"] {
+		t.Errorf("cached costs = %v, want %v", second, first)
+	}
+
+	// Any key component change is a different query
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByRegion}, "UnblendedCost", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "AmortizedCost", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", filter.Service("Amazon EC2")); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 4 {
+		t.Errorf("inner calls = %d, want 4", inner.calls)
+	}
+}
+
+func TestFetcher_OpenPeriodExpires(t *testing.T) {
+	now := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+	f, inner, store := newTestFetcher(t, now)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := f.GetDailyCosts(ctx, oct, nov, nil, "UnblendedCost", nil); err != nil {
+			t.Fatalf("GetDailyCosts() error = %v", err)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("inner calls within TTL = %d, want 1", inner.calls)
+	}
+
+	store.now = func() time.Time { return now.Add(OpenPeriodTTL + time.Minute) }
+	days, err := f.GetDailyCosts(ctx, oct, nov, nil, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetDailyCosts() error = %v", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("inner calls after TTL = %d, want 2", inner.calls)
+	}
+	if len(days) != 2 || !days[0].Date.Equal(oct) || days[1].Cost != 12 {
+		t.Errorf("unexpected daily costs: %+v", days)
+	}
+}
+
+func TestFetcher_Refresh(t *testing.T) {
+	f, inner, _ := newTestFetcher(t, time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC))
+	ctx := context.Background()
+
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil); err != nil {
+		t.Fatal(err)
+	}
+	f.SetRefresh(true)
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil); err != nil {
+		t.Fatal(err)
+	}
+	f.SetRefresh(false)
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("inner calls = %d, want 2", inner.calls)
+	}
+}
+
+func TestFetcher_CorruptEntryIsMiss(t *testing.T) {
+	f, inner, store := newTestFetcher(t, time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC))
+	ctx := context.Background()
+
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(store.Dir(), "*.json"))
+	for _, file := range files {
+		if err := os.WriteFile(file, []byte("{not json"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := f.GetCosts(ctx, sep, oct, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil); err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("inner calls = %d, want 2", inner.calls)
+	}
+}
+
+func TestStore_StatsAndClear(t *testing.T) {
+	now := time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC)
+	store := NewStore(filepath.Join(t.TempDir(), "cache"))
+	store.now = func() time.Time { return now }
+
+	// Stats on a missing directory is empty, not an error
+	stats, err := store.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 0 {
+		t.Errorf("Entries = %d, want 0", stats.Entries)
+	}
+
+	if err := store.Put(Key{Operation: "GetCosts", Start: "2024-09-01"}, true, map[string]float64{"EC2": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(Key{Operation: "GetCosts", Start: "2024-10-01"}, false, map[string]float64{"EC2": 2}); err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return now.Add(2 * OpenPeriodTTL) }
+	if err := store.Put(Key{Operation: "GetDailyCosts", Start: "2024-10-01"}, false, []int{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err = store.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 3 || stats.Finalized != 1 || stats.Open != 1 || stats.Expired != 1 {
+		t.Errorf("Stats() = %+v, want 3 entries (1 finalized, 1 open, 1 expired)", stats)
+	}
+	if stats.Bytes == 0 {
+		t.Error("Bytes should be non-zero")
+	}
+
+	removed, err := store.Clear()
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("Clear() removed %d, want 3", removed)
+	}
+	if stats, _ := store.Stats(); stats.Entries != 0 {
+		t.Errorf("Entries after Clear() = %d, want 0", stats.Entries)
+	}
+}
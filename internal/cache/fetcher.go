@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	costfilter "github.com/hserkanyilmaz/costdiff/internal/filter"
+)
+
+// SettleDays is how long after a month ends its costs are still treated as open.
+// Cost Explorer keeps adjusting the previous month for a few days after it closes.
+const SettleDays = 3
+
+// Ensure Fetcher implements aws.CostFetcher
+var _ aws.CostFetcher = (*Fetcher)(nil)
+
+// Fetcher wraps a CostFetcher and caches its results in a Store.
+// Results for closed months are kept permanently; results that include the
+// current month are reused for OpenPeriodTTL.
+type Fetcher struct {
+	inner   aws.CostFetcher
+	store   *Store
+	profile string
+	refresh bool
+	logger  aws.Logger
+	now     func() time.Time
+}
+
+// NewFetcher creates a caching wrapper around inner.
+// profile scopes entries so different AWS accounts never share results.
+func NewFetcher(inner aws.CostFetcher, store *Store, profile string) *Fetcher {
+	return &Fetcher{
+		inner:   inner,
+		store:   store,
+		profile: profile,
+		logger:  noopLogger{},
+		now:     time.Now,
+	}
+}
+
+// SetRefresh makes the fetcher ignore existing entries and overwrite them with fresh results
+func (f *Fetcher) SetRefresh(refresh bool) {
+	f.refresh = refresh
+}
+
+// SetLogger sets the logger for the fetcher and the wrapped client
+func (f *Fetcher) SetLogger(logger aws.Logger) {
+	if logger != nil {
+		f.logger = logger
+	}
+	f.inner.SetLogger(logger)
+}
+
+// GetCosts returns cached grouped costs, fetching and storing them on a miss
+func (f *Fetcher) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
+	key := Key{
+		Operation:   "GetCosts",
+		Start:       start.Format("2006-01-02"),
+		End:         end.Format("2006-01-02"),
+		Granularity: "MONTHLY",
+		GroupBy:     groupKey(groupBy),
+		Metric:      metric,
+		Filter:      costfilter.Format(filter),
+		Profile:     f.profile,
+	}
+
+	var costs map[string]float64
+	if f.lookup(key, &costs) {
+		return costs, nil
+	}
+
+	costs, err := f.inner.GetCosts(ctx, start, end, groupBy, metric, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	f.save(key, end, costs)
+	return costs, nil
+}
+
+// GetMonthlyCosts returns cached per-month costs, fetching and storing them on a miss
+func (f *Fetcher) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.MonthlyCosts, error) {
+	key := Key{
+		Operation:   "GetMonthlyCosts",
+		Start:       start.Format("2006-01-02"),
+		End:         end.Format("2006-01-02"),
+		Granularity: "MONTHLY",
+		GroupBy:     groupKey(groupBy),
+		Metric:      metric,
+		Filter:      costfilter.Format(filter),
+		Profile:     f.profile,
+	}
+
+	var monthlyCosts []aws.MonthlyCosts
+	if f.lookup(key, &monthlyCosts) {
+		return monthlyCosts, nil
+	}
+
+	monthlyCosts, err := f.inner.GetMonthlyCosts(ctx, start, end, groupBy, metric, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	f.save(key, end, monthlyCosts)
+	return monthlyCosts, nil
+}
+
+// GetDailyCosts returns cached daily costs, fetching and storing them on a miss
+func (f *Fetcher) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.DailyCost, error) {
+	key := Key{
+		Operation:   "GetDailyCosts",
+		Start:       start.Format("2006-01-02"),
+		End:         end.Format("2006-01-02"),
+		Granularity: "DAILY",
+		GroupBy:     groupKey(groupBy),
+		Metric:      metric,
+		Filter:      costfilter.Format(filter),
+		Profile:     f.profile,
+	}
+
+	var dailyCosts []aws.DailyCost
+	if f.lookup(key, &dailyCosts) {
+		return dailyCosts, nil
+	}
+
+	dailyCosts, err := f.inner.GetDailyCosts(ctx, start, end, groupBy, metric, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	f.save(key, end, dailyCosts)
+	return dailyCosts, nil
+}
+
+// lookup loads a cached result into v, reporting whether it was found.
+// Cache problems are logged and treated as misses so they never break a run.
+func (f *Fetcher) lookup(key Key, v interface{}) bool {
+	if f.refresh {
+		return false
+	}
+
+	found, err := f.store.Get(key, v)
+	if err != nil {
+		f.logger.Warnf("cache: %v", err)
+		return false
+	}
+	if found {
+		f.logger.Debugf("cache hit: %s %s..%s", key.Operation, key.Start, key.End)
+	}
+	return found
+}
+
+// save stores a result, marking it finalized when the period has settled
+func (f *Fetcher) save(key Key, end time.Time, v interface{}) {
+	finalized := IsFinalized(end, f.now())
+	if err := f.store.Put(key, finalized, v); err != nil {
+		f.logger.Warnf("cache: %v", err)
+		return
+	}
+	f.logger.Debugf("cache store: %s %s..%s (finalized=%t)", key.Operation, key.Start, key.End, finalized)
+}
+
+// groupKey encodes group types for use in a cache key
+func groupKey(groupBy []aws.GroupType) string {
+	parts := make([]string, len(groupBy))
+	for i, group := range groupBy {
+		parts[i] = group.Type + ":" + group.Key
+	}
+	return strings.Join(parts, ",")
+}
+
+// IsFinalized reports whether costs for a period ending at end (exclusive) can no
+// longer change: the period must end before the current month, and the month
+// must have closed at least SettleDays ago.
+func IsFinalized(end, now time.Time) bool {
+	settled := now.UTC().AddDate(0, 0, -SettleDays)
+	monthStart := time.Date(settled.Year(), settled.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return !end.After(monthStart)
+}
+
+// noopLogger is a logger that does nothing
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Warnf(format string, args ...interface{})  {}
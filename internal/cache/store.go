@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/fsutil"
+)
+
+const (
+	// OpenPeriodTTL is how long results for periods that can still change are reused
+	OpenPeriodTTL = time.Hour
+
+	// keyVersion is bumped whenever the key or entry format changes,
+	// which invalidates all existing entries
+	keyVersion = 2
+
+	// entryExt is the file extension of cache entries
+	entryExt = ".json"
+)
+
+// Key identifies a cached query
+type Key struct {
+	Version     int    `json:"version"`
+	Operation   string `json:"operation"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	Granularity string `json:"granularity"`
+	GroupBy     string `json:"group_by,omitempty"`
+	Metric      string `json:"metric"`
+	Filter      string `json:"filter,omitempty"`
+	Profile     string `json:"profile,omitempty"`
+}
+
+// entry is the on-disk format of a cached query result
+type entry struct {
+	Key       Key             `json:"key"`
+	CreatedAt time.Time       `json:"created_at"`
+	Finalized bool            `json:"finalized"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Stats summarizes the contents of a cache directory
+type Stats struct {
+	Dir       string
+	Entries   int
+	Finalized int
+	Open      int
+	Expired   int
+	Bytes     int64
+}
+
+// Store is a directory of cached query results, one JSON file per query
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore creates a store rooted at dir. The directory is created on first write.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// DefaultDir returns the cache directory, honoring COSTDIFF_CACHE_DIR
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("COSTDIFF_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine cache directory: %w", err)
+	}
+	return filepath.Join(base, "costdiff"), nil
+}
+
+// Dir returns the directory the store reads and writes
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Get loads the cached result for key into v.
+// It reports false when there is no usable entry: missing, expired or unreadable.
+func (s *Store) Get(key Key, v interface{}) (bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false, fmt.Errorf("corrupt cache entry %s: %w", s.path(key), err)
+	}
+	if s.expired(e) {
+		return false, nil
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return false, fmt.Errorf("corrupt cache entry %s: %w", s.path(key), err)
+	}
+
+	return true, nil
+}
+
+// Put stores v as the result for key. Finalized entries never expire;
+// other entries are reused for OpenPeriodTTL.
+func (s *Store) Put(key Key, finalized bool, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	key.Version = keyVersion
+	encoded, err := json.Marshal(entry{
+		Key:       key,
+		CreatedAt: s.now().UTC(),
+		Finalized: finalized,
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	if err := fsutil.WriteFile(s.path(key), encoded); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Stats scans the store and summarizes its entries
+func (s *Store) Stats() (Stats, error) {
+	stats := Stats{Dir: s.dir}
+
+	files, err := s.entryFiles()
+	if err != nil {
+		return stats, err
+	}
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return stats, fmt.Errorf("failed to read cache entry: %w", err)
+		}
+		stats.Entries++
+		stats.Bytes += int64(len(data))
+
+		var e entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			stats.Expired++
+			continue
+		}
+		switch {
+		case e.Finalized:
+			stats.Finalized++
+		case s.expired(e):
+			stats.Expired++
+		default:
+			stats.Open++
+		}
+	}
+
+	return stats, nil
+}
+
+// Clear removes all entries and returns how many were deleted
+func (s *Store) Clear() (int, error) {
+	files, err := s.entryFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, path := range files {
+		if err := os.Remove(path); err != nil {
+			return i, fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+	}
+
+	return len(files), nil
+}
+
+// entryFiles lists the entry files in the store
+func (s *Store) entryFiles() ([]string, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var files []string
+	for _, de := range dirEntries {
+		if !de.IsDir() && strings.HasSuffix(de.Name(), entryExt) {
+			files = append(files, filepath.Join(s.dir, de.Name()))
+		}
+	}
+	return files, nil
+}
+
+// expired reports whether an open entry is older than OpenPeriodTTL
+func (s *Store) expired(e entry) bool {
+	return !e.Finalized && s.now().Sub(e.CreatedAt) > OpenPeriodTTL
+}
+
+// path returns the entry file for a key
+func (s *Store) path(key Key) string {
+	key.Version = keyVersion
+	encoded, _ := json.Marshal(key)
+	sum := sha256.Sum256(encoded)
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])[:32]+entryExt)
+}
@@ -0,0 +1,240 @@
+package cur
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	costfilter "github.com/hserkanyilmaz/costdiff/internal/filter"
+)
+
+// Ensure Client implements aws.CostFetcher
+var _ aws.CostFetcher = (*Client)(nil)
+
+// Client answers cost queries from Cost and Usage Report files on disk.
+// Files are loaded lazily on the first query and kept in memory afterwards.
+type Client struct {
+	path   string
+	logger aws.Logger
+
+	once  sync.Once
+	items []lineItem
+	err   error
+}
+
+// NewClient creates a CUR client for the given file or export directory.
+// Directories are searched recursively for .csv, .csv.gz and .parquet files.
+func NewClient(path string) (*Client, error) {
+	if path == "" {
+		return nil, fmt.Errorf("CUR source path is empty")
+	}
+
+	files, err := findReportFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no CUR files (.csv, .csv.gz, .parquet) found in %s", path)
+	}
+
+	return &Client{
+		path:   path,
+		logger: noopLogger{},
+	}, nil
+}
+
+// SetLogger sets the logger for the client
+func (c *Client) SetLogger(logger aws.Logger) {
+	if logger != nil {
+		c.logger = logger
+	}
+}
+
+// GetCosts sums line items in [start, end) grouped by the specified types.
+// filter is optional - pass nil to include all costs.
+func (c *Client) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
+	items, err := c.lineItems()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if err := validateGroupBy(groupBy); err != nil {
+		return nil, err
+	}
+
+	costs := make(map[string]float64)
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if item.date.Before(start) || !item.date.Before(end) {
+			continue
+		}
+		if !costfilter.Match(filter, item) {
+			continue
+		}
+		costs[item.groupKey(groupBy)] += item.metrics[metric]
+	}
+
+	return costs, nil
+}
+
+// GetMonthlyCosts sums line items per calendar month in [start, end) grouped by the specified types.
+// Every month in the range is reported; months without line items have no costs.
+func (c *Client) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.MonthlyCosts, error) {
+	var monthlyCosts []aws.MonthlyCosts
+	for month := truncateMonth(start); month.Before(end); month = month.AddDate(0, 1, 0) {
+		monthStart := month
+		if monthStart.Before(start) {
+			monthStart = start
+		}
+		monthEnd := month.AddDate(0, 1, 0)
+		if monthEnd.After(end) {
+			monthEnd = end
+		}
+
+		costs, err := c.GetCosts(ctx, monthStart, monthEnd, groupBy, metric, filter)
+		if err != nil {
+			return nil, err
+		}
+		monthlyCosts = append(monthlyCosts, aws.MonthlyCosts{Month: month, Costs: costs})
+	}
+
+	return monthlyCosts, nil
+}
+
+// GetDailyCosts sums line items per day in [start, end), grouped when groupBy is set.
+// Days without any line items are reported with zero cost, matching Cost Explorer.
+func (c *Client) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.DailyCost, error) {
+	items, err := c.lineItems()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	if err := validateGroupBy(groupBy); err != nil {
+		return nil, err
+	}
+
+	byDay := make(map[time.Time]float64)
+	byDayGroup := make(map[time.Time]map[string]float64)
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if item.date.Before(start) || !item.date.Before(end) {
+			continue
+		}
+		if !costfilter.Match(filter, item) {
+			continue
+		}
+		day := truncateDay(item.date)
+		byDay[day] += item.metrics[metric]
+		if len(groupBy) > 0 {
+			if byDayGroup[day] == nil {
+				byDayGroup[day] = make(map[string]float64)
+			}
+			byDayGroup[day][item.groupKey(groupBy)] += item.metrics[metric]
+		}
+	}
+
+	var dailyCosts []aws.DailyCost
+	for day := truncateDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		dailyCost := aws.DailyCost{
+			Date: day,
+			Cost: byDay[day],
+		}
+		if len(groupBy) > 0 {
+			dailyCost.Groups = byDayGroup[day]
+			if dailyCost.Groups == nil {
+				dailyCost.Groups = make(map[string]float64)
+			}
+		}
+		dailyCosts = append(dailyCosts, dailyCost)
+	}
+
+	return dailyCosts, nil
+}
+
+// lineItems loads all report files once and returns the parsed line items
+func (c *Client) lineItems() ([]lineItem, error) {
+	c.once.Do(func() {
+		files, err := findReportFiles(c.path)
+		if err != nil {
+			c.err = err
+			return
+		}
+
+		for _, file := range files {
+			c.logger.Debugf("Reading CUR file %s", file)
+			items, err := readReportFile(file, c.logger)
+			if err != nil {
+				c.err = fmt.Errorf("failed to read CUR file %s: %w", file, err)
+				return
+			}
+			c.items = append(c.items, items...)
+		}
+
+		sort.Slice(c.items, func(i, j int) bool {
+			return c.items[i].date.Before(c.items[j].date)
+		})
+		c.logger.Debugf("Loaded %d CUR line items from %d files", len(c.items), len(files))
+	})
+
+	return c.items, c.err
+}
+
+// validateFilter rejects filters on data that CUR files do not carry
+func validateFilter(filter *types.Expression) error {
+	return costfilter.Walk(filter, func(expr *types.Expression) error {
+		if expr.CostCategories != nil {
+			return fmt.Errorf("filtering by cost category is not supported for CUR data")
+		}
+		if expr.Dimensions != nil {
+			if _, ok := dimensionColumns[string(expr.Dimensions.Key)]; !ok {
+				return fmt.Errorf("filtering by %s is not supported for CUR data", expr.Dimensions.Key)
+			}
+		}
+		return nil
+	})
+}
+
+// validateGroupBy rejects groupings by dimensions that CUR files do not carry
+func validateGroupBy(groupBy []aws.GroupType) error {
+	for _, group := range groupBy {
+		if group.Type == "TAG" {
+			continue
+		}
+		if _, ok := dimensionColumns[group.Key]; !ok {
+			return fmt.Errorf("grouping by %s is not supported for CUR data", group.Key)
+		}
+	}
+	return nil
+}
+
+// truncateDay returns midnight UTC of the given time's date
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// truncateMonth returns midnight UTC of the first day of the given time's month
+func truncateMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// noopLogger is a logger that does nothing
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Warnf(format string, args ...interface{})  {}
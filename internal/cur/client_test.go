@@ -0,0 +1,404 @@
+package cur
+
+import (
+	"compress/gzip"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/filter"
+)
+
+const legacyCSV = `identity/LineItemId,lineItem/UsageStartDate,lineItem/LineItemType,lineItem/UsageAccountId,lineItem/UsageType,lineItem/UnblendedCost,lineItem/BlendedCost,lineItem/UsageAmount,product/ProductName,product/region,savingsPlan/SavingsPlanEffectiveCost,resourceTags/user:team
+1,2024-01-05T00:00:00Z,Usage,111111111111,BoxUsage:t3.medium,10.00,9.00,24,Amazon Elastic Compute Cloud,us-east-1,,platform
+2,2024-01-05T00:00:00Z,SavingsPlanCoveredUsage,111111111111,BoxUsage:m5.large,20.00,20.00,24,Amazon Elastic Compute Cloud,eu-west-1,12.50,data
+3,2024-01-06T00:00:00Z,SavingsPlanNegation,111111111111,BoxUsage:m5.large,-20.00,-20.00,0,Amazon Elastic Compute Cloud,eu-west-1,,data
+4,2024-01-06T00:00:00Z,Usage,222222222222,TimedStorage-ByteHrs,5.00,5.00,100,Amazon Simple Storage Service,us-east-1,,
+5,2024-02-01T00:00:00Z,Usage,111111111111,BoxUsage:t3.medium,12.00,12.00,24,Amazon Elastic Compute Cloud,us-east-1,,platform
+6,not-a-date,Usage,111111111111,BoxUsage:t3.medium,99.00,99.00,24,Amazon Elastic Compute Cloud,us-east-1,,platform
+`
+
+var (
+	jan = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func assertCost(t *testing.T, costs map[string]float64, name string, want float64) {
+	t.Helper()
+	if got := costs[name]; math.Abs(got-want) > 0.001 {
+		t.Errorf("costs[%q] = %v, want %v", name, got, want)
+	}
+}
+
+func TestNewClient_Errors(t *testing.T) {
+	if _, err := NewClient(""); err == nil {
+		t.Error("expected error for empty path")
+	}
+	if _, err := NewClient(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing path")
+	}
+	if _, err := NewClient(t.TempDir()); err == nil {
+		t.Error("expected error for directory without CUR files")
+	}
+
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	writeFile(t, manifest, "{}")
+	if _, err := NewClient(manifest); err == nil {
+		t.Error("expected error for unsupported file type")
+	}
+}
+
+func TestGetCosts_LegacyCSV(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "report-1.csv"), legacyCSV)
+
+	client, err := NewClient(dir)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	costs, err := client.GetCosts(ctx, jan, feb, []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	assertCost(t, costs, "Amazon Elastic Compute Cloud", 10)
+	assertCost(t, costs, "Amazon Simple Storage Service", 5)
+
+	// Amortized cost uses the Savings Plan effective rate and drops negations
+	costs, err = client.GetCosts(ctx, jan, feb, []aws.GroupType{aws.GroupByService}, "AmortizedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	assertCost(t, costs, "Amazon Elastic Compute Cloud", 22.5)
+
+	costs, err = client.GetCosts(ctx, jan, feb, []aws.GroupType{aws.GroupByRegion}, "BlendedCost", filter.Service("Amazon Elastic Compute Cloud"))
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if len(costs) != 2 {
+		t.Errorf("got %d regions, want 2: %v", len(costs), costs)
+	}
+	assertCost(t, costs, "us-east-1", 9)
+	assertCost(t, costs, "eu-west-1", 0)
+
+	costs, err = client.GetCosts(ctx, jan, mar, []aws.GroupType{{Type: "TAG", Key: "team"}}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	assertCost(t, costs, "team$platform", 22)
+	assertCost(t, costs, "team$data", 0)
+	assertCost(t, costs, "team$", 5)
+
+	costs, err = client.GetCosts(ctx, jan, feb, []aws.GroupType{aws.GroupByService, aws.GroupByRegion}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if len(costs) != 3 {
+		t.Errorf("got %d groups, want 3: %v", len(costs), costs)
+	}
+	assertCost(t, costs, "Amazon Elastic Compute Cloud / us-east-1", 10)
+	assertCost(t, costs, "Amazon Elastic Compute Cloud / eu-west-1", 0)
+	assertCost(t, costs, "Amazon Simple Storage Service / us-east-1", 5)
+
+	expr, err := filter.Parse(`region=us-east-1 and not (tag:team=platform or service="Amazon Elastic Compute Cloud")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	costs, err = client.GetCosts(ctx, jan, feb, []aws.GroupType{aws.GroupByService}, "UnblendedCost", expr)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if len(costs) != 1 {
+		t.Errorf("got %d services, want 1: %v", len(costs), costs)
+	}
+	assertCost(t, costs, "Amazon Simple Storage Service", 5)
+
+	// Line item types are reported with Cost Explorer's RECORD_TYPE names
+	costs, err = client.GetCosts(ctx, jan, feb, []aws.GroupType{aws.GroupByRecordType}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	assertCost(t, costs, "Usage", 15)
+	assertCost(t, costs, "Savings Plan Covered Usage", 20)
+	assertCost(t, costs, "Savings Plan Negation", -20)
+}
+
+func TestGetMonthlyCosts(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "report-1.csv"), legacyCSV)
+
+	client, err := NewClient(dir)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	months, err := client.GetMonthlyCosts(context.Background(), jan, mar.AddDate(0, 1, 0), []aws.GroupType{aws.GroupByService}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetMonthlyCosts() error = %v", err)
+	}
+	if len(months) != 3 {
+		t.Fatalf("got %d months, want 3", len(months))
+	}
+	for i, want := range []time.Time{jan, feb, mar} {
+		if !months[i].Month.Equal(want) {
+			t.Errorf("months[%d].Month = %v, want %v", i, months[i].Month, want)
+		}
+	}
+	assertCost(t, months[0].Costs, "Amazon Elastic Compute Cloud", 10)
+	assertCost(t, months[0].Costs, "Amazon Simple Storage Service", 5)
+	assertCost(t, months[1].Costs, "Amazon Elastic Compute Cloud", 12)
+	if len(months[2].Costs) != 0 {
+		t.Errorf("March costs = %v, want none", months[2].Costs)
+	}
+}
+
+func TestGetCosts_UnsupportedDimension(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "report.csv"), legacyCSV)
+
+	client, err := NewClient(dir)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	_, err = client.GetCosts(context.Background(), jan, feb, []aws.GroupType{{Type: "DIMENSION", Key: "PLATFORM"}}, "UnblendedCost", nil)
+	if err == nil {
+		t.Error("expected error for unsupported dimension")
+	}
+
+	expr, _ := filter.Parse("service=A or (region=x and not account=1)")
+	expr.Or[1].And[1].Not.Dimensions.Key = "PLATFORM"
+	_, err = client.GetDailyCosts(context.Background(), jan, feb, nil, "UnblendedCost", expr)
+	if err == nil {
+		t.Error("expected error for unsupported filter dimension")
+	}
+}
+
+func TestGetDailyCosts_Grouped(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "report-1.csv"), legacyCSV)
+
+	client, err := NewClient(dir)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	days, err := client.GetDailyCosts(ctx, jan.AddDate(0, 0, 4), jan.AddDate(0, 0, 7), []aws.GroupType{aws.GroupByRegion}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetDailyCosts() error = %v", err)
+	}
+	if len(days) != 3 {
+		t.Fatalf("got %d days, want 3", len(days))
+	}
+
+	// Jan 5: us-east-1 10 + eu-west-1 20; Jan 6: eu-west-1 -20 + us-east-1 5; Jan 7: nothing
+	if days[0].Cost != 30 {
+		t.Errorf("days[0].Cost = %v, want 30", days[0].Cost)
+	}
+	assertCost(t, days[0].Groups, "us-east-1", 10)
+	assertCost(t, days[0].Groups, "eu-west-1", 20)
+	assertCost(t, days[1].Groups, "eu-west-1", -20)
+	assertCost(t, days[1].Groups, "us-east-1", 5)
+	if days[2].Groups == nil || len(days[2].Groups) != 0 {
+		t.Errorf("days[2].Groups = %v, want empty map", days[2].Groups)
+	}
+
+	_, err = client.GetDailyCosts(ctx, jan, feb, []aws.GroupType{{Type: "DIMENSION", Key: "PLATFORM"}}, "UnblendedCost", nil)
+	if err == nil {
+		t.Error("expected error for unsupported grouping")
+	}
+}
+
+func TestGetDailyCosts_GzipCUR2(t *testing.T) {
+	content := `line_item_usage_start_date,line_item_line_item_type,line_item_unblended_cost,line_item_net_unblended_cost,product,resource_tags
+2024-01-01 00:00:00,Usage,4.0,3.0,"{""product_name"": ""AWS Lambda"", ""region"": ""us-east-1""}","{""user_team"": ""platform""}"
+2024-01-03 12:00:00,Usage,6.0,,"{""product_name"": ""AWS Lambda""}",
+`
+	path := filepath.Join(t.TempDir(), "cur2.csv.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	gz.Close()
+	f.Close()
+
+	client, err := NewClient(path)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	days, err := client.GetDailyCosts(ctx, jan, jan.AddDate(0, 0, 4), nil, "NetUnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetDailyCosts() error = %v", err)
+	}
+
+	want := []float64{3, 0, 6, 0}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, day := range days {
+		if !day.Date.Equal(jan.AddDate(0, 0, i)) {
+			t.Errorf("days[%d].Date = %v, want %v", i, day.Date, jan.AddDate(0, 0, i))
+		}
+		if day.Cost != want[i] {
+			t.Errorf("days[%d].Cost = %v, want %v", i, day.Cost, want[i])
+		}
+	}
+
+	costs, err := client.GetCosts(ctx, jan, feb, []aws.GroupType{{Type: "TAG", Key: "team"}}, "UnblendedCost", filter.Service("AWS Lambda"))
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	assertCost(t, costs, "team$platform", 4)
+	assertCost(t, costs, "team$", 6)
+}
+
+type parquetRow struct {
+	UsageStartDate time.Time         `parquet:"line_item_usage_start_date,timestamp(millisecond)"`
+	LineItemType   string            `parquet:"line_item_line_item_type"`
+	UsageAccountID string            `parquet:"line_item_usage_account_id"`
+	UnblendedCost  float64           `parquet:"line_item_unblended_cost"`
+	ProductName    string            `parquet:"product_product_name"`
+	RegionCode     string            `parquet:"product_region_code"`
+	ResourceTags   map[string]string `parquet:"resource_tags"`
+}
+
+func TestGetCosts_Parquet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cur2.parquet")
+	rows := []parquetRow{
+		{UsageStartDate: jan, LineItemType: "Usage", UsageAccountID: "111", UnblendedCost: 1.25, ProductName: "Amazon RDS Service", RegionCode: "us-east-1", ResourceTags: map[string]string{"user_env": "prod"}},
+		{UsageStartDate: jan.Add(36 * time.Hour), LineItemType: "Tax", UsageAccountID: "111", UnblendedCost: 0.75, ProductName: "Amazon RDS Service", RegionCode: "us-east-1"},
+		{UsageStartDate: feb, LineItemType: "Usage", UsageAccountID: "222", UnblendedCost: 3, ProductName: "Amazon RDS Service", RegionCode: "eu-west-1", ResourceTags: map[string]string{"user_env": "dev"}},
+	}
+	if err := parquet.WriteFile(path, rows); err != nil {
+		t.Fatalf("failed to write parquet fixture: %v", err)
+	}
+
+	client, err := NewClient(path)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	costs, err := client.GetCosts(ctx, jan, mar, []aws.GroupType{{Type: "DIMENSION", Key: "RECORD_TYPE"}}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	assertCost(t, costs, "Usage", 4.25)
+	assertCost(t, costs, "Tax", 0.75)
+
+	costs, err = client.GetCosts(ctx, jan, mar, []aws.GroupType{{Type: "TAG", Key: "env"}}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	assertCost(t, costs, "env$prod", 1.25)
+	assertCost(t, costs, "env$dev", 3)
+
+	costs, err = client.GetCosts(ctx, jan, feb, []aws.GroupType{aws.GroupByAccount}, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if len(costs) != 1 {
+		t.Errorf("got %d accounts, want 1: %v", len(costs), costs)
+	}
+	assertCost(t, costs, "111", 2)
+}
+
+func TestNormalizeColumn(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"lineItem/UsageStartDate", "line_item_usage_start_date"},
+		{"lineItem/LineItemType", "line_item_line_item_type"},
+		{"reservation/ReservationARN", "reservation_reservation_arn"},
+		{"savingsPlan/SavingsPlanEffectiveCost", "savings_plan_savings_plan_effective_cost"},
+		{"product/region", "product_region"},
+		{"line_item_usage_start_date", "line_item_usage_start_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := normalizeColumn(tt.input); got != tt.want {
+				t.Errorf("normalizeColumn(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmortizedCost(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  map[string]string
+		want    float64
+		wantNet float64
+	}{
+		{
+			name:    "usage",
+			fields:  map[string]string{"line_item_line_item_type": "Usage", "line_item_unblended_cost": "10", "line_item_net_unblended_cost": "9"},
+			want:    10,
+			wantNet: 9,
+		},
+		{
+			name:    "reserved usage",
+			fields:  map[string]string{"line_item_line_item_type": "DiscountedUsage", "line_item_unblended_cost": "0", "reservation_effective_cost": "4"},
+			want:    4,
+			wantNet: 4,
+		},
+		{
+			name: "unused reservation",
+			fields: map[string]string{
+				"line_item_line_item_type":                                    "RIFee",
+				"reservation_unused_amortized_upfront_fee_for_billing_period": "1.5",
+				"reservation_unused_recurring_fee":                            "2",
+			},
+			want:    3.5,
+			wantNet: 3.5,
+		},
+		{
+			name:    "upfront reservation fee",
+			fields:  map[string]string{"line_item_line_item_type": "Fee", "line_item_unblended_cost": "1000", "reservation_reservation_arn": "arn:aws:ec2:res"},
+			want:    0,
+			wantNet: 0,
+		},
+		{
+			name:    "unused savings plan commitment",
+			fields:  map[string]string{"line_item_line_item_type": "SavingsPlanRecurringFee", "savings_plan_total_commitment_to_date": "10", "savings_plan_used_commitment": "7"},
+			want:    3,
+			wantNet: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := record{fields: tt.fields}
+			if got := rec.amortizedCost(false); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("amortizedCost(false) = %v, want %v", got, tt.want)
+			}
+			if got := rec.amortizedCost(true); math.Abs(got-tt.wantNet) > 0.001 {
+				t.Errorf("amortizedCost(true) = %v, want %v", got, tt.wantNet)
+			}
+		})
+	}
+}
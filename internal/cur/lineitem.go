@@ -0,0 +1,287 @@
+package cur
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
+)
+
+// dimensionColumns maps Cost Explorer dimensions to the CUR columns that hold them.
+// Column names are normalized (see normalizeColumn), so the same list covers
+// legacy CUR ("lineItem/UsageType") and CUR 2.0 ("line_item_usage_type") exports.
+// The first non-empty column wins.
+var dimensionColumns = map[string][]string{
+	"SERVICE":           {"product_product_name", "line_item_product_code"},
+	"USAGE_TYPE":        {"line_item_usage_type"},
+	"REGION":            {"product_region_code", "product_region"},
+	"LINKED_ACCOUNT":    {"line_item_usage_account_id"},
+	"OPERATION":         {"line_item_operation"},
+	"RECORD_TYPE":       {"line_item_line_item_type"},
+	"INSTANCE_TYPE":     {"product_instance_type"},
+	"AZ":                {"line_item_availability_zone"},
+	"LEGAL_ENTITY_NAME": {"line_item_legal_entity"},
+}
+
+// recordTypes maps CUR line item types to the RECORD_TYPE values Cost Explorer
+// reports, so charge types group and filter the same way for both sources.
+// Types not listed (Usage, Credit, Refund, Tax, DiscountedUsage, ...) already match.
+var recordTypes = map[string]string{
+	"SavingsPlanCoveredUsage": "Savings Plan Covered Usage",
+	"SavingsPlanNegation":     "Savings Plan Negation",
+	"SavingsPlanRecurringFee": "Savings Plan Recurring Fee",
+	"SavingsPlanUpfrontFee":   "Savings Plan Upfront Fee",
+	"RIFee":                   "Recurring reservation fee",
+	"EdpDiscount":             "Enterprise Discount Program Discount",
+	"BundledDiscount":         "Bundled Discount",
+	"PrivateRateDiscount":     "Private Rate Card Discount",
+	"DistributorDiscount":     "Distributor Discount",
+	"SppDiscount":             "Solution Provider Program Discount",
+}
+
+// Date layouts seen in CUR exports
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// lineItem is a single CUR row reduced to the fields costdiff queries
+type lineItem struct {
+	date    time.Time
+	dims    map[string]string
+	tags    map[string]string
+	metrics map[string]float64
+}
+
+// record is a raw CUR row with normalized column names
+type record struct {
+	fields map[string]string
+	tags   map[string]string
+}
+
+func newRecord() record {
+	return record{
+		fields: make(map[string]string),
+		tags:   make(map[string]string),
+	}
+}
+
+// set stores a raw column value, routing resource tag columns to tags
+func (r record) set(column, value string) {
+	switch {
+	case strings.HasPrefix(column, "resourceTags/"):
+		// Legacy CSV: resourceTags/user:team
+		r.tags[strings.TrimPrefix(strings.TrimPrefix(column, "resourceTags/"), "user:")] = value
+	case strings.HasPrefix(column, "resource_tags_user_"):
+		// Legacy Parquet/Athena: resource_tags_user_team
+		r.tags[strings.TrimPrefix(column, "resource_tags_user_")] = value
+	case isMapColumn(column):
+		// CUR 2.0 CSV: map columns are exported as JSON objects
+		var entries map[string]string
+		if err := json.Unmarshal([]byte(value), &entries); err == nil {
+			for k, v := range entries {
+				r.setMapEntry(column, k, v)
+			}
+		}
+	default:
+		r.fields[normalizeColumn(column)] = value
+	}
+}
+
+// setMapEntry stores an entry of a CUR 2.0 map column. resource_tags entries
+// become tags; other maps (e.g. product) are flattened to "<column>_<key>".
+func (r record) setMapEntry(column, key, value string) {
+	if column == "resource_tags" {
+		r.tags[strings.TrimPrefix(key, "user_")] = value
+		return
+	}
+
+	name := column + "_" + key
+	if _, exists := r.fields[name]; !exists {
+		r.fields[name] = value
+	}
+}
+
+// isMapColumn reports whether a column is one of the CUR 2.0 map columns
+func isMapColumn(column string) bool {
+	switch column {
+	case "resource_tags", "product", "cost_category", "discount":
+		return true
+	}
+	return false
+}
+
+// value returns the first non-empty value among the given columns
+func (r record) value(columns ...string) string {
+	for _, col := range columns {
+		if v := strings.TrimSpace(r.fields[col]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// has reports whether any of the given columns holds a non-empty value
+func (r record) has(columns ...string) bool {
+	return r.value(columns...) != ""
+}
+
+// amount parses the first non-empty value among the given columns as a float
+func (r record) amount(columns ...string) float64 {
+	v := r.value(columns...)
+	if v == "" {
+		return 0
+	}
+	amount, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+// toLineItem converts a raw record into a lineItem
+func (r record) toLineItem() (lineItem, error) {
+	raw := r.value("line_item_usage_start_date")
+	if raw == "" {
+		return lineItem{}, fmt.Errorf("missing usage start date")
+	}
+	date, err := parseDate(raw)
+	if err != nil {
+		return lineItem{}, err
+	}
+
+	dims := make(map[string]string, len(dimensionColumns))
+	for dim, columns := range dimensionColumns {
+		dims[dim] = r.value(columns...)
+	}
+	if recordType, ok := recordTypes[dims["RECORD_TYPE"]]; ok {
+		dims["RECORD_TYPE"] = recordType
+	}
+
+	return lineItem{
+		date: date,
+		dims: dims,
+		tags: r.tags,
+		metrics: map[string]float64{
+			"UnblendedCost":         r.amount("line_item_unblended_cost"),
+			"NetUnblendedCost":      r.amount("line_item_net_unblended_cost", "line_item_unblended_cost"),
+			"BlendedCost":           r.amount("line_item_blended_cost"),
+			"AmortizedCost":         r.amortizedCost(false),
+			"NetAmortizedCost":      r.amortizedCost(true),
+			"UsageQuantity":         r.amount("line_item_usage_amount"),
+			"NormalizedUsageAmount": r.amount("line_item_normalized_usage_amount"),
+		},
+	}, nil
+}
+
+// amortizedCost derives the amortized cost of a line item the way Cost Explorer does:
+// covered usage is charged at its effective rate, unused commitment is charged as
+// a fee, and upfront payments and negations are spread out and therefore zero.
+func (r record) amortizedCost(net bool) float64 {
+	// pick prefers the net_ variant of a column when computing net amortized cost
+	pick := func(column, netColumn string) float64 {
+		if net {
+			return r.amount(netColumn, column)
+		}
+		return r.amount(column)
+	}
+
+	switch r.value("line_item_line_item_type") {
+	case "SavingsPlanCoveredUsage":
+		return pick("savings_plan_savings_plan_effective_cost", "savings_plan_net_savings_plan_effective_cost")
+	case "SavingsPlanRecurringFee":
+		return r.amount("savings_plan_total_commitment_to_date") - r.amount("savings_plan_used_commitment")
+	case "SavingsPlanNegation", "SavingsPlanUpfrontFee":
+		return 0
+	case "DiscountedUsage":
+		return pick("reservation_effective_cost", "reservation_net_effective_cost")
+	case "RIFee":
+		return pick("reservation_unused_amortized_upfront_fee_for_billing_period", "reservation_net_unused_amortized_upfront_fee_for_billing_period") +
+			pick("reservation_unused_recurring_fee", "reservation_net_unused_recurring_fee")
+	case "Fee":
+		// Upfront reservation fees are amortized into DiscountedUsage and RIFee lines
+		if r.has("reservation_reservation_arn") {
+			return 0
+		}
+	}
+
+	return pick("line_item_unblended_cost", "line_item_net_unblended_cost")
+}
+
+// groupKey returns the group name for a line item, matching Cost Explorer naming:
+// tag groups are reported as "key$value" and missing dimension values as "Other".
+// Multiple group types produce a composite name.
+func (item lineItem) groupKey(groupBy []aws.GroupType) string {
+	names := make([]string, len(groupBy))
+	for i, group := range groupBy {
+		names[i] = item.groupValue(group)
+	}
+	return groupkey.Join(names)
+}
+
+// groupValue returns the line item's value for a single group type
+func (item lineItem) groupValue(group aws.GroupType) string {
+	if group.Type == "TAG" {
+		return group.Key + "$" + item.tags[group.Key]
+	}
+
+	name := item.dims[group.Key]
+	if name == "" {
+		return "Other"
+	}
+	return name
+}
+
+// Dimension returns the line item's value for a Cost Explorer dimension
+func (item lineItem) Dimension(key string) string {
+	return item.dims[key]
+}
+
+// Tag returns the line item's value for a cost allocation tag
+func (item lineItem) Tag(key string) string {
+	return item.tags[key]
+}
+
+// parseDate parses a CUR timestamp into UTC
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid usage start date %q", s)
+}
+
+// normalizeColumn converts legacy CUR column names ("lineItem/UsageStartDate")
+// to the snake_case form used by CUR 2.0 ("line_item_usage_start_date").
+func normalizeColumn(name string) string {
+	runes := []rune(strings.TrimSpace(name))
+
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case r == '/' || r == ' ' || r == '-' || r == '.':
+			b.WriteByte('_')
+		case unicode.IsUpper(r):
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteByte('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,302 @@
+package cur
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+const (
+	// Number of Parquet rows read per batch
+	parquetBatchSize = 1024
+
+	// Julian day number of 1970-01-01, used to decode INT96 timestamps
+	julianUnixEpoch = 2440588
+)
+
+// findReportFiles returns the CUR files at path, which may be a single file
+// or a directory searched recursively. Files are returned in lexical order.
+func findReportFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access CUR source: %w", err)
+	}
+
+	if !info.IsDir() {
+		if !isReportFile(path) {
+			return nil, fmt.Errorf("unsupported CUR file %s (expected .csv, .csv.gz or .parquet)", path)
+		}
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isReportFile(p) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan CUR directory: %w", err)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// isReportFile reports whether the file extension is a supported CUR format
+func isReportFile(path string) bool {
+	name := strings.ToLower(path)
+	return strings.HasSuffix(name, ".csv") ||
+		strings.HasSuffix(name, ".csv.gz") ||
+		strings.HasSuffix(name, ".parquet")
+}
+
+// readReportFile reads all line items from a single CUR file
+func readReportFile(path string, logger aws.Logger) ([]lineItem, error) {
+	if strings.HasSuffix(strings.ToLower(path), ".parquet") {
+		return readParquetFile(path, logger)
+	}
+	return readCSVFile(path, logger)
+}
+
+// readCSVFile reads line items from a CSV or gzip-compressed CSV file
+func readCSVFile(path string, logger aws.Logger) ([]lineItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make([]string, len(header))
+	copy(columns, header)
+
+	var items []lineItem
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+
+		rec := newRecord()
+		for i, value := range row {
+			if i < len(columns) {
+				rec.set(columns[i], value)
+			}
+		}
+
+		item, err := rec.toLineItem()
+		if err != nil {
+			logger.Warnf("%s:%d: %v, skipping line item", path, line, err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// readParquetFile reads line items from a Parquet file
+func readParquetFile(path string, logger aws.Logger) ([]lineItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := parquet.OpenFile(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Parquet file: %w", err)
+	}
+
+	schema := file.Schema()
+	leaves := make([]parquetLeaf, len(schema.Columns()))
+	for _, path := range schema.Columns() {
+		leaf, ok := schema.Lookup(path...)
+		if ok {
+			leaves[leaf.ColumnIndex] = parquetLeaf{path: path, node: leaf.Node}
+		}
+	}
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+
+	var items []lineItem
+	rows := make([]parquet.Row, parquetBatchSize)
+	for {
+		n, err := reader.ReadRows(rows)
+		for _, row := range rows[:n] {
+			item, convErr := parquetRecord(row, leaves).toLineItem()
+			if convErr != nil {
+				logger.Warnf("%s: %v, skipping line item", path, convErr)
+				continue
+			}
+			items = append(items, item)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Parquet rows: %w", err)
+		}
+	}
+
+	return items, nil
+}
+
+// parquetLeaf describes a leaf column of a Parquet schema
+type parquetLeaf struct {
+	path []string
+	node parquet.Node
+}
+
+// parquetRecord converts a Parquet row into a record. Flat columns are keyed by
+// name; map columns (leaf path <column>.key_value.key/value) are paired up.
+func parquetRecord(row parquet.Row, leaves []parquetLeaf) record {
+	rec := newRecord()
+	mapKeys := make(map[string][]string)
+	mapValues := make(map[string][]string)
+
+	for _, v := range row {
+		col := v.Column()
+		if col < 0 || col >= len(leaves) || v.IsNull() {
+			continue
+		}
+		leaf := leaves[col]
+		if len(leaf.path) == 0 {
+			continue
+		}
+
+		value := parquetValueString(v, leaf.node)
+		if len(leaf.path) == 1 {
+			rec.set(leaf.path[0], value)
+			continue
+		}
+
+		column := leaf.path[0]
+		switch leaf.path[len(leaf.path)-1] {
+		case "key":
+			mapKeys[column] = append(mapKeys[column], value)
+		case "value":
+			mapValues[column] = append(mapValues[column], value)
+		}
+	}
+
+	for column, keys := range mapKeys {
+		values := mapValues[column]
+		for i, key := range keys {
+			if i < len(values) {
+				rec.setMapEntry(column, key, values[i])
+			}
+		}
+	}
+
+	return rec
+}
+
+// parquetValueString renders a Parquet value as the string CUR CSV exports would contain
+func parquetValueString(v parquet.Value, node parquet.Node) string {
+	logical := node.Type().LogicalType()
+
+	switch v.Kind() {
+	case parquet.Int64:
+		if logical != nil && logical.Timestamp != nil {
+			ts := logical.Timestamp
+			switch {
+			case ts.Unit.Millis != nil:
+				return time.UnixMilli(v.Int64()).UTC().Format(time.RFC3339Nano)
+			case ts.Unit.Micros != nil:
+				return time.UnixMicro(v.Int64()).UTC().Format(time.RFC3339Nano)
+			default:
+				return time.Unix(0, v.Int64()).UTC().Format(time.RFC3339Nano)
+			}
+		}
+		if logical != nil && logical.Decimal != nil {
+			return formatDecimal(big.NewInt(v.Int64()), logical.Decimal.Scale)
+		}
+	case parquet.Int32:
+		if logical != nil && logical.Decimal != nil {
+			return formatDecimal(big.NewInt(int64(v.Int32())), logical.Decimal.Scale)
+		}
+		if logical != nil && logical.Date != nil {
+			return time.Unix(int64(v.Int32())*86400, 0).UTC().Format("2006-01-02")
+		}
+	case parquet.Int96:
+		// Legacy timestamps: nanoseconds within the day followed by the Julian day number
+		i96 := v.Int96()
+		nanos := int64(i96[1])<<32 | int64(i96[0])
+		days := int64(i96[2]) - julianUnixEpoch
+		return time.Unix(days*86400, nanos).UTC().Format(time.RFC3339Nano)
+	case parquet.Float:
+		return strconv.FormatFloat(float64(v.Float()), 'g', -1, 32)
+	case parquet.Double:
+		return strconv.FormatFloat(v.Double(), 'g', -1, 64)
+	case parquet.FixedLenByteArray, parquet.ByteArray:
+		if logical != nil && logical.Decimal != nil {
+			return formatDecimal(decimalFromBytes(v.ByteArray()), logical.Decimal.Scale)
+		}
+	}
+
+	return v.String()
+}
+
+// decimalFromBytes decodes a big-endian two's complement integer
+func decimalFromBytes(b []byte) *big.Int {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return n
+}
+
+// formatDecimal formats an unscaled decimal value with the given scale
+func formatDecimal(unscaled *big.Int, scale int32) string {
+	f := new(big.Float).SetInt(unscaled)
+	f.Quo(f, new(big.Float).SetFloat64(math.Pow10(int(scale))))
+	value, _ := f.Float64()
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"math"
+	"sort"
+)
+
+// Anomaly detection settings. Each day is scored against the days before it with a
+// robust z-score: its distance from the baseline median in units of the scaled
+// median absolute deviation (MAD).
+const (
+	// AnomalyWindow is how many preceding days form a day's baseline
+	AnomalyWindow = 28
+	// AnomalyMinBaseline is the fewest preceding days needed to score a day
+	AnomalyMinBaseline = 7
+	// AnomalyMinWeekdays is how many earlier days with the same weekday are needed
+	// to use them as the baseline instead of all preceding days
+	AnomalyMinWeekdays = 3
+	// AnomalyThreshold is the robust z-score at which a day is flagged
+	AnomalyThreshold = 3.5
+	// AnomalyMinSpread is the smallest spread assumed, as a fraction of the median,
+	// so a perfectly flat baseline does not flag every cent of variation
+	AnomalyMinSpread = 0.05
+)
+
+// madScale converts a MAD to a standard deviation estimate for normal data
+const madScale = 1.4826
+
+// Severity describes how far an anomalous day is from its baseline
+type Severity string
+
+// Severity levels by absolute robust z-score
+const (
+	SeverityLow    Severity = "low"    // at least AnomalyThreshold
+	SeverityMedium Severity = "medium" // at least 5
+	SeverityHigh   Severity = "high"   // at least 8
+)
+
+// SeverityFor returns the severity for a robust z-score, or "" below AnomalyThreshold
+func SeverityFor(score float64) Severity {
+	switch score = math.Abs(score); {
+	case score >= 8:
+		return SeverityHigh
+	case score >= 5:
+		return SeverityMedium
+	case score >= AnomalyThreshold:
+		return SeverityLow
+	}
+	return ""
+}
+
+// DetectAnomalies scores every day against up to AnomalyWindow preceding days and
+// flags days whose cost deviates by AnomalyThreshold or more. To account for weekly
+// seasonality, days are compared with earlier days of the same weekday when there
+// are at least AnomalyMinWeekdays of them. Days with fewer than AnomalyMinBaseline
+// preceding days are left unscored. days must be in date order.
+func DetectAnomalies(days []DayItem) {
+	for i := range days {
+		day := &days[i]
+		baseline := days[max(0, i-AnomalyWindow):i]
+		if len(baseline) < AnomalyMinBaseline {
+			continue
+		}
+
+		var all, sameWeekday []float64
+		for _, prev := range baseline {
+			all = append(all, prev.Cost)
+			if prev.Date.Weekday() == day.Date.Weekday() {
+				sameWeekday = append(sameWeekday, prev.Cost)
+			}
+		}
+
+		reference := all
+		if len(sameWeekday) >= AnomalyMinWeekdays {
+			reference = sameWeekday
+		}
+
+		expected := median(reference)
+		deviations := make([]float64, len(reference))
+		for j, cost := range reference {
+			deviations[j] = math.Abs(cost - expected)
+		}
+		spread := max(madScale*median(deviations), AnomalyMinSpread*math.Abs(expected))
+
+		day.Scored = true
+		day.Expected = expected
+		if spread > 0 {
+			day.Score = (day.Cost - expected) / spread
+		}
+		day.Severity = SeverityFor(day.Score)
+		day.Anomaly = day.Severity != ""
+	}
+}
+
+// CountAnomalies returns the number of days flagged as anomalous
+func CountAnomalies(days []DayItem) int {
+	var count int
+	for _, day := range days {
+		if day.Anomaly {
+			count++
+		}
+	}
+	return count
+}
+
+// median returns the median of values without modifying them
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
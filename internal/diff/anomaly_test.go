@@ -0,0 +1,116 @@
+package diff
+
+import (
+	"testing"
+	"time"
+)
+
+// testDays returns daily costs starting on Monday, Jan 1 2024
+func testDays(costs ...float64) []DayItem {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := make([]DayItem, len(costs))
+	for i, cost := range costs {
+		days[i] = DayItem{Date: start.AddDate(0, 0, i), Cost: cost}
+	}
+	return days
+}
+
+func TestDetectAnomalies_Spike(t *testing.T) {
+	days := testDays(100, 102, 98, 101, 99, 100, 103, 97, 100, 250)
+	DetectAnomalies(days)
+
+	for i := 0; i < AnomalyMinBaseline; i++ {
+		if days[i].Scored {
+			t.Errorf("day %d should not be scored without enough history", i)
+		}
+	}
+	for i := AnomalyMinBaseline; i < 9; i++ {
+		if !days[i].Scored || days[i].Anomaly {
+			t.Errorf("day %d: scored=%t anomaly=%t, want scored and normal", i, days[i].Scored, days[i].Anomaly)
+		}
+	}
+
+	spike := days[9]
+	if !spike.Anomaly || spike.Severity != SeverityHigh || spike.Score <= 0 {
+		t.Errorf("spike = %+v, want high positive anomaly", spike)
+	}
+	if spike.Expected != 100 {
+		t.Errorf("Expected = %v, want 100", spike.Expected)
+	}
+	if CountAnomalies(days) != 1 {
+		t.Errorf("CountAnomalies() = %d, want 1", CountAnomalies(days))
+	}
+}
+
+func TestDetectAnomalies_Drop(t *testing.T) {
+	days := testDays(100, 100, 100, 100, 100, 100, 100, 80)
+	DetectAnomalies(days)
+
+	drop := days[7]
+	if !drop.Anomaly || drop.Score >= 0 {
+		t.Errorf("drop = %+v, want negative anomaly", drop)
+	}
+}
+
+func TestDetectAnomalies_WeekdaySeasonality(t *testing.T) {
+	// Weekends are much cheaper than weekdays; a normal weekend is not an anomaly,
+	// but a weekend costing as much as a weekday is
+	var costs []float64
+	for week := 0; week < 4; week++ {
+		costs = append(costs, 100, 100, 100, 100, 100, 20, 20)
+	}
+	costs = append(costs, 100, 100, 100, 100, 100, 20, 100)
+	days := testDays(costs...)
+	DetectAnomalies(days)
+
+	saturday, sunday := days[len(days)-2], days[len(days)-1]
+	if saturday.Anomaly || saturday.Expected != 20 {
+		t.Errorf("saturday = %+v, want normal with expected 20", saturday)
+	}
+	if !sunday.Anomaly || sunday.Expected != 20 {
+		t.Errorf("sunday = %+v, want anomaly with expected 20", sunday)
+	}
+}
+
+func TestDetectAnomalies_MinSpread(t *testing.T) {
+	// A flat baseline tolerates small changes instead of flagging every cent
+	days := testDays(100, 100, 100, 100, 100, 100, 100, 110)
+	DetectAnomalies(days)
+
+	if days[7].Anomaly {
+		t.Errorf("10%% change on a flat baseline flagged: %+v", days[7])
+	}
+}
+
+func TestSeverityFor(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  Severity
+	}{
+		{0, ""},
+		{3.4, ""},
+		{3.5, SeverityLow},
+		{-4, SeverityLow},
+		{5, SeverityMedium},
+		{-12, SeverityHigh},
+	}
+
+	for _, tt := range tests {
+		if got := SeverityFor(tt.score); got != tt.want {
+			t.Errorf("SeverityFor(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestMedian(t *testing.T) {
+	values := []float64{5, 1, 3, 2}
+	if got := median(values); got != 2.5 {
+		t.Errorf("median() = %v, want 2.5", got)
+	}
+	if values[0] != 5 {
+		t.Error("median() should not reorder its input")
+	}
+	if got := median([]float64{4, 1, 9}); got != 4 {
+		t.Errorf("median() = %v, want 4", got)
+	}
+}
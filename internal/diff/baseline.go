@@ -0,0 +1,61 @@
+package diff
+
+// YearsEarlier returns the same period the given number of years earlier: the
+// same fiscal periods of an earlier fiscal year when p lines up with fiscal
+// calendar c, the same weekdays 52 weeks per year earlier for whole
+// weeks, and the same dates otherwise
+func (p Period) YearsEarlier(years int, c *FiscalCalendar) Period {
+	if c != nil {
+		if shifted, ok := c.Shift(p, -FiscalPeriods*years); ok {
+			return shifted
+		}
+	}
+	if p.wholeMonths() == 0 && p.Days()%7 == 0 {
+		return Period{Start: p.Start.AddDate(0, 0, -364*years), End: p.End.AddDate(0, 0, -364*years)}
+	}
+	return Period{Start: p.Start.AddDate(-years, 0, 0), End: p.End.AddDate(-years, 0, 0)}
+}
+
+// Preceding returns the n periods of the same kind just before p, oldest
+// first: runs of as many fiscal periods when p lines up with fiscal calendar
+// c, runs of as many months for whole months, and spans of as many
+// days otherwise
+func (p Period) Preceding(n int, c *FiscalCalendar) []Period {
+	periods := make([]Period, n)
+	for i := range periods {
+		periods[i] = p.before(n-i, c)
+	}
+	return periods
+}
+
+// before returns the period of the same kind k steps before p
+func (p Period) before(k int, c *FiscalCalendar) Period {
+	if c != nil {
+		if count := c.count(p); count > 0 {
+			shifted, _ := c.Shift(p, -count*k)
+			return shifted
+		}
+	}
+	if months := p.wholeMonths(); months > 0 {
+		return Period{Start: p.Start.AddDate(0, -months*k, 0), End: p.End.AddDate(0, -months*k, 0)}
+	}
+	days := p.Days()
+	return Period{Start: p.Start.AddDate(0, 0, -days*k), End: p.End.AddDate(0, 0, -days*k)}
+}
+
+// Span returns the period from the start of the first period to the end of the last
+func Span(periods []Period) Period {
+	return Period{Start: periods[0].Start, End: periods[len(periods)-1].End}
+}
+
+// Average returns the mean cost of each key across the cost maps, counting a
+// key missing from a map as zero
+func Average(costs []map[string]float64) map[string]float64 {
+	result := make(map[string]float64)
+	for _, c := range costs {
+		for key, cost := range c {
+			result[key] += cost / float64(len(costs))
+		}
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package diff
+
+import "testing"
+
+func TestPeriod_YearsEarlier(t *testing.T) {
+	tests := []struct {
+		name   string
+		period Period
+		years  int
+		want   Period
+	}{
+		{"month", Period{day(2024, 10, 1), day(2024, 11, 1)}, 1, Period{day(2023, 10, 1), day(2023, 11, 1)}},
+		{"quarter two years back", Period{day(2024, 7, 1), day(2024, 10, 1)}, 2, Period{day(2022, 7, 1), day(2022, 10, 1)}},
+		{"ISO week keeps weekdays", Period{day(2024, 1, 29), day(2024, 2, 5)}, 1, Period{day(2023, 1, 30), day(2023, 2, 6)}},
+		{"day range", Period{day(2024, 10, 1), day(2024, 10, 16)}, 1, Period{day(2023, 10, 1), day(2023, 10, 16)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.period.YearsEarlier(tt.years, nil); got != tt.want {
+				t.Errorf("YearsEarlier() = %s..%s, want %s..%s",
+					got.Start.Format("2006-01-02"), got.End.Format("2006-01-02"),
+					tt.want.Start.Format("2006-01-02"), tt.want.End.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestPeriod_Preceding(t *testing.T) {
+	tests := []struct {
+		name   string
+		period Period
+		want   []string // labels, oldest first
+	}{
+		{"month", Period{day(2024, 2, 1), day(2024, 3, 1)}, []string{"Nov 2023", "Dec 2023", "Jan 2024"}},
+		{"quarter", Period{day(2024, 7, 1), day(2024, 10, 1)}, []string{"Q4 2023", "Q1 2024", "Q2 2024"}},
+		{"days", Period{day(2024, 10, 10), day(2024, 10, 20)}, []string{"Sep 10 - Sep 19, 2024", "Sep 20 - Sep 29, 2024", "Sep 30 - Oct 9, 2024"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.period.Preceding(3, nil)
+			for i, p := range got {
+				if p.Label(nil) != tt.want[i] {
+					t.Errorf("Preceding()[%d] = %s, want %s", i, p.Label(nil), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPeriod_BaselineFiscal(t *testing.T) {
+	p3 := retail.FiscalPeriod(2025, 3)
+	if got := p3.YearsEarlier(1, retail); got != retail.FiscalPeriod(2024, 3) {
+		t.Errorf("YearsEarlier() = %s, want FY24-P03", got.Label(retail))
+	}
+
+	// Fiscal periods of different lengths, across the year boundary
+	var labels []string
+	for _, p := range retail.FiscalPeriod(2025, 2).Preceding(3, retail) {
+		labels = append(labels, p.Label(retail))
+	}
+	if want := []string{"FY24-P11", "FY24-P12", "FY25-P01"}; labels[0] != want[0] || labels[1] != want[1] || labels[2] != want[2] {
+		t.Errorf("Preceding() = %v, want %v", labels, want)
+	}
+
+	q2 := retail.Quarter(2025, 2)
+	if got := q2.Preceding(1, retail)[0].Label(retail); got != "FY25-Q1" {
+		t.Errorf("Preceding() of a quarter = %s, want FY25-Q1", got)
+	}
+}
+
+func TestAverage(t *testing.T) {
+	got := Average([]map[string]float64{
+		{"EC2": 100, "S3": 30},
+		{"EC2": 200},
+		{"EC2": 300, "Lambda": 6},
+	})
+
+	want := map[string]float64{"EC2": 200, "S3": 10, "Lambda": 2}
+	for key, cost := range want {
+		if got[key] != cost {
+			t.Errorf("Average()[%s] = %v, want %v", key, got[key], cost)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Average() = %v, want %v", got, want)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	span := Span([]Period{{day(2024, 7, 1), day(2024, 8, 1)}, {day(2024, 9, 1), day(2024, 10, 1)}})
+	if !span.Start.Equal(day(2024, 7, 1)) || !span.End.Equal(day(2024, 10, 1)) {
+		t.Errorf("Span() = %v", span)
+	}
+}
@@ -0,0 +1,191 @@
+package diff
+
+import (
+	"sort"
+	"time"
+)
+
+// Budgets are the budgets that apply to one period, by group key
+type Budgets struct {
+	Items map[string]float64
+	Total *float64 // overall budget; nil when there is none
+}
+
+// ElapsedDays returns the number of complete days of the period before now
+func ElapsedDays(p Period, now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch {
+	case !today.After(p.Start):
+		return 0
+	case !today.Before(p.End):
+		return p.Days()
+	}
+	return int(today.Sub(p.Start).Hours() / 24)
+}
+
+// ProjectionFactor returns what spend so far is multiplied by to project it to
+// the end of the period at the current daily run rate. Periods that are over,
+// or have no complete day yet, are not projected.
+func ProjectionFactor(p Period, now time.Time) float64 {
+	elapsed := ElapsedDays(p, now)
+	if elapsed == 0 || elapsed >= p.Days() {
+		return 1
+	}
+	return float64(p.Days()) / float64(elapsed)
+}
+
+// NewBudgetStatus compares actual and projected spend with a budget
+func NewBudgetStatus(budget, actual, projected float64) BudgetStatus {
+	status := BudgetStatus{
+		Budget:    budget,
+		Actual:    actual,
+		Projected: projected,
+		Variance:  projected - budget,
+		Status:    BudgetOK,
+	}
+	if budget > 0 {
+		status.VariancePct = (status.Variance / budget) * 100
+	}
+
+	switch {
+	case actual > budget:
+		status.Status = BudgetOver
+	case projected > budget:
+		status.Status = BudgetAtRisk
+	}
+	return status
+}
+
+// budgetFor returns the budget status of a group's spend, or nil when it has no budget
+func budgetFor(budgets map[string]float64, name string, actual, factor float64) *BudgetStatus {
+	budget, ok := budgets[name]
+	if !ok {
+		return nil
+	}
+	status := NewBudgetStatus(budget, actual, actual*factor)
+	return &status
+}
+
+// totalBudget returns the status of total spend against the overall budget, or nil when there is none
+func totalBudget(budgets Budgets, actual, factor float64) *BudgetStatus {
+	if budgets.Total == nil {
+		return nil
+	}
+	status := NewBudgetStatus(*budgets.Total, actual, actual*factor)
+	return &status
+}
+
+// SetBudgets compares each item's to cost, and the to total, with its budget.
+// Spend so far is projected to the end of the to period by factor.
+func (r *Result) SetBudgets(budgets Budgets, factor float64) {
+	for i := range r.Items {
+		r.Items[i].Budget = budgetFor(budgets.Items, r.Items[i].Name, r.Items[i].ToCost, factor)
+	}
+	r.Budget = totalBudget(budgets, r.ToTotal, factor)
+}
+
+// SetBudgets compares each item's cost, and the total, with its budget.
+// Spend so far is projected to the end of the period by factor.
+func (r *TopResult) SetBudgets(budgets Budgets, factor float64) {
+	for i := range r.Items {
+		r.Items[i].Budget = budgetFor(budgets.Items, r.Items[i].Name, r.Items[i].Cost, factor)
+	}
+	r.Budget = totalBudget(budgets, r.Total, factor)
+}
+
+// HasBudgets reports whether the result was compared with budgets
+func (r *Result) HasBudgets() bool {
+	if r.Budget != nil {
+		return true
+	}
+	for _, item := range r.Items {
+		if item.Budget != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBudgets reports whether the result was compared with budgets
+func (r *TopResult) HasBudgets() bool {
+	if r.Budget != nil {
+		return true
+	}
+	for _, item := range r.Items {
+		if item.Budget != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sumBudgets adds up budget statuses, skipping nil ones. It returns nil when all are nil.
+func sumBudgets(statuses []*BudgetStatus) *BudgetStatus {
+	var budget, actual, projected float64
+	var found bool
+	for _, status := range statuses {
+		if status == nil {
+			continue
+		}
+		found = true
+		budget += status.Budget
+		actual += status.Actual
+		projected += status.Projected
+	}
+	if !found {
+		return nil
+	}
+	sum := NewBudgetStatus(budget, actual, projected)
+	return &sum
+}
+
+// CompareBudgets compares the costs of each group in a period with its budget.
+// Every budgeted group is listed, largest overrun first, followed by the groups
+// that have spend but no budget, largest first. Without an overall budget, the
+// total is compared with the sum of the group budgets, so unbudgeted spend
+// counts against it.
+func CompareBudgets(costs map[string]float64, budgets Budgets, period Period, factor float64) *BudgetResult {
+	var budgeted, unbudgeted []BudgetItem
+	var totalActual, sumBudget float64
+
+	for name, budget := range budgets.Items {
+		actual := costs[name]
+		budgeted = append(budgeted, BudgetItem{Name: name, BudgetStatus: NewBudgetStatus(budget, actual, actual*factor)})
+		sumBudget += budget
+	}
+	for name, actual := range costs {
+		totalActual += actual
+		if _, ok := budgets.Items[name]; ok || actual == 0 {
+			continue
+		}
+		unbudgeted = append(unbudgeted, BudgetItem{Name: name, BudgetStatus: BudgetStatus{
+			Actual:    actual,
+			Projected: actual * factor,
+			Variance:  actual * factor,
+			Status:    BudgetUnbudgeted,
+		}})
+	}
+
+	sort.Slice(budgeted, func(i, j int) bool {
+		if budgeted[i].Variance != budgeted[j].Variance {
+			return budgeted[i].Variance > budgeted[j].Variance
+		}
+		return budgeted[i].Name < budgeted[j].Name
+	})
+	sort.Slice(unbudgeted, func(i, j int) bool {
+		if unbudgeted[i].Actual != unbudgeted[j].Actual {
+			return unbudgeted[i].Actual > unbudgeted[j].Actual
+		}
+		return unbudgeted[i].Name < unbudgeted[j].Name
+	})
+
+	if budgets.Total != nil {
+		sumBudget = *budgets.Total
+	}
+
+	return &BudgetResult{
+		Period: period,
+		Total:  NewBudgetStatus(sumBudget, totalActual, totalActual*factor),
+		Items:  append(budgeted, unbudgeted...),
+	}
+}
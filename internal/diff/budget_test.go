@@ -0,0 +1,168 @@
+package diff
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestElapsedDays(t *testing.T) {
+	oct := Period{
+		Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		wantElapsed int
+		wantFactor  float64
+	}{
+		{"before the period", time.Date(2024, 9, 20, 12, 0, 0, 0, time.UTC), 0, 1},
+		{"first day", time.Date(2024, 10, 1, 18, 0, 0, 0, time.UTC), 0, 1},
+		{"tenth day", time.Date(2024, 10, 11, 9, 0, 0, 0, time.UTC), 10, 3.1},
+		{"last day", time.Date(2024, 10, 31, 9, 0, 0, 0, time.UTC), 30, 31.0 / 30},
+		{"after the period", time.Date(2024, 11, 5, 0, 0, 0, 0, time.UTC), 31, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ElapsedDays(oct, tt.now); got != tt.wantElapsed {
+				t.Errorf("ElapsedDays() = %d, want %d", got, tt.wantElapsed)
+			}
+			if got := ProjectionFactor(oct, tt.now); math.Abs(got-tt.wantFactor) > 0.001 {
+				t.Errorf("ProjectionFactor() = %v, want %v", got, tt.wantFactor)
+			}
+		})
+	}
+}
+
+func TestNewBudgetStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		budget       float64
+		actual       float64
+		projected    float64
+		wantVariance float64
+		wantPct      float64
+		wantStatus   BudgetState
+	}{
+		{"under", 1000, 300, 900, -100, -10, BudgetOK},
+		{"exactly on budget", 1000, 500, 1000, 0, 0, BudgetOK},
+		{"projected over", 1000, 600, 1200, 200, 20, BudgetAtRisk},
+		{"already over", 1000, 1100, 1500, 500, 50, BudgetOver},
+		{"zero budget with spend", 0, 10, 10, 10, 0, BudgetOver},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBudgetStatus(tt.budget, tt.actual, tt.projected)
+			if math.Abs(got.Variance-tt.wantVariance) > 0.001 || math.Abs(got.VariancePct-tt.wantPct) > 0.001 || got.Status != tt.wantStatus {
+				t.Errorf("NewBudgetStatus() = %+v, want variance %v (%v%%) and status %s",
+					got, tt.wantVariance, tt.wantPct, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestResult_SetBudgets(t *testing.T) {
+	total := 500.0
+	result := &Result{
+		ToTotal: 300,
+		Items: []Item{
+			{Name: "EC2", ToCost: 200},
+			{Name: "S3", ToCost: 40},
+			{Name: "Lambda", ToCost: 60},
+		},
+	}
+	result.SetBudgets(Budgets{Items: map[string]float64{"EC2": 300, "S3": 100}, Total: &total}, 2)
+
+	if b := result.Items[0].Budget; b == nil || b.Projected != 400 || b.Status != BudgetAtRisk {
+		t.Errorf("EC2 budget = %+v, want projected 400 and at risk", b)
+	}
+	if b := result.Items[1].Budget; b == nil || b.Status != BudgetOK {
+		t.Errorf("S3 budget = %+v, want ok", b)
+	}
+	if result.Items[2].Budget != nil {
+		t.Errorf("Lambda has no budget, got %+v", result.Items[2].Budget)
+	}
+	if b := result.Budget; b == nil || b.Projected != 600 || b.Variance != 100 {
+		t.Errorf("total budget = %+v, want projected 600 and variance 100", b)
+	}
+	if !result.HasBudgets() {
+		t.Error("HasBudgets() = false, want true")
+	}
+
+	// Budgets of items rolled into Other are summed
+	other, _ := OtherItem(result.Items, result.Items[:1])
+	if other.Budget == nil || other.Budget.Budget != 100 || other.Budget.Actual != 40 {
+		t.Errorf("Other budget = %+v, want the S3 budget only", other.Budget)
+	}
+}
+
+func TestTopResult_SetBudgets(t *testing.T) {
+	result := &TopResult{Total: 100, Items: []TopItem{{Name: "EC2", Cost: 100}}}
+	result.SetBudgets(Budgets{Items: map[string]float64{"EC2": 50}}, 1)
+
+	if b := result.Items[0].Budget; b == nil || b.Status != BudgetOver {
+		t.Errorf("EC2 budget = %+v, want over", b)
+	}
+	if result.Budget != nil {
+		t.Errorf("total budget = %+v, want nil without an overall budget", result.Budget)
+	}
+
+	empty := &TopResult{Items: []TopItem{{Name: "EC2", Cost: 100}}}
+	if empty.HasBudgets() {
+		t.Error("HasBudgets() = true without budgets")
+	}
+}
+
+func TestCompareBudgets(t *testing.T) {
+	costs := map[string]float64{
+		"EC2":    500,
+		"S3":     100,
+		"Lambda": 50,
+		"SQS":    0,
+	}
+	budgets := Budgets{Items: map[string]float64{
+		"EC2":      800,
+		"S3":       300,
+		"DynamoDB": 100,
+	}}
+	period := Period{
+		Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	result := CompareBudgets(costs, budgets, period, 2)
+
+	// Budgeted items by variance, then unbudgeted spend; SQS has none
+	want := []struct {
+		name   string
+		status BudgetState
+	}{
+		{"EC2", BudgetAtRisk},
+		{"DynamoDB", BudgetOK},
+		{"S3", BudgetOK},
+		{"Lambda", BudgetUnbudgeted},
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("items = %+v, want %d", result.Items, len(want))
+	}
+	for i, w := range want {
+		if result.Items[i].Name != w.name || result.Items[i].Status != w.status {
+			t.Errorf("item %d = %s (%s), want %s (%s)", i, result.Items[i].Name, result.Items[i].Status, w.name, w.status)
+		}
+	}
+
+	// Without an overall budget, all spend counts against the sum of the budgets
+	if result.Total.Budget != 1200 || result.Total.Actual != 650 || result.Total.Projected != 1300 || result.Total.Status != BudgetAtRisk {
+		t.Errorf("total = %+v, want budget 1200, actual 650, projected 1300, at risk", result.Total)
+	}
+
+	overall := 2000.0
+	budgets.Total = &overall
+	if result := CompareBudgets(costs, budgets, period, 2); result.Total.Budget != 2000 || result.Total.Status != BudgetOK {
+		t.Errorf("total with overall budget = %+v, want budget 2000 and ok", result.Total)
+	}
+}
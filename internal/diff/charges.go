@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
+)
+
+// SplitCharges separates costs grouped by item and charge type, where the charge
+// type is the last part of each composite key, into per-item totals and per-item
+// costs by charge type.
+func SplitCharges(costs map[string]float64) (map[string]float64, map[string]map[string]float64) {
+	totals := make(map[string]float64)
+	charges := make(map[string]map[string]float64)
+
+	for key, cost := range costs {
+		name, chargeType := key, ""
+		if i := strings.LastIndex(key, groupkey.Separator); i >= 0 {
+			name, chargeType = key[:i], key[i+len(groupkey.Separator):]
+		}
+
+		totals[name] += cost
+		if charges[name] == nil {
+			charges[name] = make(map[string]float64)
+		}
+		charges[name][chargeType] += cost
+	}
+
+	return totals, charges
+}
+
+// SetCharges attaches the per-charge-type breakdown to each item and records
+// which charge type drove the item's change
+func (r *Result) SetCharges(fromCharges, toCharges map[string]map[string]float64) {
+	for i := range r.Items {
+		item := &r.Items[i]
+		from, to := fromCharges[item.Name], toCharges[item.Name]
+
+		chargeTypes := make(map[string]bool)
+		for chargeType := range from {
+			chargeTypes[chargeType] = true
+		}
+		for chargeType := range to {
+			chargeTypes[chargeType] = true
+		}
+
+		item.Charges = make([]ChargeDiff, 0, len(chargeTypes))
+		for chargeType := range chargeTypes {
+			item.Charges = append(item.Charges, ChargeDiff{
+				Type:     chargeType,
+				FromCost: from[chargeType],
+				ToCost:   to[chargeType],
+				Diff:     to[chargeType] - from[chargeType],
+			})
+		}
+
+		sortCharges(item.Charges)
+		item.Driver = driver(item.Charges)
+	}
+}
+
+// sortCharges orders charges largest change first, ties broken by name for stable output
+func sortCharges(charges []ChargeDiff) {
+	sort.Slice(charges, func(a, b int) bool {
+		da, db := math.Abs(charges[a].Diff), math.Abs(charges[b].Diff)
+		if da != db {
+			return da > db
+		}
+		return charges[a].Type < charges[b].Type
+	})
+}
+
+// driver returns the charge type with the largest change in sorted charges,
+// or "" when nothing changed
+func driver(charges []ChargeDiff) string {
+	if len(charges) > 0 && charges[0].Diff != 0 {
+		return charges[0].Type
+	}
+	return ""
+}
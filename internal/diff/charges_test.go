@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"testing"
+)
+
+func TestSplitCharges(t *testing.T) {
+	costs := map[string]float64{
+		"Amazon EC2 / Usage":   100,
+		"Amazon EC2 / Credit":  -30,
+		"Amazon S3 / Usage":    10,
+		"Amazon EC2 / Tax / x": 1, // only the last part is the charge type
+	}
+
+	totals, charges := SplitCharges(costs)
+
+	if totals["Amazon EC2"] != 70 {
+		t.Errorf("totals[Amazon EC2] = %v, want 70", totals["Amazon EC2"])
+	}
+	if charges["Amazon EC2"]["Credit"] != -30 {
+		t.Errorf("charges[Amazon EC2][Credit] = %v, want -30", charges["Amazon EC2"]["Credit"])
+	}
+	if charges["Amazon EC2 / Tax"]["x"] != 1 {
+		t.Errorf("charges[Amazon EC2 / Tax][x] = %v, want 1", charges["Amazon EC2 / Tax"]["x"])
+	}
+	if totals["Amazon S3"] != 10 {
+		t.Errorf("totals[Amazon S3] = %v, want 10", totals["Amazon S3"])
+	}
+}
+
+func TestResult_SetCharges(t *testing.T) {
+	fromTotals, fromCharges := SplitCharges(map[string]float64{
+		"EC2 / Usage":  100,
+		"EC2 / Credit": -50,
+		"S3 / Usage":   20,
+	})
+	toTotals, toCharges := SplitCharges(map[string]float64{
+		"EC2 / Usage": 105,
+		"EC2 / Tax":   5,
+		"S3 / Usage":  20,
+	})
+
+	result := Compare(fromTotals, toTotals, Period{}, Period{})
+	result.SetCharges(fromCharges, toCharges)
+
+	if !result.HasCharges() {
+		t.Fatal("HasCharges() = false, want true")
+	}
+
+	ec2 := result.Items[0]
+	if ec2.Name != "EC2" || ec2.Diff != 60 {
+		t.Fatalf("first item = %s (%v), want EC2 (60)", ec2.Name, ec2.Diff)
+	}
+	if ec2.Driver != "Credit" {
+		t.Errorf("Driver = %q, want Credit (expired credit drove the change)", ec2.Driver)
+	}
+	if len(ec2.Charges) != 3 {
+		t.Fatalf("got %d charges, want 3", len(ec2.Charges))
+	}
+	want := []ChargeDiff{
+		{Type: "Credit", FromCost: -50, ToCost: 0, Diff: 50},
+		{Type: "Tax", FromCost: 0, ToCost: 5, Diff: 5},
+		{Type: "Usage", FromCost: 100, ToCost: 105, Diff: 5},
+	}
+	for i, charge := range want {
+		if ec2.Charges[i] != charge {
+			t.Errorf("Charges[%d] = %+v, want %+v", i, ec2.Charges[i], charge)
+		}
+	}
+
+	s3 := result.Items[1]
+	if s3.Driver != "" {
+		t.Errorf("unchanged item Driver = %q, want empty", s3.Driver)
+	}
+}
@@ -61,6 +61,42 @@ func Compare(fromCosts, toCosts map[string]float64, fromPeriod, toPeriod Period)
 		return math.Abs(result.Items[i].Diff) > math.Abs(result.Items[j].Diff)
 	})
 
+	result.SetContributions()
+
+	return result
+}
+
+// PerDay divides each cost by the number of days in the period, giving daily run rates
+func PerDay(costs map[string]float64, period Period) map[string]float64 {
+	days := period.Days()
+	if days <= 0 {
+		return costs
+	}
+
+	rates := make(map[string]float64, len(costs))
+	for name, cost := range costs {
+		rates[name] = cost / float64(days)
+	}
+	return rates
+}
+
+// CompareForecast compares each projected month against the baseline month's actual total
+func CompareForecast(baseline Period, baselineTotal float64, months []ForecastMonth, interval int) *ForecastResult {
+	result := &ForecastResult{
+		Baseline:      baseline,
+		BaselineTotal: baselineTotal,
+		Interval:      interval,
+		Months:        months,
+	}
+
+	for i := range result.Months {
+		month := &result.Months[i]
+		month.Diff = month.Total - baselineTotal
+		if baselineTotal > 0 {
+			month.DiffPct = (month.Diff / baselineTotal) * 100
+		}
+	}
+
 	return result
 }
 
@@ -232,3 +232,50 @@ func TestSortByDiffPercent(t *testing.T) {
 		t.Errorf("Unexpected order: %s, %s, %s", items[0].Name, items[1].Name, items[2].Name)
 	}
 }
+
+func TestPerDay(t *testing.T) {
+	feb := Period{
+		Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	rates := PerDay(map[string]float64{"EC2": 290, "S3": 29}, feb)
+	if rates["EC2"] != 10 || rates["S3"] != 1 {
+		t.Errorf("PerDay() = %v, want EC2=10 S3=1", rates)
+	}
+
+	// Empty periods leave costs unchanged
+	costs := map[string]float64{"EC2": 5}
+	if got := PerDay(costs, Period{Start: feb.Start, End: feb.Start}); got["EC2"] != 5 {
+		t.Errorf("PerDay() on empty period = %v, want unchanged", got)
+	}
+}
+
+func TestCompareForecast(t *testing.T) {
+	baseline := Period{
+		Start: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	months := []ForecastMonth{
+		{Total: 1100},
+		{Total: 900},
+	}
+
+	result := CompareForecast(baseline, 1000, months, 80)
+
+	if result.Interval != 80 || result.BaselineTotal != 1000 {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Months[0].Diff != 100 || result.Months[0].DiffPct != 10 {
+		t.Errorf("Months[0] diff = %v (%v%%), want 100 (10%%)", result.Months[0].Diff, result.Months[0].DiffPct)
+	}
+	if result.Months[1].Diff != -100 || result.Months[1].DiffPct != -10 {
+		t.Errorf("Months[1] diff = %v (%v%%), want -100 (-10%%)", result.Months[1].Diff, result.Months[1].DiffPct)
+	}
+
+	// No baseline spend leaves the percentage at zero
+	result = CompareForecast(baseline, 0, []ForecastMonth{{Total: 50}}, 80)
+	if result.Months[0].DiffPct != 0 {
+		t.Errorf("DiffPct = %v, want 0", result.Months[0].DiffPct)
+	}
+}
@@ -0,0 +1,62 @@
+package diff
+
+import "sort"
+
+// SetContributions works out each item's share of the net change, its cumulative
+// contribution in Pareto order, and its share of each period's total (mix).
+// Items keep their order.
+func (r *Result) SetContributions() {
+	for i := range r.Items {
+		item := &r.Items[i]
+		item.ContributionPct = 0
+		if r.TotalDiff != 0 {
+			item.ContributionPct = (item.Diff / r.TotalDiff) * 100
+		}
+		item.FromSharePct, item.ToSharePct = 0, 0
+		if r.FromTotal != 0 {
+			item.FromSharePct = (item.FromCost / r.FromTotal) * 100
+		}
+		if r.ToTotal != 0 {
+			item.ToSharePct = (item.ToCost / r.ToTotal) * 100
+		}
+		item.ShareShift = item.ToSharePct - item.FromSharePct
+	}
+
+	// Cumulative contribution follows the Pareto order without reordering items
+	order := make([]int, len(r.Items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return r.Items[order[a]].ContributionPct > r.Items[order[b]].ContributionPct
+	})
+
+	var cumulative float64
+	for _, i := range order {
+		cumulative += r.Items[i].ContributionPct
+		r.Items[i].CumulativePct = cumulative
+	}
+}
+
+// SortByContribution sorts items by contribution to the net change descending,
+// the order in which CumulativePct accumulates
+func SortByContribution(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ContributionPct > items[j].ContributionPct
+	})
+}
+
+// Pareto returns the fewest items, in contribution order, that together explain
+// at least pct percent of the net change. All items are returned when they
+// never reach pct, e.g. when the net change is zero.
+func Pareto(items []Item, pct float64) []Item {
+	sorted := append([]Item(nil), items...)
+	SortByContribution(sorted)
+
+	for i, item := range sorted {
+		if item.CumulativePct >= pct {
+			return sorted[:i+1]
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"math"
+	"testing"
+)
+
+func contributionResult() *Result {
+	result := &Result{
+		FromTotal: 1000,
+		ToTotal:   1100,
+		TotalDiff: 100,
+		Items: []Item{
+			{Name: "S3", FromCost: 200, ToCost: 230, Diff: 30},
+			{Name: "EC2", FromCost: 500, ToCost: 620, Diff: 120},
+			{Name: "RDS", FromCost: 300, ToCost: 250, Diff: -50},
+		},
+	}
+	result.SetContributions()
+	return result
+}
+
+func TestSetContributions(t *testing.T) {
+	result := contributionResult()
+
+	tests := []struct {
+		name         string
+		contribution float64
+		cumulative   float64
+		fromShare    float64
+		toShare      float64
+	}{
+		{"S3", 30, 150, 20, 20.909},
+		{"EC2", 120, 120, 50, 56.364},
+		{"RDS", -50, 100, 30, 22.727},
+	}
+
+	for i, tt := range tests {
+		item := result.Items[i]
+		if item.Name != tt.name {
+			t.Fatalf("Items[%d] = %s, want %s (order must be preserved)", i, item.Name, tt.name)
+		}
+		if math.Abs(item.ContributionPct-tt.contribution) > 0.001 {
+			t.Errorf("%s ContributionPct = %v, want %v", tt.name, item.ContributionPct, tt.contribution)
+		}
+		if math.Abs(item.CumulativePct-tt.cumulative) > 0.001 {
+			t.Errorf("%s CumulativePct = %v, want %v", tt.name, item.CumulativePct, tt.cumulative)
+		}
+		if math.Abs(item.FromSharePct-tt.fromShare) > 0.001 {
+			t.Errorf("%s FromSharePct = %v, want %v", tt.name, item.FromSharePct, tt.fromShare)
+		}
+		if math.Abs(item.ToSharePct-tt.toShare) > 0.001 {
+			t.Errorf("%s ToSharePct = %v, want %v", tt.name, item.ToSharePct, tt.toShare)
+		}
+		if math.Abs(item.ShareShift-(tt.toShare-tt.fromShare)) > 0.001 {
+			t.Errorf("%s ShareShift = %v, want %v", tt.name, item.ShareShift, tt.toShare-tt.fromShare)
+		}
+	}
+}
+
+func TestSetContributions_NoChange(t *testing.T) {
+	result := &Result{
+		FromTotal: 100,
+		ToTotal:   100,
+		Items: []Item{
+			{Name: "EC2", FromCost: 60, ToCost: 70, Diff: 10},
+			{Name: "S3", FromCost: 40, ToCost: 30, Diff: -10},
+		},
+	}
+	result.SetContributions()
+
+	for _, item := range result.Items {
+		if item.ContributionPct != 0 {
+			t.Errorf("%s ContributionPct = %v, want 0 when the net change is zero", item.Name, item.ContributionPct)
+		}
+	}
+	if got := Pareto(result.Items, 80); len(got) != 2 {
+		t.Errorf("Pareto() returned %d items, want all 2", len(got))
+	}
+}
+
+func TestPareto(t *testing.T) {
+	result := contributionResult()
+
+	tests := []struct {
+		pct  float64
+		want []string
+	}{
+		{50, []string{"EC2"}},
+		{120, []string{"EC2"}},
+		{130, []string{"EC2", "S3"}},
+		{100, []string{"EC2"}},
+		{200, []string{"EC2", "S3", "RDS"}},
+	}
+
+	for _, tt := range tests {
+		got := Pareto(result.Items, tt.pct)
+		if len(got) != len(tt.want) {
+			t.Errorf("Pareto(%v) returned %d items, want %v", tt.pct, len(got), tt.want)
+			continue
+		}
+		for i, name := range tt.want {
+			if got[i].Name != name {
+				t.Errorf("Pareto(%v)[%d] = %s, want %s", tt.pct, i, got[i].Name, name)
+			}
+		}
+	}
+
+	// The input keeps its order
+	if result.Items[0].Name != "S3" {
+		t.Error("Pareto() should not reorder its input")
+	}
+}
@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"math"
+	"sort"
+)
+
+// NewExplainNode builds the root of an explain tree from the totals of a comparison.
+// The root accounts for all of its own change.
+func NewExplainNode(dimension, name string, result *Result) ExplainNode {
+	node := ExplainNode{
+		Dimension: dimension,
+		Name:      name,
+		FromCost:  result.FromTotal,
+		ToCost:    result.ToTotal,
+		Diff:      result.TotalDiff,
+		DiffPct:   result.TotalPct,
+	}
+	if node.Diff != 0 {
+		node.ContributionPct = 100
+	}
+	return node
+}
+
+// ScoreDimension rates how well a breakdown of a change by one dimension
+// explains it: the share of the gross change (the sum of absolute changes)
+// that sits in the single largest mover. A change concentrated in one value
+// scores close to 100; one spread evenly across many values scores low.
+// Dimensions with fewer than two values explain nothing and score zero.
+func ScoreDimension(dimension string, items []Item) DimensionScore {
+	score := DimensionScore{Dimension: dimension, Values: len(items)}
+
+	var gross, largest float64
+	for _, item := range items {
+		change := math.Abs(item.Diff)
+		gross += change
+		if change > largest {
+			largest = change
+			score.Top = item.Name
+		}
+	}
+
+	if len(items) < 2 || gross == 0 {
+		return score
+	}
+	score.Score = (largest / gross) * 100
+	return score
+}
+
+// RankDimensions sorts scores best first, keeping the given order for ties
+func RankDimensions(scores []DimensionScore) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+}
+
+// Split records the ranked dimension scores and adds the largest movers of the
+// best dimension's items as children, at most limit of them. Items that did not
+// change are left out.
+func (n *ExplainNode) Split(scores []DimensionScore, items []Item, limit int) {
+	n.Scores = scores
+	n.Children = nil
+	if len(scores) == 0 || scores[0].Score == 0 {
+		return
+	}
+
+	movers := make([]Item, 0, len(items))
+	for _, item := range items {
+		if item.Diff != 0 {
+			movers = append(movers, item)
+		}
+	}
+	sort.SliceStable(movers, func(i, j int) bool {
+		return math.Abs(movers[i].Diff) > math.Abs(movers[j].Diff)
+	})
+	if limit > 0 && len(movers) > limit {
+		movers = movers[:limit]
+	}
+
+	for _, item := range movers {
+		child := ExplainNode{
+			Dimension: scores[0].Dimension,
+			Name:      item.Name,
+			FromCost:  item.FromCost,
+			ToCost:    item.ToCost,
+			Diff:      item.Diff,
+			DiffPct:   item.DiffPct,
+		}
+		if n.Diff != 0 {
+			child.ContributionPct = (item.Diff / n.Diff) * 100
+		}
+		n.Children = append(n.Children, child)
+	}
+}
@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScoreDimension(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []Item
+		wantScore float64
+		wantTop   string
+	}{
+		{
+			name:      "concentrated",
+			items:     []Item{{Name: "us-east-1", Diff: 90}, {Name: "eu-west-1", Diff: 10}},
+			wantScore: 90,
+			wantTop:   "us-east-1",
+		},
+		{
+			name:      "offsetting changes count towards the gross change",
+			items:     []Item{{Name: "a", Diff: 60}, {Name: "b", Diff: -30}, {Name: "c", Diff: 10}},
+			wantScore: 60,
+			wantTop:   "a",
+		},
+		{
+			name:      "largest drop",
+			items:     []Item{{Name: "a", Diff: 10}, {Name: "b", Diff: -30}},
+			wantScore: 75,
+			wantTop:   "b",
+		},
+		{
+			name:      "single value explains nothing",
+			items:     []Item{{Name: "111122223333", Diff: 100}},
+			wantScore: 0,
+			wantTop:   "111122223333",
+		},
+		{
+			name:      "no change",
+			items:     []Item{{Name: "a"}, {Name: "b"}},
+			wantScore: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := ScoreDimension("region", tt.items)
+			if math.Abs(score.Score-tt.wantScore) > 0.001 {
+				t.Errorf("Score = %v, want %v", score.Score, tt.wantScore)
+			}
+			if score.Top != tt.wantTop {
+				t.Errorf("Top = %q, want %q", score.Top, tt.wantTop)
+			}
+			if score.Values != len(tt.items) {
+				t.Errorf("Values = %d, want %d", score.Values, len(tt.items))
+			}
+		})
+	}
+}
+
+func TestRankDimensions(t *testing.T) {
+	scores := []DimensionScore{
+		{Dimension: "usage-type", Score: 50},
+		{Dimension: "region", Score: 80},
+		{Dimension: "account", Score: 80},
+		{Dimension: "operation", Score: 0},
+	}
+	RankDimensions(scores)
+
+	want := []string{"region", "account", "usage-type", "operation"}
+	for i, dim := range want {
+		if scores[i].Dimension != dim {
+			t.Errorf("scores[%d] = %s, want %s", i, scores[i].Dimension, dim)
+		}
+	}
+}
+
+func TestExplainNode_Split(t *testing.T) {
+	root := NewExplainNode("service", "Amazon EC2", &Result{FromTotal: 500, ToTotal: 700, TotalDiff: 200, TotalPct: 40})
+	if root.ContributionPct != 100 {
+		t.Errorf("root ContributionPct = %v, want 100", root.ContributionPct)
+	}
+
+	items := []Item{
+		{Name: "eu-west-1", FromCost: 100, ToCost: 150, Diff: 50},
+		{Name: "ap-south-1", FromCost: 20, ToCost: 20},
+		{Name: "us-east-1", FromCost: 300, ToCost: 480, Diff: 180},
+		{Name: "us-west-2", FromCost: 80, ToCost: 50, Diff: -30},
+	}
+	scores := []DimensionScore{ScoreDimension("region", items)}
+	root.Split(scores, items, 2)
+
+	if len(root.Children) != 2 {
+		t.Fatalf("got %d children, want 2", len(root.Children))
+	}
+	if root.Children[0].Name != "us-east-1" || root.Children[1].Name != "eu-west-1" {
+		t.Errorf("children = %s, %s, want us-east-1, eu-west-1", root.Children[0].Name, root.Children[1].Name)
+	}
+	if root.Children[0].Dimension != "region" {
+		t.Errorf("child Dimension = %q, want region", root.Children[0].Dimension)
+	}
+	if root.Children[0].ContributionPct != 90 {
+		t.Errorf("child ContributionPct = %v, want 90", root.Children[0].ContributionPct)
+	}
+}
+
+func TestExplainNode_SplitUnexplained(t *testing.T) {
+	node := ExplainNode{Diff: 100}
+	items := []Item{{Name: "111122223333", Diff: 100}}
+	node.Split([]DimensionScore{ScoreDimension("account", items)}, items, 3)
+
+	if len(node.Children) != 0 {
+		t.Errorf("got %d children, want none when no dimension explains the change", len(node.Children))
+	}
+	if len(node.Scores) != 1 {
+		t.Errorf("Scores should still be recorded, got %v", node.Scores)
+	}
+}
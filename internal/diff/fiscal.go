@@ -0,0 +1,261 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// FiscalCalendar describes a fiscal year of twelve periods grouped in four
+// quarters. With Weeks set, periods are whole weeks (a 4-4-5 calendar) and the
+// year starts on the WeekStart day nearest to the first of StartMonth; the
+// extra week of a 53-week year goes to the twelfth period. Without Weeks,
+// periods are calendar months and the year starts on the first of StartMonth.
+type FiscalCalendar struct {
+	StartMonth   time.Month
+	Weeks        []int // weeks in each period of a quarter, e.g. 4, 4, 5
+	WeekStart    time.Weekday
+	NamedByStart bool // FY25 starts in 2025 rather than ends in it
+}
+
+// Number of periods and quarters in a fiscal year
+const (
+	FiscalPeriods  = 12
+	FiscalQuarters = 4
+)
+
+var (
+	fiscalYearPattern    = regexp.MustCompile(`^[Ff][Yy](\d{2}|\d{4})$`)
+	fiscalQuarterPattern = regexp.MustCompile(`^[Ff][Yy](\d{2}|\d{4})-[Qq](\d)$`)
+	fiscalPeriodPattern  = regexp.MustCompile(`^[Ff][Yy](\d{2}|\d{4})-[Pp](\d{1,2})$`)
+)
+
+// Validate checks that the calendar describes twelve periods a year
+func (c *FiscalCalendar) Validate() error {
+	if c.StartMonth < time.January || c.StartMonth > time.December {
+		return fmt.Errorf("start month must be between 1 and 12, got %d", c.StartMonth)
+	}
+	if c.Weeks == nil {
+		return nil
+	}
+	if len(c.Weeks) != 3 {
+		return fmt.Errorf("a quarter must have 3 periods, got %d", len(c.Weeks))
+	}
+	weeks := 0
+	for _, w := range c.Weeks {
+		weeks += w
+	}
+	if weeks != 13 {
+		return fmt.Errorf("a quarter must have 13 weeks, got %d", weeks)
+	}
+	return nil
+}
+
+// Year returns fiscal year fy
+func (c *FiscalCalendar) Year(fy int) Period {
+	year := c.startYear(fy)
+	return Period{Start: c.yearStart(year), End: c.yearStart(year + 1)}
+}
+
+// Quarter returns quarter q (1-4) of fiscal year fy
+func (c *FiscalCalendar) Quarter(fy, q int) Period {
+	first, last := c.FiscalPeriod(fy, 3*q-2), c.FiscalPeriod(fy, 3*q)
+	return Period{Start: first.Start, End: last.End}
+}
+
+// FiscalPeriod returns period n (1-12) of fiscal year fy
+func (c *FiscalCalendar) FiscalPeriod(fy, n int) Period {
+	year := c.startYear(fy)
+	if c.Weeks == nil {
+		start := time.Date(year, c.StartMonth+time.Month(n-1), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+
+	weeks := 0
+	for i := 0; i < n-1; i++ {
+		weeks += c.Weeks[i%3]
+	}
+	start := c.yearStart(year).AddDate(0, 0, 7*weeks)
+	if n == FiscalPeriods {
+		return Period{Start: start, End: c.yearStart(year + 1)}
+	}
+	return Period{Start: start, End: start.AddDate(0, 0, 7*c.Weeks[(n-1)%3])}
+}
+
+// Locate returns the fiscal year and period (1-12) that day t falls in
+func (c *FiscalCalendar) Locate(t time.Time) (int, int) {
+	year := t.Year()
+	if t.Before(c.yearStart(year)) {
+		year--
+	} else if !t.Before(c.yearStart(year + 1)) {
+		year++
+	}
+
+	fy := year
+	if !c.NamedByStart && c.StartMonth != time.January {
+		fy++
+	}
+	for n := 1; n < FiscalPeriods; n++ {
+		if t.Before(c.FiscalPeriod(fy, n).End) {
+			return fy, n
+		}
+	}
+	return fy, FiscalPeriods
+}
+
+// Periods returns count consecutive fiscal periods, oldest first, ending with
+// the period that day last falls in
+func (c *FiscalCalendar) Periods(last time.Time, count int) []Period {
+	fy, n := c.Locate(last)
+	periods := make([]Period, count)
+	for i := count - 1; i >= 0; i-- {
+		periods[i] = c.FiscalPeriod(fy, n)
+		if n--; n == 0 {
+			fy, n = fy-1, FiscalPeriods
+		}
+	}
+	return periods
+}
+
+// Shift moves a period made of whole fiscal periods by the given number of
+// fiscal periods, e.g. -12 for the same periods a fiscal year earlier. ok is
+// false when p does not line up with fiscal periods.
+func (c *FiscalCalendar) Shift(p Period, periods int) (shifted Period, ok bool) {
+	from, to, ok := c.bounds(p)
+	if !ok {
+		return Period{}, false
+	}
+	start := c.FiscalPeriod(fiscalIndex(from + periods))
+	end := c.FiscalPeriod(fiscalIndex(to + periods))
+	return Period{Start: start.Start, End: end.End}, true
+}
+
+// count returns the number of fiscal periods in p, or 0 when p does not line
+// up with fiscal periods
+func (c *FiscalCalendar) count(p Period) int {
+	from, to, ok := c.bounds(p)
+	if !ok {
+		return 0
+	}
+	return to - from + 1
+}
+
+// bounds returns the indexes (fiscal year * 12 + period - 1) of the first and
+// last fiscal periods of p. ok is false when p does not line up with fiscal periods.
+func (c *FiscalCalendar) bounds(p Period) (from, to int, ok bool) {
+	if !p.End.After(p.Start) {
+		return 0, 0, false
+	}
+	fromYear, fromPeriod := c.Locate(p.Start)
+	toYear, toPeriod := c.Locate(p.End.AddDate(0, 0, -1))
+	if !c.FiscalPeriod(fromYear, fromPeriod).Start.Equal(p.Start) || !c.FiscalPeriod(toYear, toPeriod).End.Equal(p.End) {
+		return 0, 0, false
+	}
+	return fromYear*FiscalPeriods + fromPeriod - 1, toYear*FiscalPeriods + toPeriod - 1, true
+}
+
+// fiscalIndex converts an index from bounds back to a fiscal year and period
+func fiscalIndex(index int) (int, int) {
+	return index / FiscalPeriods, index%FiscalPeriods + 1
+}
+
+// Label returns the fiscal label of a period made of whole fiscal periods:
+// "FY25" for a year, "FY25-Q2" for a quarter, "FY25-P03" for a period,
+// "FY25-P03 - P05" or "FY24-P12 - FY25-P02" for a run of periods and
+// "FY24 - FY25" for a run of years. ok is false for any other period.
+func (c *FiscalCalendar) Label(p Period) (label string, ok bool) {
+	first, last, ok := c.bounds(p)
+	if !ok {
+		return "", false
+	}
+	fromYear, from := fiscalIndex(first)
+	toYear, to := fiscalIndex(last)
+
+	switch {
+	case from == 1 && to == FiscalPeriods && fromYear == toYear:
+		return fiscalYearName(fromYear), true
+	case from == 1 && to == FiscalPeriods:
+		return fiscalYearName(fromYear) + " - " + fiscalYearName(toYear), true
+	case fromYear == toYear && from == to:
+		return fmt.Sprintf("%s-P%02d", fiscalYearName(fromYear), from), true
+	case fromYear == toYear && from%3 == 1 && to == from+2:
+		return fmt.Sprintf("%s-Q%d", fiscalYearName(fromYear), (from+2)/3), true
+	case fromYear == toYear:
+		return fmt.Sprintf("%s-P%02d - P%02d", fiscalYearName(fromYear), from, to), true
+	default:
+		return fmt.Sprintf("%s-P%02d - %s-P%02d", fiscalYearName(fromYear), from, fiscalYearName(toYear), to), true
+	}
+}
+
+// parseFiscal parses FY25, FY25-Q2 and FY25-P03 (or FY2025, ...) against
+// calendar c, which may be nil. ok is false when s is not a fiscal expression.
+func parseFiscal(s string, c *FiscalCalendar) (period Period, ok bool, err error) {
+	var fy, n int
+	var unit string
+	if m := fiscalYearPattern.FindStringSubmatch(s); m != nil {
+		fy = fiscalYear(m[1])
+	} else if m := fiscalQuarterPattern.FindStringSubmatch(s); m != nil {
+		fy, unit = fiscalYear(m[1]), "quarter"
+		n, _ = strconv.Atoi(m[2])
+	} else if m := fiscalPeriodPattern.FindStringSubmatch(s); m != nil {
+		fy, unit = fiscalYear(m[1]), "period"
+		n, _ = strconv.Atoi(m[2])
+	} else {
+		return Period{}, false, nil
+	}
+
+	if c == nil {
+		return Period{}, true, fmt.Errorf("fiscal period %q needs a fiscal calendar (see --fiscal)", s)
+	}
+
+	switch {
+	case unit == "quarter" && (n < 1 || n > FiscalQuarters):
+		return Period{}, true, fmt.Errorf("invalid fiscal quarter %q: must be Q1 to Q%d", s, FiscalQuarters)
+	case unit == "quarter":
+		return c.Quarter(fy, n), true, nil
+	case unit == "period" && (n < 1 || n > FiscalPeriods):
+		return Period{}, true, fmt.Errorf("invalid fiscal period %q: must be P01 to P%02d", s, FiscalPeriods)
+	case unit == "period":
+		return c.FiscalPeriod(fy, n), true, nil
+	default:
+		return c.Year(fy), true, nil
+	}
+}
+
+// startYear returns the calendar year that fiscal year fy starts in
+func (c *FiscalCalendar) startYear(fy int) int {
+	if c.NamedByStart || c.StartMonth == time.January {
+		return fy
+	}
+	return fy - 1
+}
+
+// yearStart returns the first day of the fiscal year that starts in calendar year year
+func (c *FiscalCalendar) yearStart(year int) time.Time {
+	first := time.Date(year, c.StartMonth, 1, 0, 0, 0, 0, time.UTC)
+	if c.Weeks == nil {
+		return first
+	}
+
+	// Nearest WeekStart day, up to three days either side of the first
+	offset := (int(c.WeekStart) - int(first.Weekday()) + 7) % 7
+	if offset > 3 {
+		offset -= 7
+	}
+	return first.AddDate(0, 0, offset)
+}
+
+// fiscalYear converts the digits of FY25 or FY2025 to a year
+func fiscalYear(digits string) int {
+	year, _ := strconv.Atoi(digits)
+	if len(digits) == 2 {
+		year += 2000
+	}
+	return year
+}
+
+// fiscalYearName returns the short name of fiscal year fy, e.g. "FY25"
+func fiscalYearName(fy int) string {
+	return fmt.Sprintf("FY%02d", fy%100)
+}
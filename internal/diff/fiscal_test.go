@@ -0,0 +1,217 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// retail is a 4-4-5 calendar whose year starts on the Sunday nearest February 1
+var retail = &FiscalCalendar{StartMonth: time.February, Weeks: []int{4, 4, 5}, WeekStart: time.Sunday}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFiscalCalendar_Periods445(t *testing.T) {
+	tests := []struct {
+		name   string
+		period Period
+		want   Period
+	}{
+		// Feb 1, 2024 is a Thursday; the nearest Sunday is Feb 4
+		{"FY25", retail.Year(2025), Period{day(2024, 2, 4), day(2025, 2, 2)}},
+		{"FY25-P01", retail.FiscalPeriod(2025, 1), Period{day(2024, 2, 4), day(2024, 3, 3)}},
+		{"FY25-P03", retail.FiscalPeriod(2025, 3), Period{day(2024, 3, 31), day(2024, 5, 5)}},
+		{"FY25-Q2", retail.Quarter(2025, 2), Period{day(2024, 5, 5), day(2024, 8, 4)}},
+		{"FY25-P12", retail.FiscalPeriod(2025, 12), Period{day(2024, 12, 29), day(2025, 2, 2)}},
+		// FY29 has 53 weeks; the extra week goes to P12
+		{"FY29", retail.Year(2029), Period{day(2028, 1, 30), day(2029, 2, 4)}},
+		{"FY29-P12", retail.FiscalPeriod(2029, 12), Period{day(2028, 12, 24), day(2029, 2, 4)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.period.Start.Equal(tt.want.Start) || !tt.period.End.Equal(tt.want.End) {
+				t.Errorf("got %s..%s, want %s..%s",
+					tt.period.Start.Format("2006-01-02"), tt.period.End.Format("2006-01-02"),
+					tt.want.Start.Format("2006-01-02"), tt.want.End.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestFiscalCalendar_Locate(t *testing.T) {
+	monthly := &FiscalCalendar{StartMonth: time.April}
+	byStart := &FiscalCalendar{StartMonth: time.April, NamedByStart: true}
+
+	tests := []struct {
+		name       string
+		calendar   *FiscalCalendar
+		date       time.Time
+		wantYear   int
+		wantPeriod int
+	}{
+		{"first day", retail, day(2024, 2, 4), 2025, 1},
+		{"day before the year", retail, day(2024, 2, 3), 2024, 12},
+		{"January", retail, day(2025, 1, 15), 2025, 12},
+		{"third period", retail, day(2024, 4, 30), 2025, 3},
+		{"monthly", monthly, day(2024, 3, 31), 2024, 12},
+		{"monthly next year", monthly, day(2024, 4, 1), 2025, 1},
+		{"named by start", byStart, day(2024, 4, 1), 2024, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			year, period := tt.calendar.Locate(tt.date)
+			if year != tt.wantYear || period != tt.wantPeriod {
+				t.Errorf("Locate() = FY%d P%d, want FY%d P%d", year, period, tt.wantYear, tt.wantPeriod)
+			}
+		})
+	}
+}
+
+func TestFiscalCalendar_Periods(t *testing.T) {
+	periods := retail.Periods(day(2024, 3, 10), 3)
+	want := []string{"FY24-P12", "FY25-P01", "FY25-P02"}
+
+	if len(periods) != len(want) {
+		t.Fatalf("got %d periods, want %d", len(periods), len(want))
+	}
+	for i, p := range periods {
+		if label, _ := retail.Label(p); label != want[i] {
+			t.Errorf("period %d = %s, want %s", i, label, want[i])
+		}
+	}
+	if !periods[0].End.Equal(periods[1].Start) {
+		t.Errorf("periods are not consecutive: %v", periods)
+	}
+}
+
+func TestFiscalCalendar_Label(t *testing.T) {
+	tests := []struct {
+		name   string
+		period Period
+		want   string
+	}{
+		{"year", retail.Year(2025), "FY25"},
+		{"quarter", retail.Quarter(2025, 2), "FY25-Q2"},
+		{"period", retail.FiscalPeriod(2025, 3), "FY25-P03"},
+		{"run of periods", Period{retail.FiscalPeriod(2025, 3).Start, retail.FiscalPeriod(2025, 5).End}, "FY25-P03 - P05"},
+		{"across years", Period{retail.FiscalPeriod(2024, 12).Start, retail.FiscalPeriod(2025, 2).End}, "FY24-P12 - FY25-P02"},
+		{"run of years", Period{retail.Year(2024).Start, retail.Year(2025).End}, "FY24 - FY25"},
+		{"calendar month", Period{day(2024, 3, 1), day(2024, 4, 1)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retail.Label(tt.period)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("Label() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriod_LabelFiscal(t *testing.T) {
+	p3 := retail.FiscalPeriod(2025, 3)
+	if got := p3.Label(retail); got != "FY25-P03" {
+		t.Errorf("Label() = %q, want FY25-P03", got)
+	}
+	if got := p3.CalendarLabel(); got != "Mar 31 - May 4, 2024" {
+		t.Errorf("CalendarLabel() = %q", got)
+	}
+	if got := p3.ToJSON(retail); got.Label != "FY25-P03" || got.CalendarLabel != "Mar 31 - May 4, 2024" || got.Start != "2024-03-31" {
+		t.Errorf("ToJSON() = %+v", got)
+	}
+
+	// Periods that are not fiscal keep their calendar labels
+	oct := Period{day(2024, 10, 1), day(2024, 11, 1)}
+	if got := oct.Label(retail); got != "Oct 2024" {
+		t.Errorf("Label() = %q, want Oct 2024", got)
+	}
+	if got := oct.ToJSON(retail); got.CalendarLabel != "" {
+		t.Errorf("ToJSON().CalendarLabel = %q, want empty", got.CalendarLabel)
+	}
+}
+
+func TestParsePeriod_Fiscal(t *testing.T) {
+	now := day(2024, 11, 13)
+
+	tests := []struct {
+		input     string
+		wantStart string
+		wantEnd   string
+		wantLabel string
+	}{
+		{"FY25", "2024-02-04", "2025-02-02", "FY25"},
+		{"FY2025", "2024-02-04", "2025-02-02", "FY25"},
+		{"fy25-q2", "2024-05-05", "2024-08-04", "FY25-Q2"},
+		{"FY25-P03", "2024-03-31", "2024-05-05", "FY25-P03"},
+		{"FY25-P3", "2024-03-31", "2024-05-05", "FY25-P03"},
+		{"FY25-P01..FY25-P03", "2024-02-04", "2024-05-05", "FY25-Q1"},
+		{"FY25-P11..2024-12-31", "2024-12-01", "2025-01-01", "Dec 2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			period, err := ParsePeriod(tt.input, now, retail)
+			if err != nil {
+				t.Fatalf("ParsePeriod() error = %v", err)
+			}
+			if got := period.Start.Format("2006-01-02"); got != tt.wantStart {
+				t.Errorf("Start = %s, want %s", got, tt.wantStart)
+			}
+			if got := period.End.Format("2006-01-02"); got != tt.wantEnd {
+				t.Errorf("End = %s, want %s", got, tt.wantEnd)
+			}
+			if got := period.Label(retail); got != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+			}
+		})
+	}
+
+	for input, wantErr := range map[string]string{
+		"FY25-Q5":  "must be Q1 to Q4",
+		"FY25-P13": "must be P01 to P12",
+		"FY25-P00": "must be P01 to P12",
+	} {
+		if _, err := ParsePeriod(input, now, retail); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParsePeriod(%s) error = %v, want containing %q", input, err, wantErr)
+		}
+	}
+}
+
+func TestParsePeriod_FiscalWithoutCalendar(t *testing.T) {
+	_, err := ParsePeriod("FY25-Q2", day(2024, 11, 13), nil)
+	if err == nil || !strings.Contains(err.Error(), "needs a fiscal calendar") {
+		t.Errorf("ParsePeriod() error = %v, want a missing calendar error", err)
+	}
+}
+
+func TestFiscalCalendar_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar FiscalCalendar
+		wantErr  string
+	}{
+		{"monthly", FiscalCalendar{StartMonth: time.October}, ""},
+		{"4-4-5", *retail, ""},
+		{"5-4-4", FiscalCalendar{StartMonth: time.January, Weeks: []int{5, 4, 4}}, ""},
+		{"no month", FiscalCalendar{}, "start month"},
+		{"two periods", FiscalCalendar{StartMonth: time.January, Weeks: []int{6, 7}}, "3 periods"},
+		{"14 weeks", FiscalCalendar{StartMonth: time.January, Weeks: []int{4, 5, 5}}, "13 weeks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.calendar.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package diff
+
+import "fmt"
+
+// otherName labels the rollup of n items left out of a result
+func otherName(n int) string {
+	if n == 1 {
+		return "Other (1 item)"
+	}
+	return fmt.Sprintf("Other (%d items)", n)
+}
+
+// OtherItem sums the items in all that are missing from kept into a single
+// synthetic "Other (N items)" item, so that the shown rows still add up to the
+// result's totals. Budgets of the items left out are summed as well. It returns
+// false when nothing was left out.
+func OtherItem(all, kept []Item) (Item, bool) {
+	keptNames := make(map[string]bool, len(kept))
+	for _, item := range kept {
+		keptNames[item.Name] = true
+	}
+
+	other := Item{Synthetic: true}
+	var count int
+	var charges map[string]*ChargeDiff
+	var budgets []*BudgetStatus
+	for _, item := range all {
+		// Cumulative contribution covers every item, dropped or not
+		other.CumulativePct += item.ContributionPct
+		if keptNames[item.Name] {
+			continue
+		}
+
+		count++
+		other.FromCost += item.FromCost
+		other.ToCost += item.ToCost
+		other.Diff += item.Diff
+		other.ContributionPct += item.ContributionPct
+		other.FromSharePct += item.FromSharePct
+		other.ToSharePct += item.ToSharePct
+		other.ShareShift += item.ShareShift
+		budgets = append(budgets, item.Budget)
+
+		for _, charge := range item.Charges {
+			if charges == nil {
+				charges = make(map[string]*ChargeDiff)
+			}
+			if charges[charge.Type] == nil {
+				charges[charge.Type] = &ChargeDiff{Type: charge.Type}
+			}
+			charges[charge.Type].FromCost += charge.FromCost
+			charges[charge.Type].ToCost += charge.ToCost
+			charges[charge.Type].Diff += charge.Diff
+		}
+	}
+	if count == 0 {
+		return Item{}, false
+	}
+
+	other.Name = otherName(count)
+	other.Budget = sumBudgets(budgets)
+	if other.FromCost > 0 {
+		other.DiffPct = (other.Diff / other.FromCost) * 100
+	} else if other.ToCost > 0 {
+		other.DiffPct = 100
+	}
+
+	if charges != nil {
+		other.Charges = make([]ChargeDiff, 0, len(charges))
+		for _, charge := range charges {
+			other.Charges = append(other.Charges, *charge)
+		}
+		sortCharges(other.Charges)
+		other.Driver = driver(other.Charges)
+	}
+
+	return other, true
+}
+
+// OtherTopItem sums the items in all that are missing from kept into a single
+// synthetic "Other (N items)" item. It returns false when nothing was left out.
+func OtherTopItem(all, kept []TopItem) (TopItem, bool) {
+	keptNames := make(map[string]bool, len(kept))
+	for _, item := range kept {
+		keptNames[item.Name] = true
+	}
+
+	other := TopItem{Synthetic: true}
+	var count int
+	var budgets []*BudgetStatus
+	for _, item := range all {
+		if keptNames[item.Name] {
+			continue
+		}
+		count++
+		other.Cost += item.Cost
+		other.Percent += item.Percent
+		budgets = append(budgets, item.Budget)
+	}
+	if count == 0 {
+		return TopItem{}, false
+	}
+
+	other.Name = otherName(count)
+	other.Budget = sumBudgets(budgets)
+	return other, true
+}
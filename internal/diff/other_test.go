@@ -0,0 +1,122 @@
+package diff
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOtherItem(t *testing.T) {
+	result := Compare(
+		map[string]float64{"EC2": 500, "S3": 100, "RDS": 200, "Lambda": 50},
+		map[string]float64{"EC2": 700, "S3": 120, "RDS": 150, "Lambda": 0},
+		Period{}, Period{},
+	)
+	all := result.Items
+	kept := all[:1] // EC2
+
+	other, ok := OtherItem(all, kept)
+	if !ok {
+		t.Fatal("OtherItem() ok = false, want true")
+	}
+
+	if other.Name != "Other (3 items)" {
+		t.Errorf("Name = %q, want %q", other.Name, "Other (3 items)")
+	}
+	if !other.Synthetic {
+		t.Error("Synthetic should be set")
+	}
+	if other.FromCost != 350 || other.ToCost != 270 || other.Diff != -80 {
+		t.Errorf("costs = %v -> %v (%v), want 350 -> 270 (-80)", other.FromCost, other.ToCost, other.Diff)
+	}
+	if math.Abs(other.DiffPct-(-80.0/350*100)) > 0.001 {
+		t.Errorf("DiffPct = %v, want %v", other.DiffPct, -80.0/350*100)
+	}
+	if other.IsNew || other.IsRemoved {
+		t.Error("Other should not be marked new or removed")
+	}
+
+	// Kept rows plus Other add up to the totals
+	if got := kept[0].ToCost + other.ToCost; got != result.ToTotal {
+		t.Errorf("kept + other = %v, want total %v", got, result.ToTotal)
+	}
+	if math.Abs(kept[0].ContributionPct+other.ContributionPct-100) > 0.001 {
+		t.Errorf("contributions add up to %v, want 100", kept[0].ContributionPct+other.ContributionPct)
+	}
+	if math.Abs(other.CumulativePct-100) > 0.001 {
+		t.Errorf("CumulativePct = %v, want 100", other.CumulativePct)
+	}
+}
+
+func TestOtherItem_NothingLeftOut(t *testing.T) {
+	items := []Item{{Name: "EC2"}, {Name: "S3"}}
+
+	if _, ok := OtherItem(items, items); ok {
+		t.Error("OtherItem() ok = true, want false when every item is kept")
+	}
+}
+
+func TestOtherItem_SingleItem(t *testing.T) {
+	items := []Item{{Name: "EC2", FromCost: 10, ToCost: 20, Diff: 10}, {Name: "S3", ToCost: 5, Diff: 5}}
+
+	other, ok := OtherItem(items, items[:1])
+	if !ok {
+		t.Fatal("OtherItem() ok = false, want true")
+	}
+	if other.Name != "Other (1 item)" {
+		t.Errorf("Name = %q, want %q", other.Name, "Other (1 item)")
+	}
+	if other.DiffPct != 100 {
+		t.Errorf("DiffPct = %v, want 100 for costs that are all new", other.DiffPct)
+	}
+}
+
+func TestOtherItem_Charges(t *testing.T) {
+	items := []Item{
+		{Name: "EC2"},
+		{Name: "S3", Diff: 5, Charges: []ChargeDiff{
+			{Type: "Usage", FromCost: 10, ToCost: 20, Diff: 10},
+			{Type: "Credit", FromCost: 0, ToCost: -5, Diff: -5},
+		}},
+		{Name: "RDS", Diff: -20, Charges: []ChargeDiff{
+			{Type: "Credit", FromCost: 0, ToCost: -20, Diff: -20},
+		}},
+	}
+
+	other, ok := OtherItem(items, items[:1])
+	if !ok {
+		t.Fatal("OtherItem() ok = false, want true")
+	}
+
+	if len(other.Charges) != 2 {
+		t.Fatalf("got %d charges, want 2", len(other.Charges))
+	}
+	if other.Charges[0].Type != "Credit" || other.Charges[0].Diff != -25 {
+		t.Errorf("Charges[0] = %+v, want Credit with diff -25", other.Charges[0])
+	}
+	if other.Driver != "Credit" {
+		t.Errorf("Driver = %q, want Credit", other.Driver)
+	}
+}
+
+func TestOtherTopItem(t *testing.T) {
+	items := []TopItem{
+		{Name: "EC2", Cost: 600, Percent: 60},
+		{Name: "S3", Cost: 300, Percent: 30},
+		{Name: "RDS", Cost: 100, Percent: 10},
+	}
+
+	other, ok := OtherTopItem(items, items[:1])
+	if !ok {
+		t.Fatal("OtherTopItem() ok = false, want true")
+	}
+	if other.Name != "Other (2 items)" || !other.Synthetic {
+		t.Errorf("got %+v, want synthetic Other (2 items)", other)
+	}
+	if other.Cost != 400 || other.Percent != 40 {
+		t.Errorf("Cost = %v, Percent = %v, want 400 and 40", other.Cost, other.Percent)
+	}
+
+	if _, ok := OtherTopItem(items, items); ok {
+		t.Error("OtherTopItem() ok = true, want false when every item is kept")
+	}
+}
@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PeriodSyntax lists the period expressions ParsePeriod accepts, for error messages and help
+const PeriodSyntax = "YYYY, YYYY-MM, YYYY-MM-DD, YYYY-Qn, YYYY-Wnn, FYnn, FYnn-Qn, FYnn-Pnn, last-Nd, prev-week|month|quarter|year, ytd, or FROM..TO"
+
+var (
+	quarterPattern  = regexp.MustCompile(`^(\d{4})-[Qq]([1-4])$`)
+	weekPattern     = regexp.MustCompile(`^(\d{4})-[Ww](\d{2})$`)
+	yearPattern     = regexp.MustCompile(`^\d{4}$`)
+	lastDaysPattern = regexp.MustCompile(`^last-(\d+)d$`)
+)
+
+// ParsePeriod parses a period expression. Relative expressions are resolved
+// against the day of now and only cover complete days, and fiscal ones
+// against calendar c, which may be nil:
+//
+//	2024          the calendar year
+//	2024-07       a month
+//	2024-07-15    a day
+//	2024-Q3       a calendar quarter
+//	2024-W05      an ISO week, Monday to Sunday
+//	FY25          a fiscal year (also FY25-Q2 and FY25-P03)
+//	last-30d      the 30 days before today
+//	prev-month    the previous calendar month (also prev-week, prev-quarter, prev-year)
+//	ytd           January 1 up to today
+//	A..B          from the start of A through the end of B, e.g. 2024-01-05..2024-02-10
+func ParsePeriod(s string, now time.Time, c *FiscalCalendar) (Period, error) {
+	s = strings.TrimSpace(s)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		return parseRange(from, to, today, c)
+	}
+	return parseSingle(s, today, c)
+}
+
+// parseRange parses both ends of a FROM..TO expression into one period
+func parseRange(from, to string, today time.Time, c *FiscalCalendar) (Period, error) {
+	start, err := parseSingle(strings.TrimSpace(from), today, c)
+	if err != nil {
+		return Period{}, err
+	}
+	end, err := parseSingle(strings.TrimSpace(to), today, c)
+	if err != nil {
+		return Period{}, err
+	}
+	if !end.End.After(start.Start) {
+		return Period{}, fmt.Errorf("range %s..%s ends before it starts", from, to)
+	}
+	return Period{Start: start.Start, End: end.End}, nil
+}
+
+// parseSingle parses any expression except a range
+func parseSingle(s string, today time.Time, c *FiscalCalendar) (Period, error) {
+	if period, ok, err := parseFiscal(s, c); ok {
+		return period, err
+	}
+
+	// Try YYYY-MM-DD format
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return Period{Start: t, End: t.AddDate(0, 0, 1)}, nil
+	}
+
+	// Try YYYY-MM format (full month)
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return Period{Start: t, End: t.AddDate(0, 1, 0)}, nil
+	}
+
+	if yearPattern.MatchString(s) {
+		year, _ := strconv.Atoi(s)
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return Period{Start: start, End: start.AddDate(1, 0, 0)}, nil
+	}
+
+	if m := quarterPattern.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		q, _ := strconv.Atoi(m[2])
+		start := time.Date(year, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Start: start, End: start.AddDate(0, 3, 0)}, nil
+	}
+
+	if m := weekPattern.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		week, _ := strconv.Atoi(m[2])
+		start := isoWeekStart(year, week)
+		if y, w := start.ISOWeek(); y != year || w != week {
+			return Period{}, fmt.Errorf("invalid week %q: %d has no week %d", s, year, week)
+		}
+		return Period{Start: start, End: start.AddDate(0, 0, 7)}, nil
+	}
+
+	if m := lastDaysPattern.FindStringSubmatch(s); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil || days < 1 {
+			return Period{}, fmt.Errorf("invalid period %q: the number of days must be at least 1", s)
+		}
+		return Period{Start: today.AddDate(0, 0, -days), End: today}, nil
+	}
+
+	switch strings.ToLower(s) {
+	case "prev-week":
+		thisWeek := today.AddDate(0, 0, -daysSinceMonday(today))
+		return Period{Start: thisWeek.AddDate(0, 0, -7), End: thisWeek}, nil
+	case "prev-month":
+		thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Start: thisMonth.AddDate(0, -1, 0), End: thisMonth}, nil
+	case "prev-quarter":
+		thisQuarter := time.Date(today.Year(), time.Month(3*(quarter(today)-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		return Period{Start: thisQuarter.AddDate(0, -3, 0), End: thisQuarter}, nil
+	case "prev-year":
+		thisYear := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return Period{Start: thisYear.AddDate(-1, 0, 0), End: thisYear}, nil
+	case "ytd":
+		thisYear := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		if !today.After(thisYear) {
+			return Period{}, fmt.Errorf("ytd has no complete days yet on January 1")
+		}
+		return Period{Start: thisYear, End: today}, nil
+	}
+
+	return Period{}, fmt.Errorf("invalid period %q (use %s)", s, PeriodSyntax)
+}
+
+// isoWeekStart returns the Monday of an ISO week. Week 1 is the week with the
+// year's first Thursday, which always contains January 4.
+func isoWeekStart(year, week int) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	return jan4.AddDate(0, 0, -daysSinceMonday(jan4)+7*(week-1))
+}
+
+// daysSinceMonday returns how many days t is after the Monday of its week
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// quarter returns the calendar quarter (1-4) of t
+func quarter(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
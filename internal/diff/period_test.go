@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	// A Wednesday afternoon
+	now := time.Date(2024, 11, 13, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input     string
+		wantStart string
+		wantEnd   string
+		wantLabel string
+	}{
+		{"2024", "2024-01-01", "2025-01-01", "2024"},
+		{"2024-07", "2024-07-01", "2024-08-01", "Jul 2024"},
+		{"2024-07-15", "2024-07-15", "2024-07-16", "Jul 15, 2024"},
+		{"2024-Q3", "2024-07-01", "2024-10-01", "Q3 2024"},
+		{"2024-q1", "2024-01-01", "2024-04-01", "Q1 2024"},
+		{"2024-W05", "2024-01-29", "2024-02-05", "W05 2024"},
+		// ISO week 1 can start in the previous year
+		{"2025-W01", "2024-12-30", "2025-01-06", "W01 2025"},
+		{"2020-W53", "2020-12-28", "2021-01-04", "W53 2020"},
+		{"last-30d", "2024-10-14", "2024-11-13", "Oct 14 - Nov 12, 2024"},
+		{"last-1d", "2024-11-12", "2024-11-13", "Nov 12, 2024"},
+		{"prev-week", "2024-11-04", "2024-11-11", "W45 2024"},
+		{"prev-month", "2024-10-01", "2024-11-01", "Oct 2024"},
+		{"prev-quarter", "2024-07-01", "2024-10-01", "Q3 2024"},
+		{"prev-year", "2023-01-01", "2024-01-01", "2023"},
+		{"ytd", "2024-01-01", "2024-11-13", "Jan 1 - Nov 12, 2024"},
+		{"2024-01-05..2024-02-10", "2024-01-05", "2024-02-11", "Jan 5 - Feb 10, 2024"},
+		{"2024-01..2024-03", "2024-01-01", "2024-04-01", "Q1 2024"},
+		{"2024-02..2024-04", "2024-02-01", "2024-05-01", "Feb - Apr 2024"},
+		{"2023-11..2024-02", "2023-11-01", "2024-03-01", "Nov 2023 - Feb 2024"},
+		{"2023-Q4..2024-Q1", "2023-10-01", "2024-04-01", "Oct 2023 - Mar 2024"},
+		{"2023-06-15..2024-02-10", "2023-06-15", "2024-02-11", "Jun 15, 2023 - Feb 10, 2024"},
+		{"2024-10-01..ytd", "2024-10-01", "2024-11-13", "Oct 1 - Nov 12, 2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			period, err := ParsePeriod(tt.input, now, nil)
+			if err != nil {
+				t.Fatalf("ParsePeriod() error = %v", err)
+			}
+			if got := period.Start.Format("2006-01-02"); got != tt.wantStart {
+				t.Errorf("Start = %s, want %s", got, tt.wantStart)
+			}
+			if got := period.End.Format("2006-01-02"); got != tt.wantEnd {
+				t.Errorf("End = %s, want %s", got, tt.wantEnd)
+			}
+			if got := period.Label(nil); got != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestParsePeriod_Invalid(t *testing.T) {
+	now := time.Date(2024, 11, 13, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"invalid", "invalid period"},
+		{"12-2024", "invalid period"},
+		{"2024/12/15", "invalid period"},
+		{"2024-Q5", "invalid period"},
+		{"2024-W00", "has no week 0"},
+		{"2024-W53", "has no week 53"},
+		{"last-0d", "at least 1"},
+		{"last-d", "invalid period"},
+		{"2024-03..2024-01", "ends before it starts"},
+		{"2024-01..", "invalid period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParsePeriod(tt.input, now, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParsePeriod() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := ParsePeriod("ytd", time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), nil); err == nil {
+		t.Error("ytd on January 1 should have no complete days")
+	}
+}
@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConditionKind is what a --fail-on condition checks
+type ConditionKind string
+
+// Condition kinds, in the order their violations are reported
+const (
+	ConditionTotal ConditionKind = "total" // the total increase
+	ConditionItem  ConditionKind = "item"  // the increase of any single item
+	ConditionNew   ConditionKind = "new"   // the cost of any new item
+)
+
+// Units of a condition threshold
+const (
+	UnitUSD     = "usd"
+	UnitPercent = "percent"
+)
+
+// Condition is a single --fail-on check, such as "total>10%" or "item>$500"
+type Condition struct {
+	Text      string
+	Kind      ConditionKind
+	Threshold float64
+	Percent   bool // threshold is a percentage rather than dollars
+}
+
+// ParseCondition parses a condition of the form KIND>AMOUNT, where KIND is
+// total, item or new and AMOUNT is dollars ("500" or "$500") or, for total
+// only, a percentage ("10%")
+func ParseCondition(s string) (Condition, error) {
+	text := strings.TrimSpace(s)
+	kind, amount, found := strings.Cut(text, ">")
+	if !found {
+		return Condition{}, fmt.Errorf("invalid condition %q (must be KIND>AMOUNT, e.g. total>10%% or item>$500)", s)
+	}
+
+	cond := Condition{Text: text, Kind: ConditionKind(strings.ToLower(strings.TrimSpace(kind)))}
+	switch cond.Kind {
+	case ConditionTotal, ConditionItem, ConditionNew:
+	default:
+		return Condition{}, fmt.Errorf("invalid condition %q: unknown kind %q (must be total|item|new)", s, strings.TrimSpace(kind))
+	}
+
+	amount = strings.TrimSpace(amount)
+	if value, ok := strings.CutSuffix(amount, "%"); ok {
+		if cond.Kind != ConditionTotal {
+			return Condition{}, fmt.Errorf("invalid condition %q: only total conditions take a percentage", s)
+		}
+		cond.Percent = true
+		amount = value
+	} else {
+		amount = strings.TrimPrefix(amount, "$")
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	if err != nil || threshold < 0 {
+		return Condition{}, fmt.Errorf("invalid condition %q: amount must be a non-negative number", s)
+	}
+	cond.Threshold = threshold
+
+	return cond, nil
+}
+
+// ParseConditions parses every condition in list
+func ParseConditions(list []string) ([]Condition, error) {
+	conditions := make([]Condition, 0, len(list))
+	for _, s := range list {
+		cond, err := ParseCondition(s)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, nil
+}
+
+// Evaluate checks the result against conditions and records the outcome in
+// r.Gate. It must run before items are filtered or limited, so that every
+// item is checked.
+func (r *Result) Evaluate(conditions []Condition) {
+	gate := &Gate{Passed: true, Violations: make([]Violation, 0)}
+
+	for _, cond := range conditions {
+		gate.Conditions = append(gate.Conditions, cond.Text)
+
+		switch cond.Kind {
+		case ConditionTotal:
+			value := r.TotalDiff
+			if cond.Percent {
+				value = r.TotalPct
+			}
+			if value > cond.Threshold {
+				gate.Violations = append(gate.Violations, cond.violation("", value))
+			}
+		case ConditionItem, ConditionNew:
+			for _, item := range r.Items {
+				if item.Synthetic {
+					continue
+				}
+				if cond.Kind == ConditionItem && item.Diff > cond.Threshold {
+					gate.Violations = append(gate.Violations, cond.violation(item.Name, item.Diff))
+				}
+				if cond.Kind == ConditionNew && item.IsNew && item.ToCost > cond.Threshold {
+					gate.Violations = append(gate.Violations, cond.violation(item.Name, item.ToCost))
+				}
+			}
+		}
+	}
+
+	gate.Passed = len(gate.Violations) == 0
+	r.Gate = gate
+}
+
+// violation builds the violation of cond by value
+func (c Condition) violation(name string, value float64) Violation {
+	unit := UnitUSD
+	if c.Percent {
+		unit = UnitPercent
+	}
+	return Violation{
+		Condition: c.Text,
+		Kind:      c.Kind,
+		Name:      name,
+		Value:     value,
+		Threshold: c.Threshold,
+		Unit:      unit,
+	}
+}
+
+// Kinds returns the distinct kinds of the gate's violations, in the order
+// total, item, new
+func (g *Gate) Kinds() []ConditionKind {
+	var kinds []ConditionKind
+	for _, kind := range []ConditionKind{ConditionTotal, ConditionItem, ConditionNew} {
+		for _, v := range g.Violations {
+			if v.Kind == kind {
+				kinds = append(kinds, kind)
+				break
+			}
+		}
+	}
+	return kinds
+}
@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Condition
+		wantErr string
+	}{
+		{input: "total>10%", want: Condition{Text: "total>10%", Kind: ConditionTotal, Threshold: 10, Percent: true}},
+		{input: "total>$500", want: Condition{Text: "total>$500", Kind: ConditionTotal, Threshold: 500}},
+		{input: " Item > 250.5 ", want: Condition{Text: "Item > 250.5", Kind: ConditionItem, Threshold: 250.5}},
+		{input: "new>$0", want: Condition{Text: "new>$0", Kind: ConditionNew, Threshold: 0}},
+		{input: "total=10%", wantErr: "must be KIND>AMOUNT"},
+		{input: "service>10", wantErr: `unknown kind "service"`},
+		{input: "item>5%", wantErr: "only total conditions take a percentage"},
+		{input: "new>lots", wantErr: "non-negative number"},
+		{input: "total>-5", wantErr: "non-negative number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCondition(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseCondition() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCondition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_Evaluate(t *testing.T) {
+	result := &Result{
+		FromTotal: 1000,
+		ToTotal:   1150,
+		TotalDiff: 150,
+		TotalPct:  15,
+		Items: []Item{
+			{Name: "EC2", FromCost: 500, ToCost: 620, Diff: 120},
+			{Name: "SQS", ToCost: 60, Diff: 60, IsNew: true},
+			{Name: "S3", FromCost: 500, ToCost: 470, Diff: -30},
+			{Name: "Other (2 items)", Diff: 500, Synthetic: true},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		conditions []string
+		wantKinds  []ConditionKind
+		wantNames  []string
+	}{
+		{"total percent over", []string{"total>10%"}, []ConditionKind{ConditionTotal}, []string{""}},
+		{"total percent under", []string{"total>20%"}, nil, nil},
+		{"total dollars", []string{"total>$100"}, []ConditionKind{ConditionTotal}, []string{""}},
+		{"items over", []string{"item>50"}, []ConditionKind{ConditionItem}, []string{"EC2", "SQS"}},
+		{"new items", []string{"new>$50"}, []ConditionKind{ConditionNew}, []string{"SQS"}},
+		{"new items under", []string{"new>$100"}, nil, nil},
+		{"several kinds", []string{"new>$10", "total>5%"}, []ConditionKind{ConditionTotal, ConditionNew}, []string{"SQS", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions, err := ParseConditions(tt.conditions)
+			if err != nil {
+				t.Fatalf("ParseConditions() error = %v", err)
+			}
+			result.Evaluate(conditions)
+			gate := result.Gate
+
+			if gate.Passed != (len(tt.wantNames) == 0) {
+				t.Errorf("Passed = %v with violations %+v", gate.Passed, gate.Violations)
+			}
+			if len(gate.Conditions) != len(tt.conditions) {
+				t.Errorf("Conditions = %v, want %v", gate.Conditions, tt.conditions)
+			}
+			if len(gate.Violations) != len(tt.wantNames) {
+				t.Fatalf("Violations = %+v, want %d", gate.Violations, len(tt.wantNames))
+			}
+			for i, name := range tt.wantNames {
+				if gate.Violations[i].Name != name {
+					t.Errorf("violation %d name = %q, want %q", i, gate.Violations[i].Name, name)
+				}
+			}
+			kinds := gate.Kinds()
+			if len(kinds) != len(tt.wantKinds) {
+				t.Fatalf("Kinds() = %v, want %v", kinds, tt.wantKinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tt.wantKinds[i] {
+					t.Errorf("Kinds() = %v, want %v", kinds, tt.wantKinds)
+				}
+			}
+		})
+	}
+}
+
+func TestResult_Evaluate_ViolationFields(t *testing.T) {
+	result := &Result{TotalDiff: 150, TotalPct: 15}
+	conditions, _ := ParseConditions([]string{"total>10%"})
+	result.Evaluate(conditions)
+
+	v := result.Gate.Violations[0]
+	if v.Condition != "total>10%" || v.Value != 15 || v.Threshold != 10 || v.Unit != UnitPercent {
+		t.Errorf("violation = %+v", v)
+	}
+}
@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"math"
+	"sort"
+)
+
+// rolledUpName is the item that the "Other (N items)" rows of saved results
+// are compared under. The rows rarely cover the same items, so their names
+// rarely match.
+const rolledUpName = "Other"
+
+// CompareTop compares two top results, such as saved top outputs of two
+// periods, as if their costs had been fetched for a diff. Items rolled up into
+// "Other" rows are compared as a single "Other" item.
+func CompareTop(from, to *TopResult) *Result {
+	result := Compare(topCosts(from), topCosts(to), from.Period, to.Period)
+	result.Fiscal = to.Fiscal
+	for i := range result.Items {
+		result.Items[i].Synthetic = result.Items[i].Name == rolledUpName && (hasSynthetic(from) || hasSynthetic(to))
+	}
+	return result
+}
+
+// topCosts returns the cost of each item of a top result, with the "Other"
+// rows summed under rolledUpName
+func topCosts(r *TopResult) map[string]float64 {
+	costs := make(map[string]float64, len(r.Items))
+	for _, item := range r.Items {
+		if item.Synthetic {
+			costs[rolledUpName] += item.Cost
+			continue
+		}
+		costs[item.Name] += item.Cost
+	}
+	return costs
+}
+
+// hasSynthetic reports whether a top result has an "Other" row
+func hasSynthetic(r *TopResult) bool {
+	for _, item := range r.Items {
+		if item.Synthetic {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareChanges compares the changes of two diff results, item by item,
+// largest difference first. Items rolled up into "Other" rows are compared as
+// a single "Other" item.
+func CompareChanges(older, newer *Result) *ChangeResult {
+	result := &ChangeResult{
+		Old:        changeSide(older),
+		New:        changeSide(newer),
+		GroupBy:    newer.GroupBy,
+		PerDay:     newer.PerDay,
+		TotalDelta: newer.TotalDiff - older.TotalDiff,
+		Items:      make([]ChangeItem, 0),
+		Fiscal:     newer.Fiscal,
+	}
+
+	oldItems, newItems := changeItems(older), changeItems(newer)
+	for name, item := range oldItems {
+		change := ChangeItem{
+			Name:       name,
+			Keys:       item.Keys,
+			OldDiff:    item.Diff,
+			OldDiffPct: item.DiffPct,
+			Synthetic:  item.Synthetic,
+		}
+		if newItem, ok := newItems[name]; ok {
+			change.NewDiff = newItem.Diff
+			change.NewDiffPct = newItem.DiffPct
+		} else {
+			change.OnlyInOld = true
+		}
+		change.Delta = change.NewDiff - change.OldDiff
+		result.Items = append(result.Items, change)
+	}
+	for name, item := range newItems {
+		if _, ok := oldItems[name]; ok {
+			continue
+		}
+		result.Items = append(result.Items, ChangeItem{
+			Name:       name,
+			Keys:       item.Keys,
+			NewDiff:    item.Diff,
+			NewDiffPct: item.DiffPct,
+			Delta:      item.Diff,
+			OnlyInNew:  true,
+			Synthetic:  item.Synthetic,
+		})
+	}
+
+	SortChangesByDelta(result.Items)
+	return result
+}
+
+// changeSide summarizes a diff result for a ChangeResult
+func changeSide(r *Result) ChangeSide {
+	return ChangeSide{
+		FromPeriod: r.FromPeriod,
+		ToPeriod:   r.ToPeriod,
+		FromLabel:  r.FromLabel(),
+		FromTotal:  r.FromTotal,
+		ToTotal:    r.ToTotal,
+		TotalDiff:  r.TotalDiff,
+		TotalPct:   r.TotalPct,
+	}
+}
+
+// changeItems returns the items of a diff result by name, with the "Other"
+// rows summed under rolledUpName
+func changeItems(r *Result) map[string]Item {
+	items := make(map[string]Item, len(r.Items))
+	for _, item := range r.Items {
+		if !item.Synthetic {
+			items[item.Name] = item
+			continue
+		}
+
+		other := items[rolledUpName]
+		other.Name, other.Synthetic = rolledUpName, true
+		other.FromCost += item.FromCost
+		other.ToCost += item.ToCost
+		other.Diff += item.Diff
+		if other.FromCost > 0 {
+			other.DiffPct = (other.Diff / other.FromCost) * 100
+		} else if other.ToCost > 0 {
+			other.DiffPct = 100
+		}
+		items[rolledUpName] = other
+	}
+	return items
+}
+
+// SortChangesByDelta sorts items by absolute delta descending
+func SortChangesByDelta(items []ChangeItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return math.Abs(items[i].Delta) > math.Abs(items[j].Delta)
+	})
+}
+
+// SortChangesByName sorts items by name alphabetically
+func SortChangesByName(items []ChangeItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+}
+
+// OtherChangeItem sums the items in all that are missing from kept into a
+// single synthetic "Other (N items)" item. It returns false when nothing was
+// left out.
+func OtherChangeItem(all, kept []ChangeItem) (ChangeItem, bool) {
+	keptNames := make(map[string]bool, len(kept))
+	for _, item := range kept {
+		keptNames[item.Name] = true
+	}
+
+	other := ChangeItem{Synthetic: true}
+	var count int
+	for _, item := range all {
+		if keptNames[item.Name] {
+			continue
+		}
+		count++
+		other.OldDiff += item.OldDiff
+		other.NewDiff += item.NewDiff
+		other.Delta += item.Delta
+	}
+	if count == 0 {
+		return ChangeItem{}, false
+	}
+
+	other.Name = otherName(count)
+	return other, true
+}
@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompareTop(t *testing.T) {
+	sep := Period{day(2024, 9, 1), day(2024, 10, 1)}
+	oct := Period{day(2024, 10, 1), day(2024, 11, 1)}
+	from := &TopResult{Period: sep, Total: 1000, Items: []TopItem{
+		{Name: "EC2", Cost: 600},
+		{Name: "S3", Cost: 300},
+		{Name: "Other (2 items)", Cost: 100, Synthetic: true},
+	}}
+	to := &TopResult{Period: oct, Total: 1200, Items: []TopItem{
+		{Name: "EC2", Cost: 800},
+		{Name: "RDS", Cost: 250},
+		{Name: "Other (3 items)", Cost: 150, Synthetic: true},
+	}}
+
+	result := CompareTop(from, to)
+
+	if result.FromTotal != 1000 || result.ToTotal != 1200 || result.TotalDiff != 200 {
+		t.Errorf("totals = %.2f → %.2f (%.2f), want 1000 → 1200 (200)", result.FromTotal, result.ToTotal, result.TotalDiff)
+	}
+	if !result.FromPeriod.Start.Equal(sep.Start) || !result.ToPeriod.Start.Equal(oct.Start) {
+		t.Errorf("periods = %s → %s", result.FromPeriod.Label(nil), result.ToPeriod.Label(nil))
+	}
+
+	items := make(map[string]Item)
+	for _, item := range result.Items {
+		items[item.Name] = item
+	}
+	if other := items["Other"]; other.Diff != 50 || !other.Synthetic {
+		t.Errorf("Other = %+v, want a synthetic +50 item", other)
+	}
+	if !items["RDS"].IsNew || !items["S3"].IsRemoved {
+		t.Errorf("RDS should be new and S3 removed, got %+v and %+v", items["RDS"], items["S3"])
+	}
+	if items["EC2"].Synthetic {
+		t.Error("EC2 should not be synthetic")
+	}
+}
+
+func TestCompareChanges(t *testing.T) {
+	month := func(m time.Month) Period {
+		return Period{day(2024, m, 1), day(2024, m+1, 1)}
+	}
+	older := &Result{
+		FromPeriod: month(time.September), ToPeriod: month(time.October),
+		FromTotal: 1000, ToTotal: 1100, TotalDiff: 100, TotalPct: 10,
+		Items: []Item{
+			{Name: "EC2", Diff: 80, DiffPct: 20},
+			{Name: "S3", Diff: 30, DiffPct: 10},
+			{Name: "Other (2 items)", FromCost: 100, ToCost: 90, Diff: -10, Synthetic: true},
+		},
+	}
+	newer := &Result{
+		FromPeriod: month(time.October), ToPeriod: month(time.November),
+		FromTotal: 1100, ToTotal: 1400, TotalDiff: 300, TotalPct: 27.27,
+		Items: []Item{
+			{Name: "EC2", Diff: 250, DiffPct: 50},
+			{Name: "Lambda", Diff: 40, IsNew: true, DiffPct: 100},
+			{Name: "Other (1 item)", FromCost: 60, ToCost: 70, Diff: 10, Synthetic: true},
+		},
+	}
+
+	result := CompareChanges(older, newer)
+
+	if result.TotalDelta != 200 {
+		t.Errorf("TotalDelta = %.2f, want 200", result.TotalDelta)
+	}
+	if got := result.Old.Label(nil); got != "Sep 2024 → Oct 2024" {
+		t.Errorf("Old.Label() = %q", got)
+	}
+
+	want := []struct {
+		name      string
+		delta     float64
+		onlyInOld bool
+		onlyInNew bool
+	}{
+		{"EC2", 170, false, false},
+		{"Lambda", 40, false, true},
+		{"S3", -30, true, false},
+		{"Other", 20, false, false},
+	}
+	if len(result.Items) != len(want) {
+		t.Fatalf("got %d items, want %d: %+v", len(result.Items), len(want), result.Items)
+	}
+	for i, w := range want {
+		item := result.Items[i]
+		if item.Name != w.name || item.Delta != w.delta || item.OnlyInOld != w.onlyInOld || item.OnlyInNew != w.onlyInNew {
+			t.Errorf("item %d = %+v, want %s with delta %.2f", i, item, w.name, w.delta)
+		}
+	}
+	if !result.Items[3].Synthetic {
+		t.Error("Other should be synthetic")
+	}
+}
+
+func TestOtherChangeItem(t *testing.T) {
+	all := []ChangeItem{
+		{Name: "EC2", OldDiff: 10, NewDiff: 50, Delta: 40},
+		{Name: "S3", OldDiff: 5, NewDiff: 15, Delta: 10},
+		{Name: "RDS", OldDiff: -5, NewDiff: 0, Delta: 5},
+	}
+
+	other, ok := OtherChangeItem(all, all[:1])
+	if !ok {
+		t.Fatal("OtherChangeItem() should roll up the items left out")
+	}
+	if other.Name != "Other (2 items)" || other.Delta != 15 || other.NewDiff != 15 || other.OldDiff != 0 || !other.Synthetic {
+		t.Errorf("OtherChangeItem() = %+v", other)
+	}
+
+	if _, ok := OtherChangeItem(all, all); ok {
+		t.Error("OtherChangeItem() should return false when nothing was left out")
+	}
+}
+
+func TestPeriodJSON_Period(t *testing.T) {
+	oct := Period{day(2024, 10, 1), day(2024, 11, 1)}
+	got, err := oct.ToJSON(nil).Period()
+	if err != nil {
+		t.Fatalf("Period() error = %v", err)
+	}
+	if !got.Start.Equal(oct.Start) || !got.End.Equal(oct.End) {
+		t.Errorf("Period() = %s..%s, want Oct 2024", got.Start, got.End)
+	}
+
+	for _, p := range []PeriodJSON{
+		{Start: "2024-10", End: "2024-11-01"},
+		{Start: "2024-11-01", End: "2024-10-01"},
+	} {
+		if _, err := p.Period(); err == nil {
+			t.Errorf("Period(%+v) should fail", p)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"math"
+	"sort"
+)
+
+// BuildTrend builds a month-by-month trend from one cost map per month.
+// costs[i] holds the grouped costs for months[i]; items are sorted by total cost.
+func BuildTrend(months []Period, costs []map[string]float64) *TrendResult {
+	result := &TrendResult{
+		Months: months,
+		Totals: make([]float64, len(months)),
+		Items:  make([]TrendItem, 0),
+	}
+
+	// Collect each item's series, with zero for months it has no costs
+	series := make(map[string][]float64)
+	for i := range months {
+		for name, cost := range costs[i] {
+			if series[name] == nil {
+				series[name] = make([]float64, len(months))
+			}
+			series[name][i] = cost
+			result.Totals[i] += cost
+		}
+	}
+
+	for name, values := range series {
+		item := TrendItem{Name: name, Costs: values}
+		for _, cost := range values {
+			item.Total += cost
+		}
+
+		first, last := values[0], values[len(values)-1]
+		item.Change = last - first
+		if first == 0 && last > 0 {
+			item.ChangePct = 100 // Treat new costs as 100% increase
+		} else if first > 0 && last == 0 {
+			item.ChangePct = -100 // Treat removed costs as 100% decrease
+		} else if first > 0 {
+			item.ChangePct = (item.Change / first) * 100
+		}
+		item.CAGR = CAGR(first, last, len(values)-1)
+
+		result.Items = append(result.Items, item)
+	}
+
+	sort.Slice(result.Items, func(i, j int) bool {
+		return result.Items[i].Total > result.Items[j].Total
+	})
+
+	return result
+}
+
+// CAGR returns the compound annual growth rate in percent for a cost that went
+// from first to last over the given number of months. Returns nil when growth
+// is undefined: fewer than two months, or a first or last cost that is not positive.
+func CAGR(first, last float64, months int) *float64 {
+	if months < 1 || first <= 0 || last <= 0 {
+		return nil
+	}
+
+	rate := (math.Pow(last/first, 12/float64(months)) - 1) * 100
+	return &rate
+}
+
+// SortTrendByCost sorts items by last month's cost descending
+func SortTrendByCost(items []TrendItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Costs[len(items[i].Costs)-1] > items[j].Costs[len(items[j].Costs)-1]
+	})
+}
+
+// SortTrendByChange sorts items by absolute change descending
+func SortTrendByChange(items []TrendItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return math.Abs(items[i].Change) > math.Abs(items[j].Change)
+	})
+}
+
+// SortTrendByChangePercent sorts items by absolute change percentage descending
+func SortTrendByChangePercent(items []TrendItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return math.Abs(items[i].ChangePct) > math.Abs(items[j].ChangePct)
+	})
+}
+
+// SortTrendByName sorts items by name alphabetically
+func SortTrendByName(items []TrendItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+}
@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func testMonths(n int) []Period {
+	months := make([]Period, n)
+	for i := range months {
+		start := time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+		months[i] = Period{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+	return months
+}
+
+func TestBuildTrend(t *testing.T) {
+	result := BuildTrend(testMonths(3), []map[string]float64{
+		{"EC2": 100, "S3": 10},
+		{"EC2": 110},
+		{"EC2": 121, "Lambda": 5},
+	})
+
+	if len(result.Items) != 3 {
+		t.Fatalf("got %d items, want 3", len(result.Items))
+	}
+
+	// Sorted by total cost
+	ec2 := result.Items[0]
+	if ec2.Name != "EC2" || ec2.Total != 331 {
+		t.Errorf("first item = %s (%v), want EC2 (331)", ec2.Name, ec2.Total)
+	}
+	if ec2.Change != 21 || math.Abs(ec2.ChangePct-21) > 0.001 {
+		t.Errorf("EC2 change = %v (%v%%), want 21 (21%%)", ec2.Change, ec2.ChangePct)
+	}
+	if ec2.CAGR == nil {
+		t.Fatal("EC2 CAGR should be set")
+	}
+
+	// Months without costs are zero
+	s3 := result.Items[1]
+	if s3.Name != "S3" || s3.Costs[1] != 0 || s3.Costs[2] != 0 {
+		t.Errorf("S3 costs = %v, want [10 0 0]", s3.Costs)
+	}
+	if s3.ChangePct != -100 || s3.CAGR != nil {
+		t.Errorf("S3 change = %v%%, CAGR = %v; want -100%%, nil", s3.ChangePct, s3.CAGR)
+	}
+
+	lambda := result.Items[2]
+	if lambda.ChangePct != 100 || lambda.CAGR != nil {
+		t.Errorf("Lambda change = %v%%, CAGR = %v; want 100%%, nil", lambda.ChangePct, lambda.CAGR)
+	}
+
+	wantTotals := []float64{110, 110, 126}
+	for i, want := range wantTotals {
+		if result.Totals[i] != want {
+			t.Errorf("Totals[%d] = %v, want %v", i, result.Totals[i], want)
+		}
+	}
+}
+
+func TestCAGR(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, last float64
+		months      int
+		want        float64
+		wantNil     bool
+	}{
+		{name: "doubled over a year", first: 100, last: 200, months: 12, want: 100},
+		{name: "10% per month", first: 100, last: 121, months: 2, want: (math.Pow(1.1, 12) - 1) * 100},
+		{name: "halved over a year", first: 200, last: 100, months: 12, want: -50},
+		{name: "single month", first: 100, last: 100, months: 0, wantNil: true},
+		{name: "new cost", first: 0, last: 100, months: 11, wantNil: true},
+		{name: "removed cost", first: 100, last: 0, months: 11, wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CAGR(tt.first, tt.last, tt.months)
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("CAGR() = %v, want nil", *got)
+				}
+				return
+			}
+			if got == nil || math.Abs(*got-tt.want) > 0.001 {
+				t.Errorf("CAGR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrendResult_ToLongJSON(t *testing.T) {
+	result := BuildTrend(testMonths(2), []map[string]float64{
+		{"EC2 / us-east-1": 100},
+		{"EC2 / us-east-1": 120},
+	})
+	result.SetGroupBy([]string{"service", "region"})
+
+	long := result.ToLongJSON()
+	if len(long.Records) != 2 {
+		t.Fatalf("got %d records, want 2", len(long.Records))
+	}
+	record := long.Records[1]
+	if record.Month != "2024-02" || record.Cost != 120 || len(record.Keys) != 2 || record.Keys[1] != "us-east-1" {
+		t.Errorf("record = %+v", record)
+	}
+
+	wide := result.ToJSON()
+	if len(wide.Months) != 2 || wide.Months[0].Label != "Jan 2024" {
+		t.Errorf("wide months = %+v", wide.Months)
+	}
+}
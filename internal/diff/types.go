@@ -1,7 +1,12 @@
 package diff
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
 )
 
 // Period represents a time period for cost comparison
@@ -10,59 +15,258 @@ type Period struct {
 	End   time.Time
 }
 
-// Label returns a human-readable label for the period
-func (p Period) Label() string {
-	// If period is exactly one month
-	if p.Start.Day() == 1 && p.End.Day() == 1 {
-		nextMonth := p.Start.AddDate(0, 1, 0)
-		if p.End.Equal(nextMonth) {
+// Label returns a human-readable label for the period: its fiscal label when
+// fiscal calendar c is set and the period is made of fiscal periods, and its
+// calendar label otherwise
+func (p Period) Label(c *FiscalCalendar) string {
+	if label, ok := p.FiscalLabel(c); ok {
+		return label
+	}
+	return p.CalendarLabel()
+}
+
+// FiscalLabel returns the period's label in fiscal calendar c, such as
+// "FY25-Q2". ok is false when c is nil or the period does not line up with
+// fiscal periods.
+func (p Period) FiscalLabel(c *FiscalCalendar) (label string, ok bool) {
+	if c == nil {
+		return "", false
+	}
+	return c.Label(p)
+}
+
+// CalendarLabel returns the period's label in the Gregorian calendar: "Dec
+// 2024" for a month, "Q3 2024" for a quarter, "2024" for a year, "W05 2024"
+// for an ISO week, "Dec 15, 2024" for a day, and the first and last day for
+// anything else
+func (p Period) CalendarLabel() string {
+	if months := p.wholeMonths(); months > 0 {
+		last := p.End.AddDate(0, -1, 0)
+		switch {
+		case months == 1:
 			return p.Start.Format("Jan 2006")
+		case months == 3 && p.Start.Month()%3 == 1:
+			return fmt.Sprintf("Q%d %d", quarter(p.Start), p.Start.Year())
+		case months == 12 && p.Start.Month() == time.January:
+			return p.Start.Format("2006")
+		case p.Start.Year() == last.Year():
+			return p.Start.Format("Jan") + " - " + last.Format("Jan 2006")
+		default:
+			return p.Start.Format("Jan 2006") + " - " + last.Format("Jan 2006")
 		}
 	}
 
+	// If period is an ISO week
+	if p.Start.Weekday() == time.Monday && p.Days() == 7 {
+		year, week := p.Start.ISOWeek()
+		return fmt.Sprintf("W%02d %d", week, year)
+	}
+
 	// If period is a single day
 	if p.End.Sub(p.Start) == 24*time.Hour {
 		return p.Start.Format("Jan 2, 2006")
 	}
 
-	// Generic range
-	return p.Start.Format("Jan 2") + " - " + p.End.AddDate(0, 0, -1).Format("Jan 2, 2006")
+	// Generic range; the start year is spelled out unless the range is short
+	// enough that it can only be the year before, e.g. "Dec 15 - Jan 14, 2025"
+	last := p.End.AddDate(0, 0, -1)
+	if p.Start.Year() != last.Year() && last.Sub(p.Start) > 31*24*time.Hour {
+		return p.Start.Format("Jan 2, 2006") + " - " + last.Format("Jan 2, 2006")
+	}
+	return p.Start.Format("Jan 2") + " - " + last.Format("Jan 2, 2006")
+}
+
+// wholeMonths returns the number of calendar months the period spans when it
+// starts and ends on month boundaries, and 0 otherwise
+func (p Period) wholeMonths() int {
+	if p.Start.Day() != 1 || p.End.Day() != 1 || !p.End.After(p.Start) {
+		return 0
+	}
+	months := (p.End.Year()-p.Start.Year())*12 + int(p.End.Month()) - int(p.Start.Month())
+	if !p.Start.AddDate(0, months, 0).Equal(p.End) {
+		return 0
+	}
+	return months
+}
+
+// Days returns the number of days in the period
+func (p Period) Days() int {
+	return int(p.End.Sub(p.Start).Hours() / 24)
 }
 
 // Item represents a single cost item with comparison data
 type Item struct {
-	Name      string  `json:"name"`
-	FromCost  float64 `json:"from_cost"`
-	ToCost    float64 `json:"to_cost"`
-	Diff      float64 `json:"diff"`
-	DiffPct   float64 `json:"diff_percent"`
-	IsNew     bool    `json:"is_new,omitempty"`
-	IsRemoved bool    `json:"is_removed,omitempty"`
+	Name      string   `json:"name"`
+	Keys      []string `json:"keys,omitempty"`
+	FromCost  float64  `json:"from_cost"`
+	ToCost    float64  `json:"to_cost"`
+	Diff      float64  `json:"diff"`
+	DiffPct   float64  `json:"diff_percent"`
+	IsNew     bool     `json:"is_new,omitempty"`
+	IsRemoved bool     `json:"is_removed,omitempty"`
+	Synthetic bool     `json:"synthetic,omitempty"` // "Other" rollup of items left out
+
+	// Contribution analysis, set by SetContributions. Percentages of the net
+	// change can exceed 100 when other items move the opposite way.
+	ContributionPct float64 `json:"contribution_percent"` // share of the net change
+	CumulativePct   float64 `json:"cumulative_percent"`   // running contribution, largest first
+	FromSharePct    float64 `json:"from_share_percent"`   // share of the from total
+	ToSharePct      float64 `json:"to_share_percent"`     // share of the to total
+	ShareShift      float64 `json:"share_shift"`          // mix shift in percentage points
+
+	// Charges breaks the item down by charge type (RECORD_TYPE), largest change first.
+	// Driver is the charge type responsible for most of the change.
+	Charges []ChargeDiff `json:"charges,omitempty"`
+	Driver  string       `json:"driver,omitempty"`
+
+	// Budget compares ToCost with the item's budget, set by SetBudgets
+	Budget *BudgetStatus `json:"budget,omitempty"`
+}
+
+// ChargeDiff is the change in a single charge type, such as Usage or Credit, within an item
+type ChargeDiff struct {
+	Type     string  `json:"type"`
+	FromCost float64 `json:"from_cost"`
+	ToCost   float64 `json:"to_cost"`
+	Diff     float64 `json:"diff"`
 }
 
 // Result represents the complete comparison result
 type Result struct {
-	FromPeriod Period  `json:"from_period"`
-	ToPeriod   Period  `json:"to_period"`
-	FromTotal  float64 `json:"from_total"`
-	ToTotal    float64 `json:"to_total"`
-	TotalDiff  float64 `json:"total_diff"`
-	TotalPct   float64 `json:"total_diff_percent"`
-	Items      []Item  `json:"items"`
+	FromPeriod Period        `json:"from_period"`
+	ToPeriod   Period        `json:"to_period"`
+	Baseline   []Period      `json:"baseline,omitempty"` // periods averaged into the from costs; FromPeriod only spans them
+	Snapshot   string        `json:"snapshot,omitempty"` // saved snapshot the from costs come from
+	GroupBy    []string      `json:"group_by,omitempty"`
+	PerDay     bool          `json:"per_day,omitempty"`        // costs are per-day run rates
+	Pareto     float64       `json:"pareto_percent,omitempty"` // items limited to those explaining this share of the change
+	FromTotal  float64       `json:"from_total"`
+	ToTotal    float64       `json:"to_total"`
+	TotalDiff  float64       `json:"total_diff"`
+	TotalPct   float64       `json:"total_diff_percent"`
+	Budget     *BudgetStatus `json:"budget,omitempty"` // ToTotal against the overall budget
+	Gate       *Gate         `json:"gate,omitempty"`   // --fail-on outcome
+	Items      []Item        `json:"items"`
+
+	// Fiscal labels the periods; nil for calendar labels
+	Fiscal *FiscalCalendar `json:"-"`
+}
+
+// FromLabel returns the label of the from side: the from period, preceded by
+// the snapshot name for snapshot costs. When costs are the average of baseline
+// periods, it is "Avg" and their span for consecutive periods, such as "Avg Q3
+// 2024", and the list of periods otherwise, such as "Avg of Oct 2022, Oct 2023"
+// for --yoy, whose span would include the months in between.
+func (r *Result) FromLabel() string {
+	if r.Snapshot != "" {
+		return fmt.Sprintf("%s (%s)", r.Snapshot, r.FromPeriod.Label(r.Fiscal))
+	}
+	if len(r.Baseline) > 1 {
+		if consecutive(r.Baseline) {
+			return "Avg " + r.FromPeriod.Label(r.Fiscal)
+		}
+		labels := make([]string, len(r.Baseline))
+		for i, p := range r.Baseline {
+			labels[i] = p.Label(r.Fiscal)
+		}
+		return "Avg of " + strings.Join(labels, ", ")
+	}
+	return r.FromPeriod.Label(r.Fiscal)
+}
+
+// consecutive reports whether each period starts where the previous one ends
+func consecutive(periods []Period) bool {
+	for i := 1; i < len(periods); i++ {
+		if !periods[i].Start.Equal(periods[i-1].End) {
+			return false
+		}
+	}
+	return true
+}
+
+// Gate is the outcome of checking a result against --fail-on conditions
+type Gate struct {
+	Conditions []string    `json:"conditions"`
+	Passed     bool        `json:"passed"`
+	Violations []Violation `json:"violations"`
+}
+
+// Violation is a single condition crossed by the total or by one item
+type Violation struct {
+	Condition string        `json:"condition"`      // as given, e.g. "item>$500"
+	Kind      ConditionKind `json:"kind"`           // total, item or new
+	Name      string        `json:"name,omitempty"` // the offending item, for item and new conditions
+	Value     float64       `json:"value"`          // the value that crossed the threshold
+	Threshold float64       `json:"threshold"`
+	Unit      string        `json:"unit"` // usd or percent
 }
 
 // TopItem represents a single cost item for the top command
 type TopItem struct {
-	Name    string  `json:"name"`
-	Cost    float64 `json:"cost"`
-	Percent float64 `json:"percent"`
+	Name      string   `json:"name"`
+	Keys      []string `json:"keys,omitempty"`
+	Cost      float64  `json:"cost"`
+	Percent   float64  `json:"percent"`
+	Synthetic bool     `json:"synthetic,omitempty"` // "Other" rollup of items left out
+
+	// Budget compares Cost with the item's budget, set by SetBudgets
+	Budget *BudgetStatus `json:"budget,omitempty"`
 }
 
 // TopResult represents the result of the top command
 type TopResult struct {
-	Period Period    `json:"period"`
-	Total  float64   `json:"total"`
-	Items  []TopItem `json:"items"`
+	Period  Period        `json:"period"`
+	GroupBy []string      `json:"group_by,omitempty"`
+	Total   float64       `json:"total"`
+	Budget  *BudgetStatus `json:"budget,omitempty"` // Total against the overall budget
+	Items   []TopItem     `json:"items"`
+
+	// Fiscal labels the periods; nil for calendar labels
+	Fiscal *FiscalCalendar `json:"-"`
+}
+
+// HasCharges reports whether items carry a charge type breakdown
+func (r *Result) HasCharges() bool {
+	for _, item := range r.Items {
+		if item.Charges != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMultiLevel reports whether items are grouped by more than one dimension
+func (r *Result) IsMultiLevel() bool {
+	return len(r.GroupBy) > 1
+}
+
+// SetGroupBy records the grouping dimensions and, for multi-level groupings,
+// splits each item's composite name into its per-dimension keys
+func (r *Result) SetGroupBy(dimensions []string) {
+	r.GroupBy = dimensions
+	if !r.IsMultiLevel() {
+		return
+	}
+	for i := range r.Items {
+		r.Items[i].Keys = groupkey.Split(r.Items[i].Name, len(dimensions))
+	}
+}
+
+// IsMultiLevel reports whether items are grouped by more than one dimension
+func (r *TopResult) IsMultiLevel() bool {
+	return len(r.GroupBy) > 1
+}
+
+// SetGroupBy records the grouping dimensions and, for multi-level groupings,
+// splits each item's composite name into its per-dimension keys
+func (r *TopResult) SetGroupBy(dimensions []string) {
+	r.GroupBy = dimensions
+	if !r.IsMultiLevel() {
+		return
+	}
+	for i := range r.Items {
+		r.Items[i].Keys = groupkey.Split(r.Items[i].Name, len(dimensions))
+	}
 }
 
 // DayItem represents a single day's cost
@@ -71,70 +275,391 @@ type DayItem struct {
 	Cost          float64   `json:"cost"`
 	Change        float64   `json:"change"`
 	ChangePercent float64   `json:"change_percent"`
+
+	// Anomaly detection results, set by DetectAnomalies. Days without
+	// enough history are not scored.
+	Scored   bool     `json:"scored"`
+	Expected float64  `json:"expected"`      // baseline median cost
+	Score    float64  `json:"anomaly_score"` // robust z-score; negative for drops
+	Anomaly  bool     `json:"anomaly"`
+	Severity Severity `json:"severity,omitempty"`
+
+	// Groups is the day's cost per group when the watch is grouped
+	Groups map[string]float64 `json:"groups,omitempty"`
+}
+
+// WatchGroup is one column of a grouped watch
+type WatchGroup struct {
+	Name    string   `json:"name"`
+	Keys    []string `json:"keys,omitempty"`
+	Total   float64  `json:"total"`
+	IsOther bool     `json:"is_other,omitempty"` // groups beyond the limit, summed
 }
 
 // WatchResult represents the result of the watch command
 type WatchResult struct {
-	StartDate time.Time `json:"start_date"`
-	EndDate   time.Time `json:"end_date"`
+	StartDate time.Time    `json:"start_date"`
+	EndDate   time.Time    `json:"end_date"`
+	GroupBy   []string     `json:"group_by,omitempty"`
+	Groups    []WatchGroup `json:"groups,omitempty"` // largest total first
+	Total     float64      `json:"total"`
+	Average   float64      `json:"average"`
+	Anomalies int          `json:"anomalies"` // number of days flagged as anomalous
+	Days      []DayItem    `json:"days"`
+}
+
+// IsGrouped reports whether days are broken down by group
+func (r *WatchResult) IsGrouped() bool {
+	return len(r.Groups) > 0
+}
+
+// IsMultiLevel reports whether groups are grouped by more than one dimension
+func (r *WatchResult) IsMultiLevel() bool {
+	return len(r.GroupBy) > 1
+}
+
+// SetGroups records the grouping dimensions and builds the group columns from
+// the days' per-group costs, largest total first. When more than one group is
+// beyond limit, those are summed into a single "Other" group so that every day
+// still adds up to its total.
+func (r *WatchResult) SetGroups(dimensions []string, limit int) {
+	r.GroupBy = dimensions
+
+	totals := make(map[string]float64)
+	for _, day := range r.Days {
+		for name, cost := range day.Groups {
+			totals[name] += cost
+		}
+	}
+
+	groups := make([]WatchGroup, 0, len(totals))
+	for name, total := range totals {
+		group := WatchGroup{Name: name, Total: total}
+		if r.IsMultiLevel() {
+			group.Keys = groupkey.Split(name, len(dimensions))
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Total != groups[j].Total {
+			return groups[i].Total > groups[j].Total
+		}
+		return groups[i].Name < groups[j].Name
+	})
+
+	if limit > 0 && len(groups) > limit+1 {
+		other := WatchGroup{Name: fmt.Sprintf("Other (%d groups)", len(groups)-limit), IsOther: true}
+		for _, group := range groups[limit:] {
+			other.Total += group.Total
+			for i := range r.Days {
+				if cost, ok := r.Days[i].Groups[group.Name]; ok {
+					r.Days[i].Groups[other.Name] += cost
+					delete(r.Days[i].Groups, group.Name)
+				}
+			}
+		}
+		groups = append(groups[:limit], other)
+	}
+
+	r.Groups = groups
+}
+
+// ForecastMonth is the projected spend for one calendar month
+type ForecastMonth struct {
+	Period   Period  `json:"period"`
+	Actual   float64 `json:"actual"`   // costs already incurred in the month
+	Forecast float64 `json:"forecast"` // predicted costs for the rest of the month
+	Total    float64 `json:"total"`    // Actual + Forecast
+	Lower    float64 `json:"lower"`    // Total at the lower prediction bound
+	Upper    float64 `json:"upper"`    // Total at the upper prediction bound
+	Diff     float64 `json:"diff"`     // Total compared to the baseline month
+	DiffPct  float64 `json:"diff_percent"`
+}
+
+// ForecastResult represents the result of the forecast command
+type ForecastResult struct {
+	Baseline      Period          `json:"baseline"`
+	BaselineTotal float64         `json:"baseline_total"`
+	Interval      int             `json:"interval"` // prediction interval level in percent
+	Months        []ForecastMonth `json:"months"`
+
+	// Fiscal labels the periods; nil for calendar labels
+	Fiscal *FiscalCalendar `json:"-"`
+}
+
+// TrendItem is one group's cost for every month of a trend
+type TrendItem struct {
+	Name      string    `json:"name"`
+	Keys      []string  `json:"keys,omitempty"`
+	Costs     []float64 `json:"costs"` // one per month, oldest first
 	Total     float64   `json:"total"`
-	Average   float64   `json:"average"`
-	Days      []DayItem `json:"days"`
+	Change    float64   `json:"change"` // last month compared to the first
+	ChangePct float64   `json:"change_percent"`
+	CAGR      *float64  `json:"cagr_percent"` // nil when growth is undefined
+}
+
+// TrendResult represents the result of the trend command. With a fiscal
+// calendar, months are fiscal periods.
+type TrendResult struct {
+	Months  []Period    `json:"months"`
+	GroupBy []string    `json:"group_by,omitempty"`
+	Totals  []float64   `json:"totals"` // total cost per month
+	Items   []TrendItem `json:"items"`
+
+	// Fiscal labels the periods; nil for calendar labels
+	Fiscal *FiscalCalendar `json:"-"`
+}
+
+// IsMultiLevel reports whether items are grouped by more than one dimension
+func (r *TrendResult) IsMultiLevel() bool {
+	return len(r.GroupBy) > 1
+}
+
+// IsFiscal reports whether every month of the trend is a fiscal period
+func (r *TrendResult) IsFiscal() bool {
+	for _, m := range r.Months {
+		if _, ok := m.FiscalLabel(r.Fiscal); !ok {
+			return false
+		}
+	}
+	return len(r.Months) > 0
+}
+
+// SetGroupBy records the grouping dimensions and, for multi-level groupings,
+// splits each item's composite name into its per-dimension keys
+func (r *TrendResult) SetGroupBy(dimensions []string) {
+	r.GroupBy = dimensions
+	if !r.IsMultiLevel() {
+		return
+	}
+	for i := range r.Items {
+		r.Items[i].Keys = groupkey.Split(r.Items[i].Name, len(dimensions))
+	}
+}
+
+// ExplainNode is one contributor in an explain tree: the change within a single
+// value of a dimension, split further by the dimension that best explains it
+type ExplainNode struct {
+	Dimension       string  `json:"dimension"`
+	Name            string  `json:"name"`
+	FromCost        float64 `json:"from_cost"`
+	ToCost          float64 `json:"to_cost"`
+	Diff            float64 `json:"diff"`
+	DiffPct         float64 `json:"diff_percent"`
+	ContributionPct float64 `json:"contribution_percent"` // share of the parent's change
+
+	// Scores ranks the dimensions tried for this node, best first.
+	// Children are the largest movers within the best one.
+	Scores   []DimensionScore `json:"scores,omitempty"`
+	Children []ExplainNode    `json:"children,omitempty"`
+}
+
+// DimensionScore rates how well a dimension explains a change
+type DimensionScore struct {
+	Dimension string  `json:"dimension"`
+	Score     float64 `json:"score"`         // largest mover's share of the gross change, in percent
+	Top       string  `json:"top,omitempty"` // the largest mover
+	Values    int     `json:"values"`        // number of distinct values
+}
+
+// ExplainResult represents the result of the explain command
+type ExplainResult struct {
+	FromPeriod Period      `json:"from_period"`
+	ToPeriod   Period      `json:"to_period"`
+	Root       ExplainNode `json:"root"`
+
+	// Fiscal labels the periods; nil for calendar labels
+	Fiscal *FiscalCalendar `json:"-"`
+}
+
+// BudgetState classifies spend against a budget
+type BudgetState string
+
+// Budget states, from best to worst
+const (
+	BudgetOK         BudgetState = "ok"
+	BudgetAtRisk     BudgetState = "at-risk"    // projected to exceed the budget by the end of the period
+	BudgetOver       BudgetState = "over"       // actual spend already exceeds the budget
+	BudgetUnbudgeted BudgetState = "unbudgeted" // spend without a budget
+)
+
+// BudgetStatus compares spend in a period with its budget
+type BudgetStatus struct {
+	Budget      float64     `json:"budget"`
+	Actual      float64     `json:"actual"`
+	Projected   float64     `json:"projected"` // actual extrapolated to the end of the period
+	Variance    float64     `json:"variance"`  // projected minus budget; positive is an overrun
+	VariancePct float64     `json:"variance_percent"`
+	Status      BudgetState `json:"status"`
+}
+
+// BudgetItem is a single group's spend against its budget
+type BudgetItem struct {
+	Name string `json:"name"`
+	BudgetStatus
+}
+
+// BudgetResult represents the result of the budget command
+type BudgetResult struct {
+	Period      Period       `json:"period"`
+	Dimension   string       `json:"dimension"`
+	ElapsedDays int          `json:"elapsed_days"` // complete days of the period so far
+	Total       BudgetStatus `json:"total"`
+	Items       []BudgetItem `json:"items"`
+
+	// Fiscal labels the periods; nil for calendar labels
+	Fiscal *FiscalCalendar `json:"-"`
+}
+
+// ChangeSide summarizes one of the two diff results compared by CompareChanges
+type ChangeSide struct {
+	FromPeriod Period  `json:"from_period"`
+	ToPeriod   Period  `json:"to_period"`
+	FromLabel  string  `json:"from_label"` // the result's FromLabel, e.g. "Avg Q3 2024"
+	FromTotal  float64 `json:"from_total"`
+	ToTotal    float64 `json:"to_total"`
+	TotalDiff  float64 `json:"total_diff"`
+	TotalPct   float64 `json:"total_diff_percent"`
+}
+
+// Label returns the compared periods, e.g. "Sep 2024 → Oct 2024", with the to
+// period labeled in fiscal calendar c
+func (s ChangeSide) Label(c *FiscalCalendar) string {
+	return s.FromLabel + " → " + s.ToPeriod.Label(c)
+}
+
+// ChangeItem compares one group's change in two diff results
+type ChangeItem struct {
+	Name       string   `json:"name"`
+	Keys       []string `json:"keys,omitempty"`
+	OldDiff    float64  `json:"old_diff"`
+	OldDiffPct float64  `json:"old_diff_percent"`
+	NewDiff    float64  `json:"new_diff"`
+	NewDiffPct float64  `json:"new_diff_percent"`
+	Delta      float64  `json:"delta"`                 // NewDiff - OldDiff
+	OnlyInOld  bool     `json:"only_in_old,omitempty"` // not listed in the new result
+	OnlyInNew  bool     `json:"only_in_new,omitempty"` // not listed in the old result
+	Synthetic  bool     `json:"synthetic,omitempty"`   // "Other" rollup
+}
+
+// ChangeResult compares the changes of two diff results, such as last
+// month's report with this month's
+type ChangeResult struct {
+	Old        ChangeSide   `json:"old"`
+	New        ChangeSide   `json:"new"`
+	GroupBy    []string     `json:"group_by,omitempty"`
+	PerDay     bool         `json:"per_day,omitempty"`
+	TotalDelta float64      `json:"total_delta"` // New.TotalDiff - Old.TotalDiff
+	Items      []ChangeItem `json:"items"`
+
+	// Fiscal labels the periods; nil for calendar labels
+	Fiscal *FiscalCalendar `json:"-"`
+}
+
+// IsMultiLevel reports whether items are grouped by more than one dimension
+func (r *ChangeResult) IsMultiLevel() bool {
+	return len(r.GroupBy) > 1
 }
 
 // PeriodJSON is a JSON-friendly representation of Period
 type PeriodJSON struct {
-	Start string `json:"start"`
-	End   string `json:"end"`
-	Label string `json:"label"`
+	Start         string `json:"start"`
+	End           string `json:"end"`
+	Label         string `json:"label"`
+	CalendarLabel string `json:"calendar_label,omitempty"` // set when Label is a fiscal label
 }
 
-// ToJSON converts Period to PeriodJSON
-func (p Period) ToJSON() PeriodJSON {
-	return PeriodJSON{
+// ToJSON converts Period to PeriodJSON, labeled in fiscal calendar c
+func (p Period) ToJSON(c *FiscalCalendar) PeriodJSON {
+	result := PeriodJSON{
 		Start: p.Start.Format("2006-01-02"),
 		End:   p.End.Format("2006-01-02"),
-		Label: p.Label(),
+		Label: p.Label(c),
 	}
+	if _, ok := p.FiscalLabel(c); ok {
+		result.CalendarLabel = p.CalendarLabel()
+	}
+	return result
 }
 
-// ResultJSON is a JSON-friendly representation of Result
+// Period converts PeriodJSON back to Period
+func (p PeriodJSON) Period() (Period, error) {
+	start, err := time.Parse("2006-01-02", p.Start)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid period start %q: %w", p.Start, err)
+	}
+	end, err := time.Parse("2006-01-02", p.End)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid period end %q: %w", p.End, err)
+	}
+	if !end.After(start) {
+		return Period{}, fmt.Errorf("invalid period %s..%s: end must be after start", p.Start, p.End)
+	}
+	return Period{Start: start, End: end}, nil
+}
+
+// ResultJSON is a JSON-friendly representation of Result. When baseline is
+// set, the from costs are the average of its periods and from_period is only
+// their envelope, which for --yoy also covers the months in between.
 type ResultJSON struct {
-	FromPeriod PeriodJSON `json:"from_period"`
-	ToPeriod   PeriodJSON `json:"to_period"`
-	FromTotal  float64    `json:"from_total"`
-	ToTotal    float64    `json:"to_total"`
-	TotalDiff  float64    `json:"total_diff"`
-	TotalPct   float64    `json:"total_diff_percent"`
-	Items      []Item     `json:"items"`
+	FromPeriod PeriodJSON    `json:"from_period"`
+	ToPeriod   PeriodJSON    `json:"to_period"`
+	Baseline   []PeriodJSON  `json:"baseline,omitempty"`
+	Snapshot   string        `json:"snapshot,omitempty"`
+	GroupBy    []string      `json:"group_by,omitempty"`
+	PerDay     bool          `json:"per_day,omitempty"`
+	Pareto     float64       `json:"pareto_percent,omitempty"`
+	FromTotal  float64       `json:"from_total"`
+	ToTotal    float64       `json:"to_total"`
+	TotalDiff  float64       `json:"total_diff"`
+	TotalPct   float64       `json:"total_diff_percent"`
+	Budget     *BudgetStatus `json:"budget,omitempty"`
+	Gate       *Gate         `json:"gate,omitempty"`
+	Items      []Item        `json:"items"`
 }
 
 // ToJSON converts Result to ResultJSON
 func (r *Result) ToJSON() ResultJSON {
+	var baseline []PeriodJSON
+	for _, p := range r.Baseline {
+		baseline = append(baseline, p.ToJSON(r.Fiscal))
+	}
+
 	return ResultJSON{
-		FromPeriod: r.FromPeriod.ToJSON(),
-		ToPeriod:   r.ToPeriod.ToJSON(),
+		FromPeriod: r.FromPeriod.ToJSON(r.Fiscal),
+		ToPeriod:   r.ToPeriod.ToJSON(r.Fiscal),
+		Baseline:   baseline,
+		Snapshot:   r.Snapshot,
+		GroupBy:    r.GroupBy,
+		PerDay:     r.PerDay,
+		Pareto:     r.Pareto,
 		FromTotal:  r.FromTotal,
 		ToTotal:    r.ToTotal,
 		TotalDiff:  r.TotalDiff,
 		TotalPct:   r.TotalPct,
+		Budget:     r.Budget,
+		Gate:       r.Gate,
 		Items:      r.Items,
 	}
 }
 
 // TopResultJSON is a JSON-friendly representation of TopResult
 type TopResultJSON struct {
-	Period PeriodJSON `json:"period"`
-	Total  float64    `json:"total"`
-	Items  []TopItem  `json:"items"`
+	Period  PeriodJSON    `json:"period"`
+	GroupBy []string      `json:"group_by,omitempty"`
+	Total   float64       `json:"total"`
+	Budget  *BudgetStatus `json:"budget,omitempty"`
+	Items   []TopItem     `json:"items"`
 }
 
 // ToJSON converts TopResult to TopResultJSON
 func (r *TopResult) ToJSON() TopResultJSON {
 	return TopResultJSON{
-		Period: r.Period.ToJSON(),
-		Total:  r.Total,
-		Items:  r.Items,
+		Period:  r.Period.ToJSON(r.Fiscal),
+		GroupBy: r.GroupBy,
+		Total:   r.Total,
+		Budget:  r.Budget,
+		Items:   r.Items,
 	}
 }
 
@@ -142,17 +667,36 @@ func (r *TopResult) ToJSON() TopResultJSON {
 type WatchResultJSON struct {
 	StartDate string        `json:"start_date"`
 	EndDate   string        `json:"end_date"`
+	GroupBy   []string      `json:"group_by,omitempty"`
+	Groups    []WatchGroup  `json:"groups,omitempty"`
 	Total     float64       `json:"total"`
 	Average   float64       `json:"average"`
+	Anomalies int           `json:"anomalies"`
 	Days      []DayItemJSON `json:"days"`
+
+	// Breakdown has one entry per day and group when the watch is grouped
+	Breakdown []DayGroupJSON `json:"breakdown,omitempty"`
+}
+
+// DayGroupJSON is a single group's cost on a single day
+type DayGroupJSON struct {
+	Date string   `json:"date"`
+	Name string   `json:"name"`
+	Keys []string `json:"keys,omitempty"`
+	Cost float64  `json:"cost"`
 }
 
 // DayItemJSON is a JSON-friendly representation of DayItem
 type DayItemJSON struct {
-	Date          string  `json:"date"`
-	Cost          float64 `json:"cost"`
-	Change        float64 `json:"change"`
-	ChangePercent float64 `json:"change_percent"`
+	Date          string   `json:"date"`
+	Cost          float64  `json:"cost"`
+	Change        float64  `json:"change"`
+	ChangePercent float64  `json:"change_percent"`
+	Scored        bool     `json:"scored"`
+	Expected      float64  `json:"expected"`
+	Score         float64  `json:"anomaly_score"`
+	Anomaly       bool     `json:"anomaly"`
+	Severity      Severity `json:"severity,omitempty"`
 }
 
 // ToJSON converts WatchResult to WatchResultJSON
@@ -164,14 +708,234 @@ func (r *WatchResult) ToJSON() WatchResultJSON {
 			Cost:          d.Cost,
 			Change:        d.Change,
 			ChangePercent: d.ChangePercent,
+			Scored:        d.Scored,
+			Expected:      d.Expected,
+			Score:         d.Score,
+			Anomaly:       d.Anomaly,
+			Severity:      d.Severity,
+		}
+	}
+
+	var breakdown []DayGroupJSON
+	for _, d := range r.Days {
+		for _, group := range r.Groups {
+			breakdown = append(breakdown, DayGroupJSON{
+				Date: d.Date.Format("2006-01-02"),
+				Name: group.Name,
+				Keys: group.Keys,
+				Cost: d.Groups[group.Name],
+			})
 		}
 	}
 
 	return WatchResultJSON{
 		StartDate: r.StartDate.Format("2006-01-02"),
 		EndDate:   r.EndDate.Format("2006-01-02"),
+		GroupBy:   r.GroupBy,
+		Groups:    r.Groups,
 		Total:     r.Total,
 		Average:   r.Average,
+		Anomalies: r.Anomalies,
 		Days:      days,
+		Breakdown: breakdown,
+	}
+}
+
+// ForecastMonthJSON is a JSON-friendly representation of ForecastMonth
+type ForecastMonthJSON struct {
+	Period   PeriodJSON `json:"period"`
+	Actual   float64    `json:"actual"`
+	Forecast float64    `json:"forecast"`
+	Total    float64    `json:"total"`
+	Lower    float64    `json:"lower"`
+	Upper    float64    `json:"upper"`
+	Diff     float64    `json:"diff"`
+	DiffPct  float64    `json:"diff_percent"`
+}
+
+// ForecastResultJSON is a JSON-friendly representation of ForecastResult
+type ForecastResultJSON struct {
+	Baseline      PeriodJSON          `json:"baseline"`
+	BaselineTotal float64             `json:"baseline_total"`
+	Interval      int                 `json:"interval"`
+	Months        []ForecastMonthJSON `json:"months"`
+}
+
+// ToJSON converts ForecastResult to ForecastResultJSON
+func (r *ForecastResult) ToJSON() ForecastResultJSON {
+	months := make([]ForecastMonthJSON, len(r.Months))
+	for i, m := range r.Months {
+		months[i] = ForecastMonthJSON{
+			Period:   m.Period.ToJSON(r.Fiscal),
+			Actual:   m.Actual,
+			Forecast: m.Forecast,
+			Total:    m.Total,
+			Lower:    m.Lower,
+			Upper:    m.Upper,
+			Diff:     m.Diff,
+			DiffPct:  m.DiffPct,
+		}
+	}
+
+	return ForecastResultJSON{
+		Baseline:      r.Baseline.ToJSON(r.Fiscal),
+		BaselineTotal: r.BaselineTotal,
+		Interval:      r.Interval,
+		Months:        months,
+	}
+}
+
+// TrendResultJSON is a JSON-friendly representation of TrendResult in wide
+// layout: one entry per item with a cost for every month
+type TrendResultJSON struct {
+	Months  []PeriodJSON `json:"months"`
+	GroupBy []string     `json:"group_by,omitempty"`
+	Totals  []float64    `json:"totals"`
+	Items   []TrendItem  `json:"items"`
+}
+
+// ToJSON converts TrendResult to TrendResultJSON
+func (r *TrendResult) ToJSON() TrendResultJSON {
+	months := make([]PeriodJSON, len(r.Months))
+	for i, m := range r.Months {
+		months[i] = m.ToJSON(r.Fiscal)
+	}
+
+	return TrendResultJSON{
+		Months:  months,
+		GroupBy: r.GroupBy,
+		Totals:  r.Totals,
+		Items:   r.Items,
+	}
+}
+
+// TrendRecordJSON is a single item's cost for a single month. Fiscal trends
+// give the fiscal period and its calendar boundaries instead of the month.
+type TrendRecordJSON struct {
+	Month  string   `json:"month,omitempty"`
+	Period string   `json:"period,omitempty"`
+	Start  string   `json:"start,omitempty"`
+	End    string   `json:"end,omitempty"`
+	Name   string   `json:"name"`
+	Keys   []string `json:"keys,omitempty"`
+	Cost   float64  `json:"cost"`
+}
+
+// TrendResultLongJSON is a JSON-friendly representation of TrendResult in long
+// layout: one record per item and month
+type TrendResultLongJSON struct {
+	GroupBy []string          `json:"group_by,omitempty"`
+	Records []TrendRecordJSON `json:"records"`
+}
+
+// ToLongJSON converts TrendResult to TrendResultLongJSON
+func (r *TrendResult) ToLongJSON() TrendResultLongJSON {
+	fiscal := r.IsFiscal()
+	records := make([]TrendRecordJSON, 0, len(r.Items)*len(r.Months))
+	for _, item := range r.Items {
+		for i, m := range r.Months {
+			record := TrendRecordJSON{
+				Name: item.Name,
+				Keys: item.Keys,
+				Cost: item.Costs[i],
+			}
+			if fiscal {
+				record.Period = m.Label(r.Fiscal)
+				record.Start = m.Start.Format("2006-01-02")
+				record.End = m.End.Format("2006-01-02")
+			} else {
+				record.Month = m.Start.Format("2006-01")
+			}
+			records = append(records, record)
+		}
+	}
+
+	return TrendResultLongJSON{
+		GroupBy: r.GroupBy,
+		Records: records,
+	}
+}
+
+// ExplainResultJSON is a JSON-friendly representation of ExplainResult
+type ExplainResultJSON struct {
+	FromPeriod PeriodJSON  `json:"from_period"`
+	ToPeriod   PeriodJSON  `json:"to_period"`
+	Root       ExplainNode `json:"root"`
+}
+
+// ToJSON converts ExplainResult to ExplainResultJSON
+func (r *ExplainResult) ToJSON() ExplainResultJSON {
+	return ExplainResultJSON{
+		FromPeriod: r.FromPeriod.ToJSON(r.Fiscal),
+		ToPeriod:   r.ToPeriod.ToJSON(r.Fiscal),
+		Root:       r.Root,
+	}
+}
+
+// BudgetResultJSON is a JSON-friendly representation of BudgetResult
+type BudgetResultJSON struct {
+	Period      PeriodJSON   `json:"period"`
+	Dimension   string       `json:"dimension"`
+	ElapsedDays int          `json:"elapsed_days"`
+	Days        int          `json:"days"`
+	Total       BudgetStatus `json:"total"`
+	Items       []BudgetItem `json:"items"`
+}
+
+// ToJSON converts BudgetResult to BudgetResultJSON
+func (r *BudgetResult) ToJSON() BudgetResultJSON {
+	return BudgetResultJSON{
+		Period:      r.Period.ToJSON(r.Fiscal),
+		Dimension:   r.Dimension,
+		ElapsedDays: r.ElapsedDays,
+		Days:        r.Period.Days(),
+		Total:       r.Total,
+		Items:       r.Items,
+	}
+}
+
+// ChangeSideJSON is a JSON-friendly representation of ChangeSide
+type ChangeSideJSON struct {
+	FromPeriod PeriodJSON `json:"from_period"`
+	ToPeriod   PeriodJSON `json:"to_period"`
+	Label      string     `json:"label"`
+	FromTotal  float64    `json:"from_total"`
+	ToTotal    float64    `json:"to_total"`
+	TotalDiff  float64    `json:"total_diff"`
+	TotalPct   float64    `json:"total_diff_percent"`
+}
+
+// ToJSON converts ChangeSide to ChangeSideJSON, labeled in fiscal calendar c
+func (s ChangeSide) ToJSON(c *FiscalCalendar) ChangeSideJSON {
+	return ChangeSideJSON{
+		FromPeriod: s.FromPeriod.ToJSON(c),
+		ToPeriod:   s.ToPeriod.ToJSON(c),
+		Label:      s.Label(c),
+		FromTotal:  s.FromTotal,
+		ToTotal:    s.ToTotal,
+		TotalDiff:  s.TotalDiff,
+		TotalPct:   s.TotalPct,
+	}
+}
+
+// ChangeResultJSON is a JSON-friendly representation of ChangeResult
+type ChangeResultJSON struct {
+	Old        ChangeSideJSON `json:"old"`
+	New        ChangeSideJSON `json:"new"`
+	GroupBy    []string       `json:"group_by,omitempty"`
+	PerDay     bool           `json:"per_day,omitempty"`
+	TotalDelta float64        `json:"total_delta"`
+	Items      []ChangeItem   `json:"items"`
+}
+
+// ToJSON converts ChangeResult to ChangeResultJSON
+func (r *ChangeResult) ToJSON() ChangeResultJSON {
+	return ChangeResultJSON{
+		Old:        r.Old.ToJSON(r.Fiscal),
+		New:        r.New.ToJSON(r.Fiscal),
+		GroupBy:    r.GroupBy,
+		PerDay:     r.PerDay,
+		TotalDelta: r.TotalDelta,
+		Items:      r.Items,
 	}
 }
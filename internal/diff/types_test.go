@@ -3,6 +3,8 @@ package diff
 import (
 	"testing"
 	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
 )
 
 func TestPeriod_Label(t *testing.T) {
@@ -47,20 +49,64 @@ func TestPeriod_Label(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.period.Label(); got != tt.want {
+			if got := tt.period.Label(nil); got != tt.want {
 				t.Errorf("Period.Label() = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestResult_FromLabel(t *testing.T) {
+	oct := Period{Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name     string
+		baseline []Period
+		snapshot string
+		want     string
+	}{
+		{"single period", []Period{oct.before(1, nil)}, "", "Sep 2024"},
+		{"consecutive periods", oct.Preceding(3, nil), "", "Avg Q3 2024"},
+		{"yoy periods", []Period{oct.YearsEarlier(3, nil), oct.YearsEarlier(2, nil), oct.YearsEarlier(1, nil)}, "", "Avg of Oct 2021, Oct 2022, Oct 2023"},
+		{"snapshot", []Period{oct.before(1, nil)}, "sep", "sep (Sep 2024)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &Result{FromPeriod: Span(tt.baseline), ToPeriod: oct, Snapshot: tt.snapshot}
+			if len(tt.baseline) > 1 {
+				result.Baseline = tt.baseline
+			}
+			if got := result.FromLabel(); got != tt.want {
+				t.Errorf("FromLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriod_Days(t *testing.T) {
+	tests := []struct {
+		start, end time.Time
+		want       int
+	}{
+		{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 29},
+		{time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 31},
+		{time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 16, 0, 0, 0, 0, time.UTC), 1},
+	}
+
+	for _, tt := range tests {
+		if got := (Period{Start: tt.start, End: tt.end}).Days(); got != tt.want {
+			t.Errorf("Days() for %s = %d, want %d", tt.start.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
 func TestPeriod_ToJSON(t *testing.T) {
 	period := Period{
 		Start: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
 		End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
-	json := period.ToJSON()
+	json := period.ToJSON(nil)
 
 	if json.Start != "2024-12-01" {
 		t.Errorf("Start = %q, want %q", json.Start, "2024-12-01")
@@ -142,8 +188,10 @@ func TestWatchResult_ToJSON(t *testing.T) {
 		Average:   100,
 		Days: []DayItem{
 			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 100, Change: 0, ChangePercent: 0},
-			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 120, Change: 20, ChangePercent: 20},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 120, Change: 20, ChangePercent: 20,
+				Scored: true, Expected: 80, Score: 4.2, Anomaly: true, Severity: SeverityLow},
 		},
+		Anomalies: 1,
 	}
 
 	json := result.ToJSON()
@@ -169,6 +217,12 @@ func TestWatchResult_ToJSON(t *testing.T) {
 	if json.Days[1].Change != 20 {
 		t.Errorf("Days[1].Change = %v, want %v", json.Days[1].Change, 20)
 	}
+	if json.Anomalies != 1 {
+		t.Errorf("Anomalies = %v, want %v", json.Anomalies, 1)
+	}
+	if day := json.Days[1]; !day.Anomaly || day.Severity != SeverityLow || day.Expected != 80 || day.Score != 4.2 {
+		t.Errorf("Days[1] anomaly fields = %+v", day)
+	}
 }
 
 func TestItem_Fields(t *testing.T) {
@@ -215,3 +269,102 @@ func TestDayItem_Fields(t *testing.T) {
 		t.Errorf("Change = %v, want %v", item.Change, 25.50)
 	}
 }
+
+func TestResult_SetGroupBy(t *testing.T) {
+	result := &Result{
+		Items: []Item{
+			{Name: groupkey.Join([]string{"Amazon EC2", "us-east-1"})},
+			{Name: groupkey.Join([]string{"Amazon S3", "Other"})},
+		},
+	}
+
+	result.SetGroupBy([]string{"service", "region"})
+
+	if !result.IsMultiLevel() {
+		t.Fatal("IsMultiLevel() = false, want true")
+	}
+	if result.Items[0].Name != "Amazon EC2 / us-east-1" {
+		t.Errorf("Name = %q, want %q", result.Items[0].Name, "Amazon EC2 / us-east-1")
+	}
+	if got := result.Items[0].Keys; len(got) != 2 || got[0] != "Amazon EC2" || got[1] != "us-east-1" {
+		t.Errorf("Keys = %v, want [Amazon EC2 us-east-1]", got)
+	}
+	if got := result.Items[1].Keys; len(got) != 2 || got[1] != "Other" {
+		t.Errorf("Keys = %v, want [Amazon S3 Other]", got)
+	}
+}
+
+func TestTopResult_SetGroupBy_SingleLevel(t *testing.T) {
+	result := &TopResult{
+		Items: []TopItem{{Name: "Amazon EC2"}},
+	}
+
+	result.SetGroupBy([]string{"service"})
+
+	if result.IsMultiLevel() {
+		t.Error("IsMultiLevel() = true, want false")
+	}
+	if result.Items[0].Keys != nil {
+		t.Errorf("Keys = %v, want nil for single-level grouping", result.Items[0].Keys)
+	}
+}
+
+func testGroupedWatch() *WatchResult {
+	return &WatchResult{
+		Days: []DayItem{
+			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 116, Groups: map[string]float64{"EC2": 100, "S3": 10, "Lambda": 5, "SQS": 1}},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 124, Groups: map[string]float64{"EC2": 110, "S3": 12, "SQS": 2}},
+		},
+	}
+}
+
+func TestWatchResult_SetGroups(t *testing.T) {
+	result := testGroupedWatch()
+	result.SetGroups([]string{"service"}, 2)
+
+	if len(result.Groups) != 3 {
+		t.Fatalf("got %d groups, want 3: %+v", len(result.Groups), result.Groups)
+	}
+	if result.Groups[0].Name != "EC2" || result.Groups[0].Total != 210 || result.Groups[1].Name != "S3" {
+		t.Errorf("groups = %+v, want EC2 then S3", result.Groups)
+	}
+
+	other := result.Groups[2]
+	if !other.IsOther || other.Name != "Other (2 groups)" || other.Total != 8 {
+		t.Errorf("other = %+v, want Other (2 groups) totalling 8", other)
+	}
+	if result.Days[0].Groups[other.Name] != 6 || result.Days[1].Groups[other.Name] != 2 {
+		t.Errorf("other per day = %v, %v; want 6, 2", result.Days[0].Groups[other.Name], result.Days[1].Groups[other.Name])
+	}
+	if _, ok := result.Days[0].Groups["Lambda"]; ok {
+		t.Error("folded groups should be removed from days")
+	}
+
+	// A single group beyond the limit is kept as is
+	result = testGroupedWatch()
+	result.SetGroups([]string{"service"}, 3)
+	if len(result.Groups) != 4 || result.Groups[3].IsOther {
+		t.Errorf("groups = %+v, want all 4 without Other", result.Groups)
+	}
+}
+
+func TestWatchResult_ToJSON_Breakdown(t *testing.T) {
+	result := testGroupedWatch()
+	result.SetGroups([]string{"service"}, 0)
+
+	json := result.ToJSON()
+	if len(json.Breakdown) != 8 {
+		t.Fatalf("got %d breakdown entries, want 8 (2 days x 4 groups)", len(json.Breakdown))
+	}
+	entry := json.Breakdown[4]
+	if entry.Date != "2025-01-02" || entry.Name != "EC2" || entry.Cost != 110 {
+		t.Errorf("Breakdown[4] = %+v", entry)
+	}
+	if missing := json.Breakdown[6]; missing.Name != "Lambda" || missing.Cost != 0 {
+		t.Errorf("Breakdown[6] = %+v, want Lambda with zero cost", missing)
+	}
+
+	if ungrouped := (&WatchResult{Days: result.Days}).ToJSON(); ungrouped.Breakdown != nil {
+		t.Error("ungrouped results should have no breakdown")
+	}
+}
@@ -0,0 +1,140 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenEq
+	tokenNotEq
+)
+
+// token is a lexical unit of a filter expression
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// String describes the token for error messages
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// isKeyword reports whether the token is an unquoted word matching one of the keywords
+func (t token) isKeyword(keywords ...string) bool {
+	if t.kind != tokenWord {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(t.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValue reports whether the token can be used as a comparison value.
+// Keywords must be quoted to be used as values.
+func (t token) isValue() bool {
+	if t.kind == tokenString {
+		return true
+	}
+	return t.kind == tokenWord && !t.isKeyword("and", "or", "not", "in")
+}
+
+// lex splits input into tokens, always ending with a tokenEOF
+func lex(input string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(input); {
+		c := input[i]
+
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+
+		case c == '=':
+			tokens = append(tokens, token{kind: tokenEq, text: "=", pos: i})
+			i++
+
+		case c == '!':
+			if i+1 >= len(input) || input[i+1] != '=' {
+				return nil, &SyntaxError{Input: input, Pos: i, Msg: "expected \"!=\""}
+			}
+			tokens = append(tokens, token{kind: tokenNotEq, text: "!=", pos: i})
+			i += 2
+
+		case c == '"' || c == '\'':
+			text, end, err := lexString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = end
+
+		default:
+			start := i
+			for i < len(input) && isWordByte(input[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: input[start:i], pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// lexString reads a quoted string starting at input[start], returning its
+// unescaped text and the offset just past the closing quote
+func lexString(input string, start int) (string, int, error) {
+	quote := input[start]
+	var sb strings.Builder
+
+	for i := start + 1; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '\\' && i+1 < len(input):
+			i++
+			sb.WriteByte(input[i])
+		case c == quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", 0, &SyntaxError{Input: input, Pos: start, Msg: "unterminated string"}
+}
+
+// isWordByte reports whether c can appear in an unquoted word
+func isWordByte(c byte) bool {
+	if unicode.IsSpace(rune(c)) {
+		return false
+	}
+	return !strings.ContainsRune("()=!,\"'", rune(c))
+}
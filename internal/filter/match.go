@@ -0,0 +1,144 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// Values provides a cost record's dimension and tag values to Match
+type Values interface {
+	// Dimension returns the value of a Cost Explorer dimension such as SERVICE
+	Dimension(key string) string
+	// Tag returns the value of a cost allocation tag, or "" when untagged
+	Tag(key string) string
+}
+
+// Match evaluates an expression against a single record.
+// A nil expression matches everything.
+func Match(expr *types.Expression, values Values) bool {
+	if expr == nil {
+		return true
+	}
+
+	switch {
+	case len(expr.And) > 0:
+		for i := range expr.And {
+			if !Match(&expr.And[i], values) {
+				return false
+			}
+		}
+		return true
+
+	case len(expr.Or) > 0:
+		for i := range expr.Or {
+			if Match(&expr.Or[i], values) {
+				return true
+			}
+		}
+		return false
+
+	case expr.Not != nil:
+		return !Match(expr.Not, values)
+
+	case expr.Dimensions != nil:
+		return slices.Contains(expr.Dimensions.Values, values.Dimension(string(expr.Dimensions.Key)))
+
+	case expr.Tags != nil && expr.Tags.Key != nil:
+		return slices.Contains(expr.Tags.Values, values.Tag(*expr.Tags.Key))
+	}
+
+	return true
+}
+
+// Walk calls fn for every node of the expression tree, stopping at the first error
+func Walk(expr *types.Expression, fn func(*types.Expression) error) error {
+	if expr == nil {
+		return nil
+	}
+	if err := fn(expr); err != nil {
+		return err
+	}
+
+	for i := range expr.And {
+		if err := Walk(&expr.And[i], fn); err != nil {
+			return err
+		}
+	}
+	for i := range expr.Or {
+		if err := Walk(&expr.Or[i], fn); err != nil {
+			return err
+		}
+	}
+	return Walk(expr.Not, fn)
+}
+
+// Format renders an expression back into filter syntax.
+// The output is stable, so it can be used as a cache key.
+func Format(expr *types.Expression) string {
+	if expr == nil {
+		return ""
+	}
+
+	switch {
+	case len(expr.And) > 0:
+		return formatOperands(expr.And, " and ")
+
+	case len(expr.Or) > 0:
+		return formatOperands(expr.Or, " or ")
+
+	case expr.Not != nil:
+		return "not " + formatOperand(expr.Not)
+
+	case expr.Dimensions != nil:
+		return formatLeaf(fieldName(expr.Dimensions.Key), expr.Dimensions.Values)
+
+	case expr.Tags != nil && expr.Tags.Key != nil:
+		return formatLeaf(tagPrefix+*expr.Tags.Key, expr.Tags.Values)
+
+	case expr.CostCategories != nil && expr.CostCategories.Key != nil:
+		return formatLeaf("cost-category:"+*expr.CostCategories.Key, expr.CostCategories.Values)
+	}
+
+	return ""
+}
+
+// formatOperands joins operands, parenthesizing nested and/or groups
+func formatOperands(operands []types.Expression, sep string) string {
+	parts := make([]string, len(operands))
+	for i := range operands {
+		parts[i] = formatOperand(&operands[i])
+	}
+	return strings.Join(parts, sep)
+}
+
+func formatOperand(expr *types.Expression) string {
+	if len(expr.And) > 0 || len(expr.Or) > 0 {
+		return "(" + Format(expr) + ")"
+	}
+	return Format(expr)
+}
+
+func formatLeaf(field string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+
+	if len(quoted) == 1 {
+		return field + "=" + quoted[0]
+	}
+	return field + " in (" + strings.Join(quoted, ",") + ")"
+}
+
+// fieldName returns the filter field name for a dimension key
+func fieldName(dimension types.Dimension) string {
+	for name, dim := range Dimensions {
+		if dim == dimension {
+			return name
+		}
+	}
+	return strings.ToLower(string(dimension))
+}
@@ -0,0 +1,54 @@
+package filter
+
+import (
+	"testing"
+)
+
+// record is a Values implementation backed by maps
+type record struct {
+	dims map[string]string
+	tags map[string]string
+}
+
+func (r record) Dimension(key string) string { return r.dims[key] }
+func (r record) Tag(key string) string       { return r.tags[key] }
+
+func TestMatch(t *testing.T) {
+	ec2 := record{
+		dims: map[string]string{"SERVICE": "Amazon EC2", "REGION": "us-east-1"},
+		tags: map[string]string{"env": "prod"},
+	}
+	tax := record{
+		dims: map[string]string{"SERVICE": "Tax", "REGION": "eu-west-1"},
+	}
+
+	tests := []struct {
+		filter  string
+		wantEC2 bool
+		wantTax bool
+	}{
+		{"", true, true},
+		{"service='Amazon EC2'", true, false},
+		{"region in (us-east-1,eu-west-1) and not service=Tax", true, false},
+		{"tag:env=prod or service=Tax", true, true},
+		{"tag:env=''", false, true},
+		{"tag:env != prod", false, true},
+		{"region not in (us-east-1)", false, true},
+		{"not (service=Tax or region=us-east-1)", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := Parse(tt.filter)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := Match(expr, ec2); got != tt.wantEC2 {
+				t.Errorf("Match(ec2) = %v, want %v", got, tt.wantEC2)
+			}
+			if got := Match(expr, tax); got != tt.wantTax {
+				t.Errorf("Match(tax) = %v, want %v", got, tt.wantTax)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// Dimensions maps filter field names to Cost Explorer dimension keys
+var Dimensions = map[string]types.Dimension{
+	"service":       types.DimensionService,
+	"region":        types.DimensionRegion,
+	"account":       types.DimensionLinkedAccount,
+	"usage-type":    types.DimensionUsageType,
+	"operation":     types.DimensionOperation,
+	"instance-type": types.DimensionInstanceType,
+	"az":            types.DimensionAz,
+	"record-type":   types.DimensionRecordType,
+	"legal-entity":  types.DimensionLegalEntityName,
+}
+
+// tagPrefix marks a field as a cost allocation tag, e.g. tag:env
+const tagPrefix = "tag:"
+
+// SyntaxError reports a problem with a filter expression and where it occurred
+type SyntaxError struct {
+	Input string
+	Pos   int // byte offset of the offending token
+	Msg   string
+}
+
+// Error returns the message followed by the input with a caret under the offending token
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d\n  %s\n  %s^", e.Msg, e.Pos+1, e.Input, strings.Repeat(" ", e.Pos))
+}
+
+// Parse parses a filter expression such as
+//
+//	region in (us-east-1,eu-west-1) and tag:env=prod and not service="Tax"
+//
+// into a Cost Explorer expression tree. Keywords are case-insensitive; "and"
+// binds tighter than "or". An empty input returns a nil expression.
+func Parse(input string) (*types.Expression, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, nil
+	}
+
+	p := &parser{input: input, tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %s", tok))
+	}
+	return expr, nil
+}
+
+// And combines expressions with a logical AND, skipping nil ones
+func And(exprs ...*types.Expression) *types.Expression {
+	var operands []*types.Expression
+	for _, expr := range exprs {
+		if expr != nil {
+			operands = append(operands, expr)
+		}
+	}
+
+	switch len(operands) {
+	case 0:
+		return nil
+	case 1:
+		return operands[0]
+	}
+
+	combined := &types.Expression{}
+	for _, expr := range operands {
+		combined.And = append(combined.And, *expr)
+	}
+	return combined
+}
+
+// Service returns an expression matching a single service, or nil for an empty name
+func Service(name string) *types.Expression {
+	return Dimension(types.DimensionService, name)
+}
+
+// Dimension returns an expression matching a single value of a dimension, or nil
+// for an empty value
+func Dimension(key types.Dimension, value string) *types.Expression {
+	if value == "" {
+		return nil
+	}
+	return &types.Expression{
+		Dimensions: &types.DimensionValues{
+			Key:    key,
+			Values: []string{value},
+		},
+	}
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorAt(tok token, msg string) error {
+	return &SyntaxError{Input: p.input, Pos: tok.pos, Msg: msg}
+}
+
+// parseOr parses: and-expr ("or" and-expr)*
+func (p *parser) parseOr() (*types.Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []types.Expression{*left}
+	for p.peek().isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, *right)
+	}
+
+	if len(operands) == 1 {
+		return left, nil
+	}
+	return &types.Expression{Or: operands}, nil
+}
+
+// parseAnd parses: unary ("and" unary)*
+func (p *parser) parseAnd() (*types.Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []types.Expression{*left}
+	for p.peek().isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, *right)
+	}
+
+	if len(operands) == 1 {
+		return left, nil
+	}
+	return &types.Expression{And: operands}, nil
+}
+
+// parseUnary parses: "not" unary | "(" or-expr ")" | predicate
+func (p *parser) parseUnary() (*types.Expression, error) {
+	tok := p.peek()
+
+	switch {
+	case tok.isKeyword("not"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &types.Expression{Not: operand}, nil
+
+	case tok.kind == tokenLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, fmt.Sprintf("expected \")\" but found %s", closing))
+		}
+		return expr, nil
+	}
+
+	return p.parsePredicate()
+}
+
+// parsePredicate parses: field ("=" value | "!=" value | ["not"] "in" "(" values ")")
+func (p *parser) parsePredicate() (*types.Expression, error) {
+	field := p.next()
+	if field.kind != tokenWord || field.isKeyword("and", "or", "in") {
+		return nil, p.errorAt(field, fmt.Sprintf("expected a field such as service or tag:env but found %s", field))
+	}
+
+	leaf, err := p.leafFor(field)
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.next()
+	negate := false
+	switch {
+	case op.kind == tokenEq || op.kind == tokenNotEq:
+		value := p.next()
+		if !value.isValue() {
+			return nil, p.errorAt(value, fmt.Sprintf("expected a value after %s but found %s", op, value))
+		}
+		leaf.values = []string{value.text}
+		negate = op.kind == tokenNotEq
+
+	case op.isKeyword("not") || op.isKeyword("in"):
+		if op.isKeyword("not") {
+			negate = true
+			if in := p.next(); !in.isKeyword("in") {
+				return nil, p.errorAt(in, fmt.Sprintf("expected \"in\" after \"not\" but found %s", in))
+			}
+		}
+		values, err := p.parseValueList()
+		if err != nil {
+			return nil, err
+		}
+		leaf.values = values
+
+	default:
+		return nil, p.errorAt(op, fmt.Sprintf("expected =, != or in after %q but found %s", field.text, op))
+	}
+
+	expr := leaf.expression()
+	if negate {
+		return &types.Expression{Not: expr}, nil
+	}
+	return expr, nil
+}
+
+// parseValueList parses: "(" value ("," value)* ")"
+func (p *parser) parseValueList() ([]string, error) {
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, p.errorAt(open, fmt.Sprintf("expected \"(\" after \"in\" but found %s", open))
+	}
+
+	var values []string
+	for {
+		value := p.next()
+		if !value.isValue() {
+			return nil, p.errorAt(value, fmt.Sprintf("expected a value but found %s", value))
+		}
+		values = append(values, value.text)
+
+		sep := p.next()
+		switch sep.kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return values, nil
+		default:
+			return nil, p.errorAt(sep, fmt.Sprintf("expected \",\" or \")\" but found %s", sep))
+		}
+	}
+}
+
+// leaf is a single dimension or tag comparison being built
+type leaf struct {
+	dimension types.Dimension
+	tag       string
+	values    []string
+}
+
+func (p *parser) leafFor(field token) (*leaf, error) {
+	name := strings.ToLower(field.text)
+	if strings.HasPrefix(name, tagPrefix) {
+		// Tag keys are case-sensitive, only the prefix is not
+		tag := field.text[len(tagPrefix):]
+		if tag == "" {
+			return nil, p.errorAt(field, "tag key is empty")
+		}
+		return &leaf{tag: tag}, nil
+	}
+
+	dimension, ok := Dimensions[name]
+	if !ok {
+		return nil, p.errorAt(field, fmt.Sprintf("unknown field %q (must be %s or tag:<key>)", field.text, fieldNames()))
+	}
+	return &leaf{dimension: dimension}, nil
+}
+
+func (l *leaf) expression() *types.Expression {
+	if l.tag != "" {
+		return &types.Expression{
+			Tags: &types.TagValues{
+				Key:    &l.tag,
+				Values: l.values,
+			},
+		}
+	}
+	return &types.Expression{
+		Dimensions: &types.DimensionValues{
+			Key:    l.dimension,
+			Values: l.values,
+		},
+	}
+}
+
+// fieldNames lists the supported dimension field names in a stable order
+func fieldNames() string {
+	return "service|region|account|usage-type|operation|instance-type|az|record-type|legal-entity"
+}
@@ -0,0 +1,162 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+func TestParse(t *testing.T) {
+	expr, err := Parse(`region in (us-east-1,eu-west-1) and tag:env=prod and not service="Tax"`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(expr.And) != 3 {
+		t.Fatalf("got %d And operands, want 3", len(expr.And))
+	}
+
+	region := expr.And[0].Dimensions
+	if region == nil || region.Key != types.DimensionRegion {
+		t.Fatalf("operand 0 = %+v, want REGION dimension", expr.And[0])
+	}
+	if len(region.Values) != 2 || region.Values[0] != "us-east-1" || region.Values[1] != "eu-west-1" {
+		t.Errorf("region values = %v, want [us-east-1 eu-west-1]", region.Values)
+	}
+
+	tag := expr.And[1].Tags
+	if tag == nil || *tag.Key != "env" || len(tag.Values) != 1 || tag.Values[0] != "prod" {
+		t.Errorf("operand 1 = %+v, want tag env=prod", expr.And[1])
+	}
+
+	not := expr.And[2].Not
+	if not == nil || not.Dimensions == nil || not.Dimensions.Key != types.DimensionService || not.Dimensions.Values[0] != "Tax" {
+		t.Errorf("operand 2 = %+v, want not service=Tax", expr.And[2])
+	}
+}
+
+func TestParse_Format(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"service = 'Amazon EC2'", `service="Amazon EC2"`},
+		{"SERVICE=EC2 AND Region=us-east-1", `service="EC2" and region="us-east-1"`},
+		{"service=A or service=B and region=x", `service="A" or (service="B" and region="x")`},
+		{"(service=A or service=B) and region=x", `(service="A" or service="B") and region="x"`},
+		{"account != 123", `not account="123"`},
+		{"usage-type not in (a, b)", `not usage-type in ("a","b")`},
+		{"not (tag:team=web or tag:Team=api)", `not (tag:team="web" or tag:Team="api")`},
+		{`service="and"`, `service="and"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := Format(expr); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+
+			// Formatted output parses back to the same expression
+			again, err := Parse(Format(expr))
+			if err != nil {
+				t.Fatalf("Parse(Format()) error = %v", err)
+			}
+			if got := Format(again); got != tt.want {
+				t.Errorf("round trip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantPos int
+		wantMsg string
+	}{
+		{"region in us-east-1", 10, `expected "(" after "in"`},
+		{"region in (us-east-1", 20, `expected "," or ")"`},
+		{"bogus=1", 0, `unknown field "bogus"`},
+		{"service = and", 10, "expected a value"},
+		{"service=A and", 13, "expected a field"},
+		{"service=A region=B", 10, `unexpected "region"`},
+		{"service ~ A", 8, "expected =, != or in"},
+		{"service ! A", 8, "expected \"!=\""},
+		{"service=A and (region=x", 23, `expected ")"`},
+		{`service="Amazon EC2`, 8, "unterminated string"},
+		{"tag:=x", 0, "tag key is empty"},
+		{"service not A", 12, `expected "in" after "not"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("error = %T, want *SyntaxError", err)
+			}
+			if syntaxErr.Pos != tt.wantPos {
+				t.Errorf("Pos = %d, want %d (%v)", syntaxErr.Pos, tt.wantPos, err)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.wantMsg) {
+				t.Errorf("Msg = %q, want it to contain %q", syntaxErr.Msg, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestSyntaxError_Caret(t *testing.T) {
+	_, err := Parse("service = and")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %q", len(lines), err.Error())
+	}
+	if lines[1] != "  service = and" {
+		t.Errorf("input line = %q", lines[1])
+	}
+	if lines[2] != "            ^" {
+		t.Errorf("caret line = %q, want caret under \"and\"", lines[2])
+	}
+}
+
+func TestAnd(t *testing.T) {
+	if And(nil, nil) != nil {
+		t.Error("And(nil, nil) should be nil")
+	}
+
+	service := Service("Amazon EC2")
+	if got := And(nil, service); got != service {
+		t.Errorf("And(nil, x) = %+v, want x", got)
+	}
+
+	region, _ := Parse("region=us-east-1")
+	if got := Format(And(service, region)); got != `service="Amazon EC2" and region="us-east-1"` {
+		t.Errorf("And() = %q", got)
+	}
+
+	if Service("") != nil {
+		t.Error("Service(\"\") should be nil")
+	}
+
+	if got := Format(Dimension(types.DimensionOperation, "RunInstances")); got != `operation="RunInstances"` {
+		t.Errorf("Dimension() = %q", got)
+	}
+	if Dimension(types.DimensionRegion, "") != nil {
+		t.Error("Dimension() with an empty value should be nil")
+	}
+}
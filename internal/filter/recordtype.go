@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// RecordTypes maps short charge type names to Cost Explorer RECORD_TYPE values
+var RecordTypes = map[string]string{
+	"usage":            "Usage",
+	"credit":           "Credit",
+	"refund":           "Refund",
+	"tax":              "Tax",
+	"support":          "Support fee",
+	"discounted-usage": "DiscountedUsage",
+	"ri-fee":           "Recurring reservation fee",
+	"ri-upfront":       "Upfront reservation fee",
+	"sp-covered":       "Savings Plan Covered Usage",
+	"sp-negation":      "Savings Plan Negation",
+	"sp-fee":           "Savings Plan Recurring Fee",
+	"sp-upfront":       "Savings Plan Upfront Fee",
+	"edp-discount":     "Enterprise Discount Program Discount",
+}
+
+// ExcludeRecordTypes returns an expression excluding the given charge types.
+// Names may be short names from RecordTypes or exact RECORD_TYPE values.
+// Returns nil when no names are given.
+func ExcludeRecordTypes(names []string) (*types.Expression, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		value, err := recordType(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return &types.Expression{
+		Not: &types.Expression{
+			Dimensions: &types.DimensionValues{
+				Key:    types.DimensionRecordType,
+				Values: values,
+			},
+		},
+	}, nil
+}
+
+// recordType resolves a short name or exact value to a RECORD_TYPE value
+func recordType(name string) (string, error) {
+	if value, ok := RecordTypes[strings.ToLower(name)]; ok {
+		return value, nil
+	}
+	for _, value := range RecordTypes {
+		if strings.EqualFold(value, name) {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unknown record type: %s (must be usage|credit|refund|tax|support|discounted-usage|ri-fee|ri-upfront|sp-covered|sp-negation|sp-fee|sp-upfront|edp-discount)", name)
+}
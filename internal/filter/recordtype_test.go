@@ -0,0 +1,25 @@
+package filter
+
+import (
+	"testing"
+)
+
+func TestExcludeRecordTypes(t *testing.T) {
+	expr, err := ExcludeRecordTypes(nil)
+	if err != nil || expr != nil {
+		t.Errorf("ExcludeRecordTypes(nil) = %v, %v; want nil, nil", expr, err)
+	}
+
+	expr, err = ExcludeRecordTypes([]string{"credit", " Refund", "Savings Plan Negation"})
+	if err != nil {
+		t.Fatalf("ExcludeRecordTypes() error = %v", err)
+	}
+	want := `not record-type in ("Credit","Refund","Savings Plan Negation")`
+	if got := Format(expr); got != want {
+		t.Errorf("ExcludeRecordTypes() = %q, want %q", got, want)
+	}
+
+	if _, err := ExcludeRecordTypes([]string{"credits"}); err == nil {
+		t.Error("expected error for unknown record type")
+	}
+}
@@ -0,0 +1,135 @@
+// Package fiscal loads a fiscal calendar definition from a YAML or JSON file
+package fiscal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/fsutil"
+)
+
+// Period patterns besides the week-based ones such as 4-4-5
+const patternMonthly = "monthly"
+
+// Fiscal year naming
+const (
+	yearNameEnd   = "end"
+	yearNameStart = "start"
+)
+
+// Config describes a fiscal calendar, e.g. a retail 4-4-5 year starting in February:
+//
+//	start_month: 2     # month the fiscal year starts in
+//	pattern: 4-4-5     # weeks per period in each quarter, or monthly (default)
+//	week_start: sunday # weekday fiscal weeks start on (default sunday)
+//	year_name: end     # FY25 ends in 2025 (default) or starts in it (start)
+//
+// JSON is valid YAML, so the same structure can be written as JSON.
+type Config struct {
+	StartMonth int    `yaml:"start_month"`
+	Pattern    string `yaml:"pattern"`
+	WeekStart  string `yaml:"week_start"`
+	YearName   string `yaml:"year_name"`
+}
+
+// DefaultPath returns the fiscal calendar file used when --fiscal is not
+// given, honoring COSTDIFF_FISCAL
+func DefaultPath() (string, error) {
+	return fsutil.ConfigDir("COSTDIFF_FISCAL", "fiscal.yaml")
+}
+
+// Load reads a fiscal calendar file
+func Load(path string) (*diff.FiscalCalendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fiscal calendar file: %w", err)
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse fiscal calendar file %s: %w", path, err)
+	}
+
+	calendar, err := config.Calendar()
+	if err != nil {
+		return nil, fmt.Errorf("invalid fiscal calendar file %s: %w", path, err)
+	}
+	return calendar, nil
+}
+
+// LoadDefault loads the fiscal calendar file at DefaultPath, returning a nil
+// calendar when it does not exist
+func LoadDefault() (*diff.FiscalCalendar, string, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	calendar, err := Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, path, nil
+	}
+	return calendar, path, err
+}
+
+// Calendar validates the configuration and builds the calendar it describes
+func (c Config) Calendar() (*diff.FiscalCalendar, error) {
+	calendar := &diff.FiscalCalendar{StartMonth: time.Month(c.StartMonth)}
+	if c.StartMonth == 0 {
+		return nil, fmt.Errorf("start_month is required")
+	}
+
+	if pattern := strings.ToLower(c.Pattern); pattern != "" && pattern != patternMonthly {
+		for _, part := range strings.Split(pattern, "-") {
+			weeks, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q (must be monthly or weeks per period, e.g. 4-4-5)", c.Pattern)
+			}
+			calendar.Weeks = append(calendar.Weeks, weeks)
+		}
+	}
+
+	weekStart, err := parseWeekday(c.WeekStart)
+	if err != nil {
+		return nil, err
+	}
+	calendar.WeekStart = weekStart
+
+	switch strings.ToLower(c.YearName) {
+	case "", yearNameEnd:
+	case yearNameStart:
+		calendar.NamedByStart = true
+	default:
+		return nil, fmt.Errorf("invalid year_name %q (must be %s|%s)", c.YearName, yearNameEnd, yearNameStart)
+	}
+
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// parseWeekday parses a weekday name such as "sunday" or "Mon", defaulting to Sunday
+func parseWeekday(s string) (time.Weekday, error) {
+	if s == "" {
+		return time.Sunday, nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid week_start %q (must be a weekday, e.g. sunday)", s)
+}
@@ -0,0 +1,83 @@
+package fiscal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeCalendar(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write fiscal calendar: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	yamlPath := writeCalendar(t, "fiscal.yaml", `
+start_month: 2
+pattern: 4-4-5
+week_start: sunday
+`)
+	jsonPath := writeCalendar(t, "fiscal.json", `{"start_month": 2, "pattern": "4-4-5", "week_start": "Sun"}`)
+
+	for _, path := range []string{yamlPath, jsonPath} {
+		calendar, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", filepath.Base(path), err)
+		}
+		if calendar.StartMonth != time.February || calendar.WeekStart != time.Sunday || calendar.NamedByStart {
+			t.Errorf("%s: calendar = %+v", filepath.Base(path), calendar)
+		}
+		if len(calendar.Weeks) != 3 || calendar.Weeks[2] != 5 {
+			t.Errorf("%s: Weeks = %v, want [4 4 5]", filepath.Base(path), calendar.Weeks)
+		}
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	calendar, err := Load(writeCalendar(t, "fiscal.yaml", "start_month: 10\nyear_name: start\n"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if calendar.Weeks != nil || calendar.WeekStart != time.Sunday || !calendar.NamedByStart {
+		t.Errorf("calendar = %+v, want monthly periods named by their start year", calendar)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty", ``, "start_month is required"},
+		{"month out of range", "start_month: 13\n", "start month must be between 1 and 12"},
+		{"bad pattern", "start_month: 2\npattern: weekly\n", `invalid pattern "weekly"`},
+		{"not 13 weeks", "start_month: 2\npattern: 4-4-4\n", "13 weeks"},
+		{"bad weekday", "start_month: 2\nweek_start: someday\n", `invalid week_start "someday"`},
+		{"bad year name", "start_month: 2\nyear_name: middle\n", `invalid year_name "middle"`},
+		{"unknown field", "start: 2\n", "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeCalendar(t, "fiscal.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadDefault_Missing(t *testing.T) {
+	t.Setenv("COSTDIFF_FISCAL", filepath.Join(t.TempDir(), "missing.yaml"))
+	calendar, _, err := LoadDefault()
+	if calendar != nil || err != nil {
+		t.Errorf("LoadDefault() = %+v, %v, want no calendar and no error", calendar, err)
+	}
+}
@@ -0,0 +1,48 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigDir(t *testing.T) {
+	t.Setenv("COSTDIFF_TEST_DIR", "/tmp/elsewhere")
+	if dir, err := ConfigDir("COSTDIFF_TEST_DIR", "things"); err != nil || dir != "/tmp/elsewhere" {
+		t.Errorf("ConfigDir() = %q, %v, want the environment override", dir, err)
+	}
+
+	t.Setenv("COSTDIFF_TEST_DIR", "")
+	dir, err := ConfigDir("COSTDIFF_TEST_DIR", "things")
+	if err != nil {
+		t.Skipf("no user config directory: %v", err)
+	}
+	if filepath.Base(dir) != "things" || filepath.Base(filepath.Dir(dir)) != "costdiff" {
+		t.Errorf("ConfigDir() = %q, want costdiff/things in the user config directory", dir)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "record.json")
+
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(content)); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != content {
+			t.Errorf("file = %q, %v, want %q", data, err, content)
+		}
+	}
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want 1", len(entries))
+	}
+
+	if err := WriteFile(filepath.Join(dir, "missing", "record.json"), []byte("x")); err == nil {
+		t.Error("expected error for a missing directory")
+	}
+}
@@ -0,0 +1,18 @@
+// Package groupkey builds and splits the composite group keys used when costs
+// are grouped by two dimensions, e.g. "Amazon EC2 / us-east-1"
+package groupkey
+
+import "strings"
+
+// Separator joins the parts of a composite group key
+const Separator = " / "
+
+// Join builds a composite group key from its parts
+func Join(parts []string) string {
+	return strings.Join(parts, Separator)
+}
+
+// Split splits a composite group key into at most n parts
+func Split(name string, n int) []string {
+	return strings.SplitN(name, Separator, n)
+}
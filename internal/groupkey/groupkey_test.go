@@ -0,0 +1,10 @@
+package groupkey
+
+import "testing"
+
+func TestSplit_SeparatorInLastPart(t *testing.T) {
+	got := Split("Amazon EC2 / team / a", 2)
+	if len(got) != 2 || got[0] != "Amazon EC2" || got[1] != "team / a" {
+		t.Errorf("Split() = %v, want [Amazon EC2 team / a]", got)
+	}
+}
@@ -0,0 +1,299 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+// RetentionMonths is how many months before the current one Cost Explorer
+// is sure to return. Anything older is read from the store.
+const RetentionMonths = 13
+
+// ErrNotStored is returned when a period beyond Cost Explorer's retention has
+// not been backfilled and Cost Explorer no longer returns it
+var ErrNotStored = errors.New("costs are not in the local history")
+
+// Ensure Fetcher implements aws.CostFetcher
+var _ aws.CostFetcher = (*Fetcher)(nil)
+
+// Fetcher wraps a CostFetcher and serves the parts of queries that fall before
+// Cost Explorer's retention horizon from a Store. Queries after the horizon
+// go to the wrapped fetcher unchanged, and so do months before it that were
+// not stored, since accounts may keep costs for longer than the horizon.
+type Fetcher struct {
+	inner   aws.CostFetcher
+	store   *Store
+	profile string
+	logger  aws.Logger
+	now     func() time.Time
+}
+
+// NewFetcher creates a history wrapper around inner.
+// profile scopes series so different AWS accounts never share costs.
+func NewFetcher(inner aws.CostFetcher, store *Store, profile string) *Fetcher {
+	return &Fetcher{
+		inner:   inner,
+		store:   store,
+		profile: profile,
+		logger:  noopLogger{},
+		now:     time.Now,
+	}
+}
+
+// Horizon returns the first day Cost Explorer still returns costs for at now
+func Horizon(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -RetentionMonths, 0)
+}
+
+// SetLogger sets the logger for the fetcher and the wrapped client
+func (f *Fetcher) SetLogger(logger aws.Logger) {
+	if logger != nil {
+		f.logger = logger
+	}
+	f.inner.SetLogger(logger)
+}
+
+// GetCosts returns grouped costs, summing stored costs before the horizon
+// with fetched costs after it
+func (f *Fetcher) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
+	horizon := Horizon(f.now())
+	if !start.Before(horizon) {
+		return f.inner.GetCosts(ctx, start, end, groupBy, metric, filter)
+	}
+
+	series := NewSeries(f.profile, metric, groupBy, filter)
+	costs := make(map[string]float64)
+	for _, month := range monthsIn(start, minTime(end, horizon)) {
+		monthCosts, found, err := f.storedCosts(series, month)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			if monthCosts, err = f.inner.GetCosts(ctx, month.start, month.end, groupBy, metric, filter); err != nil {
+				return nil, f.notStored(series, month, err)
+			}
+		}
+		for name, cost := range monthCosts {
+			costs[name] += cost
+		}
+	}
+
+	if end.After(horizon) {
+		live, err := f.inner.GetCosts(ctx, horizon, end, groupBy, metric, filter)
+		if err != nil {
+			return nil, err
+		}
+		for name, cost := range live {
+			costs[name] += cost
+		}
+	}
+	return costs, nil
+}
+
+// GetMonthlyCosts returns per-month costs, stored before the horizon and
+// fetched after it
+func (f *Fetcher) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.MonthlyCosts, error) {
+	horizon := Horizon(f.now())
+	if !start.Before(horizon) {
+		return f.inner.GetMonthlyCosts(ctx, start, end, groupBy, metric, filter)
+	}
+
+	series := NewSeries(f.profile, metric, groupBy, filter)
+	var monthlyCosts []aws.MonthlyCosts
+	for _, month := range monthsIn(start, minTime(end, horizon)) {
+		costs, found, err := f.storedCosts(series, month)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			fetched, err := f.inner.GetMonthlyCosts(ctx, month.start, month.end, groupBy, metric, filter)
+			if err != nil {
+				return nil, f.notStored(series, month, err)
+			}
+			monthlyCosts = append(monthlyCosts, fetched...)
+			continue
+		}
+		monthlyCosts = append(monthlyCosts, aws.MonthlyCosts{Month: month.start, Costs: costs})
+	}
+
+	if end.After(horizon) {
+		live, err := f.inner.GetMonthlyCosts(ctx, horizon, end, groupBy, metric, filter)
+		if err != nil {
+			return nil, err
+		}
+		monthlyCosts = append(monthlyCosts, live...)
+	}
+	return monthlyCosts, nil
+}
+
+// GetDailyCosts returns daily costs, stored before the horizon and fetched
+// after it. Daily totals can be served by a series of any grouping.
+func (f *Fetcher) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.DailyCost, error) {
+	horizon := Horizon(f.now())
+	if !start.Before(horizon) {
+		return f.inner.GetDailyCosts(ctx, start, end, groupBy, metric, filter)
+	}
+
+	series := NewSeries(f.profile, metric, groupBy, filter)
+	var dailyCosts []aws.DailyCost
+	for _, month := range monthsIn(start, minTime(end, horizon)) {
+		days, found, err := f.storedDays(series, month)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			fetched, err := f.inner.GetDailyCosts(ctx, month.start, month.end, groupBy, metric, filter)
+			if err != nil {
+				return nil, f.notStored(series, month, err)
+			}
+			dailyCosts = append(dailyCosts, fetched...)
+			continue
+		}
+		for _, day := range days {
+			if !day.Date.Before(month.start) && day.Date.Before(month.end) {
+				if len(groupBy) == 0 {
+					day.Groups = nil
+				}
+				dailyCosts = append(dailyCosts, day)
+			}
+		}
+	}
+
+	if end.After(horizon) {
+		live, err := f.inner.GetDailyCosts(ctx, horizon, end, groupBy, metric, filter)
+		if err != nil {
+			return nil, err
+		}
+		dailyCosts = append(dailyCosts, live...)
+	}
+	return dailyCosts, nil
+}
+
+// storedCosts returns the stored costs of the part of a month: the monthly
+// record for a whole month, or the sum of the daily records otherwise.
+// found is false when neither is stored.
+func (f *Fetcher) storedCosts(series Series, month monthSpan) (map[string]float64, bool, error) {
+	if month.whole() {
+		costs, found, err := f.store.Monthly(series, month.month)
+		if err != nil {
+			return nil, false, err
+		}
+		if found {
+			f.logger.Debugf("history: %s from the monthly record", month.month.Format(monthFormat))
+			return costs, true, nil
+		}
+	}
+
+	days, found, err := f.store.Daily(series, month.month)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		f.logger.Debugf("history: %s is not stored, fetching it", month.month.Format(monthFormat))
+		return nil, false, nil
+	}
+
+	f.logger.Debugf("history: %s from the daily record", month.month.Format(monthFormat))
+	costs := make(map[string]float64)
+	for _, day := range days {
+		if day.Date.Before(month.start) || !day.Date.Before(month.end) {
+			continue
+		}
+		for name, cost := range day.Groups {
+			costs[name] += cost
+		}
+	}
+	return costs, true, nil
+}
+
+// storedDays returns the stored daily costs of a month. Without grouping, the
+// daily totals of any series with the same metric and filter will do.
+// found is false when none are stored.
+func (f *Fetcher) storedDays(series Series, month monthSpan) ([]aws.DailyCost, bool, error) {
+	days, found, err := f.store.Daily(series, month.month)
+	if err != nil || found {
+		return days, found, err
+	}
+
+	if series.GroupBy == "" {
+		coverages, err := f.store.List()
+		if err != nil {
+			return nil, false, err
+		}
+		for _, coverage := range coverages {
+			other := coverage.Series
+			if other.Profile != series.Profile || other.Metric != series.Metric || other.Filter != series.Filter {
+				continue
+			}
+			days, found, err := f.store.Daily(other, month.month)
+			if err != nil || found {
+				return days, found, err
+			}
+		}
+	}
+
+	f.logger.Debugf("history: %s is not stored, fetching it", month.month.Format(monthFormat))
+	return nil, false, nil
+}
+
+// notStored explains which backfill is missing, after fetching the month
+// failed with err
+func (f *Fetcher) notStored(series Series, month monthSpan, err error) error {
+	grouping := series.GroupBy
+	if grouping == "" {
+		grouping = "no grouping"
+	}
+	return fmt.Errorf("%w: %s (%s, %s) is older than Cost Explorer's %d months and fetching it failed (%v); run costdiff backfill with the same flags to store it",
+		ErrNotStored, month.month.Format("Jan 2006"), series.Metric, grouping, RetentionMonths, err)
+}
+
+// monthSpan is the part of one calendar month covered by a query
+type monthSpan struct {
+	month      time.Time // first day of the month
+	start, end time.Time // covered days, end exclusive
+}
+
+// whole reports whether the span covers the entire month
+func (m monthSpan) whole() bool {
+	return m.start.Equal(m.month) && m.end.Equal(m.month.AddDate(0, 1, 0))
+}
+
+// monthsIn splits [start, end) into the parts of each calendar month it covers
+func monthsIn(start, end time.Time) []monthSpan {
+	var spans []monthSpan
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month.Before(end) {
+		next := month.AddDate(0, 1, 0)
+		span := monthSpan{month: month, start: month, end: next}
+		if start.After(span.start) {
+			span.start = start
+		}
+		if end.Before(span.end) {
+			span.end = end
+		}
+		spans = append(spans, span)
+		month = next
+	}
+	return spans
+}
+
+// minTime returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// noopLogger is a logger that does nothing
+type noopLogger struct{}
+
+func (noopLogger) Debugf(format string, args ...interface{}) {}
+func (noopLogger) Warnf(format string, args ...interface{})  {}
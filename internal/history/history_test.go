@@ -0,0 +1,269 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+)
+
+// rangeFetcher returns fixed data, or err when set, and records the ranges it
+// is asked for
+type rangeFetcher struct {
+	ranges [][2]time.Time
+	err    error
+}
+
+func (f *rangeFetcher) GetCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) (map[string]float64, error) {
+	f.ranges = append(f.ranges, [2]time.Time{start, end})
+	if f.err != nil {
+		return nil, f.err
+	}
+	return map[string]float64{"EC2": 100}, nil
+}
+
+func (f *rangeFetcher) GetMonthlyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.MonthlyCosts, error) {
+	f.ranges = append(f.ranges, [2]time.Time{start, end})
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []aws.MonthlyCosts{{Month: start, Costs: map[string]float64{"EC2": 100}}}, nil
+}
+
+func (f *rangeFetcher) GetDailyCosts(ctx context.Context, start, end time.Time, groupBy []aws.GroupType, metric string, filter *types.Expression) ([]aws.DailyCost, error) {
+	f.ranges = append(f.ranges, [2]time.Time{start, end})
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []aws.DailyCost{{Date: start, Cost: 10}}, nil
+}
+
+func (f *rangeFetcher) SetLogger(logger aws.Logger) {}
+
+var (
+	// now puts the retention horizon at Nov 1, 2023
+	now = time.Date(2024, 12, 10, 12, 0, 0, 0, time.UTC)
+
+	sep23 = time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	oct23 = time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	nov23 = time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	dec23 = time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	byService = []aws.GroupType{aws.GroupByService}
+)
+
+func newTestFetcher(t *testing.T) (*Fetcher, *rangeFetcher, *Store) {
+	t.Helper()
+	inner := &rangeFetcher{}
+	store := NewStore(t.TempDir())
+	store.now = func() time.Time { return now }
+	f := NewFetcher(inner, store, "default")
+	f.now = func() time.Time { return now }
+	return f, inner, store
+}
+
+func TestHorizon(t *testing.T) {
+	if got := Horizon(now); !got.Equal(nov23) {
+		t.Errorf("Horizon() = %v, want %v", got, nov23)
+	}
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir())
+	series := NewSeries("default", "UnblendedCost", byService, nil)
+
+	if _, found, err := store.Monthly(series, sep23); err != nil || found {
+		t.Fatalf("Monthly() before put = found %v, err %v", found, err)
+	}
+
+	if err := store.PutMonthly(series, sep23, map[string]float64{"EC2": 100, "S3": 20}); err != nil {
+		t.Fatalf("PutMonthly() error = %v", err)
+	}
+	days := []aws.DailyCost{
+		{Date: sep23, Cost: 3, Groups: map[string]float64{"EC2": 2, "S3": 1}},
+		{Date: sep23.AddDate(0, 0, 1), Cost: 4, Groups: map[string]float64{"EC2": 4}},
+	}
+	if err := store.PutDaily(series, sep23, days); err != nil {
+		t.Fatalf("PutDaily() error = %v", err)
+	}
+
+	costs, found, err := store.Monthly(series, sep23)
+	if err != nil || !found {
+		t.Fatalf("Monthly() = found %v, err %v", found, err)
+	}
+	if costs["EC2"] != 100 || costs["S3"] != 20 {
+		t.Errorf("Monthly() = %v", costs)
+	}
+
+	gotDays, found, err := store.Daily(series, sep23)
+	if err != nil || !found {
+		t.Fatalf("Daily() = found %v, err %v", found, err)
+	}
+	if len(gotDays) != 2 || !gotDays[1].Date.Equal(sep23.AddDate(0, 0, 1)) || gotDays[0].Groups["S3"] != 1 {
+		t.Errorf("Daily() = %+v", gotDays)
+	}
+
+	// Another metric is another series
+	other := NewSeries("default", "AmortizedCost", byService, nil)
+	if _, found, _ := store.Monthly(other, sep23); found {
+		t.Error("Monthly() found costs of another metric")
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if coverages, err := store.List(); err != nil || len(coverages) != 0 {
+		t.Fatalf("List() on empty store = %v, %v", coverages, err)
+	}
+
+	series := NewSeries("default", "UnblendedCost", byService, nil)
+	for _, month := range []time.Time{oct23, sep23} {
+		if err := store.PutMonthly(series, month, map[string]float64{"EC2": 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.PutDaily(series, sep23, nil); err != nil {
+		t.Fatal(err)
+	}
+	total := NewSeries("default", "UnblendedCost", nil, nil)
+	if err := store.PutMonthly(total, sep23, map[string]float64{"Total": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	coverages, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(coverages) != 2 {
+		t.Fatalf("List() returned %d series, want 2", len(coverages))
+	}
+	if coverages[0].Series != total || coverages[1].Series != series {
+		t.Errorf("List() series = %+v, %+v", coverages[0].Series, coverages[1].Series)
+	}
+	got := coverages[1]
+	if len(got.Monthly) != 2 || !got.Monthly[0].Equal(sep23) || !got.Monthly[1].Equal(oct23) {
+		t.Errorf("Monthly coverage = %v, want [sep oct]", got.Monthly)
+	}
+	if len(got.Daily) != 1 || !got.Daily[0].Equal(sep23) {
+		t.Errorf("Daily coverage = %v, want [sep]", got.Daily)
+	}
+}
+
+func TestFetcher_PassesThroughWithinRetention(t *testing.T) {
+	f, inner, _ := newTestFetcher(t)
+
+	costs, err := f.GetCosts(context.Background(), dec23, dec23.AddDate(0, 1, 0), byService, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if costs["EC2"] != 100 || len(inner.ranges) != 1 {
+		t.Errorf("GetCosts() = %v with %d calls, want live costs", costs, len(inner.ranges))
+	}
+}
+
+func TestFetcher_ReadsStoredMonths(t *testing.T) {
+	f, inner, store := newTestFetcher(t)
+	ctx := context.Background()
+	series := NewSeries("default", "UnblendedCost", byService, nil)
+	if err := store.PutMonthly(series, oct23, map[string]float64{"EC2": 40, "S3": 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	costs, err := f.GetCosts(ctx, oct23, nov23, byService, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if costs["EC2"] != 40 || costs["S3"] != 5 || len(inner.ranges) != 0 {
+		t.Errorf("GetCosts() = %v with %d calls, want stored costs only", costs, len(inner.ranges))
+	}
+
+	// A range across the horizon adds the live part
+	costs, err = f.GetCosts(ctx, oct23, dec23, byService, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if costs["EC2"] != 140 {
+		t.Errorf("GetCosts() EC2 = %v, want 140", costs["EC2"])
+	}
+	if len(inner.ranges) != 1 || !inner.ranges[0][0].Equal(nov23) || !inner.ranges[0][1].Equal(dec23) {
+		t.Errorf("live ranges = %v, want [nov dec)", inner.ranges)
+	}
+
+	monthly, err := f.GetMonthlyCosts(ctx, oct23, dec23, byService, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetMonthlyCosts() error = %v", err)
+	}
+	if len(monthly) != 2 || !monthly[0].Month.Equal(oct23) || monthly[0].Costs["EC2"] != 40 || !monthly[1].Month.Equal(nov23) {
+		t.Errorf("GetMonthlyCosts() = %+v", monthly)
+	}
+}
+
+func TestFetcher_PartialMonthFromDailyRecord(t *testing.T) {
+	f, _, store := newTestFetcher(t)
+	series := NewSeries("default", "UnblendedCost", byService, nil)
+	var days []aws.DailyCost
+	for day := oct23; day.Before(nov23); day = day.AddDate(0, 0, 1) {
+		days = append(days, aws.DailyCost{Date: day, Cost: 2, Groups: map[string]float64{"EC2": 2}})
+	}
+	if err := store.PutDaily(series, oct23, days); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 10, 21, 0, 0, 0, 0, time.UTC)
+	costs, err := f.GetCosts(context.Background(), start, end, byService, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if costs["EC2"] != 20 {
+		t.Errorf("GetCosts() EC2 = %v, want 20 (10 days)", costs["EC2"])
+	}
+
+	// Ungrouped daily totals can come from the grouped series
+	daily, err := f.GetDailyCosts(context.Background(), start, end, nil, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetDailyCosts() error = %v", err)
+	}
+	if len(daily) != 10 || daily[0].Groups != nil || !daily[0].Date.Equal(start) {
+		t.Errorf("GetDailyCosts() returned %d days, first %+v", len(daily), daily[0])
+	}
+}
+
+func TestFetcher_NotStored(t *testing.T) {
+	f, inner, _ := newTestFetcher(t)
+	ctx := context.Background()
+
+	// Months that were not stored are fetched, in case Cost Explorer still has them
+	costs, err := f.GetCosts(ctx, sep23, nov23, byService, "UnblendedCost", nil)
+	if err != nil {
+		t.Fatalf("GetCosts() error = %v", err)
+	}
+	if costs["EC2"] != 200 || len(inner.ranges) != 2 || !inner.ranges[0][0].Equal(sep23) || !inner.ranges[1][1].Equal(nov23) {
+		t.Errorf("GetCosts() = %v with ranges %v, want each month fetched", costs, inner.ranges)
+	}
+
+	monthly, err := f.GetMonthlyCosts(ctx, sep23, oct23, byService, "UnblendedCost", nil)
+	if err != nil || len(monthly) != 1 || !monthly[0].Month.Equal(sep23) {
+		t.Errorf("GetMonthlyCosts() = %+v, %v, want the fetched month", monthly, err)
+	}
+	daily, err := f.GetDailyCosts(ctx, sep23, oct23, nil, "UnblendedCost", nil)
+	if err != nil || len(daily) != 1 || !daily[0].Date.Equal(sep23) {
+		t.Errorf("GetDailyCosts() = %+v, %v, want the fetched days", daily, err)
+	}
+
+	// ErrNotStored only when fetching fails too
+	inner.err = errors.New("data unavailable")
+	if _, err := f.GetCosts(ctx, sep23, oct23, byService, "UnblendedCost", nil); !errors.Is(err, ErrNotStored) {
+		t.Errorf("GetCosts() error = %v, want ErrNotStored", err)
+	}
+	if _, err := f.GetMonthlyCosts(ctx, sep23, oct23, byService, "UnblendedCost", nil); !errors.Is(err, ErrNotStored) {
+		t.Errorf("GetMonthlyCosts() error = %v, want ErrNotStored", err)
+	}
+	if _, err := f.GetDailyCosts(ctx, sep23, oct23, nil, "UnblendedCost", nil); !errors.Is(err, ErrNotStored) {
+		t.Errorf("GetDailyCosts() error = %v, want ErrNotStored", err)
+	}
+}
@@ -0,0 +1,304 @@
+// Package history keeps grouped costs of closed months on disk, so that
+// periods Cost Explorer no longer returns can still be compared
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+
+	"github.com/hserkanyilmaz/costdiff/internal/aws"
+	costfilter "github.com/hserkanyilmaz/costdiff/internal/filter"
+	"github.com/hserkanyilmaz/costdiff/internal/fsutil"
+)
+
+const (
+	// formatVersion is bumped whenever the series or record format changes
+	formatVersion = 1
+
+	// seriesFile describes the query a series directory holds costs for
+	seriesFile = "series.json"
+
+	// Granularities a month of costs can be stored at
+	GranularityMonthly = "monthly"
+	GranularityDaily   = "daily"
+
+	// monthFormat names the record file of a month
+	monthFormat = "2006-01"
+)
+
+// Series identifies one query whose costs are stored: the same costs can only
+// answer the same metric, grouping and filter for the same AWS profile
+type Series struct {
+	Version int    `json:"version"`
+	Profile string `json:"profile,omitempty"`
+	Metric  string `json:"metric"`
+	GroupBy string `json:"group_by,omitempty"`
+	Filter  string `json:"filter,omitempty"`
+}
+
+// NewSeries creates the series of a Cost Explorer query
+func NewSeries(profile, metric string, groupBy []aws.GroupType, filter *types.Expression) Series {
+	parts := make([]string, len(groupBy))
+	for i, group := range groupBy {
+		parts[i] = group.Type + ":" + group.Key
+	}
+	return Series{
+		Version: formatVersion,
+		Profile: profile,
+		Metric:  metric,
+		GroupBy: strings.Join(parts, ","),
+		Filter:  costfilter.Format(filter),
+	}
+}
+
+// id returns the directory name of the series
+func (s Series) id() string {
+	s.Version = formatVersion
+	encoded, _ := json.Marshal(s)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:8])
+}
+
+// monthlyRecord is the on-disk format of a month's grouped costs
+type monthlyRecord struct {
+	Month     string             `json:"month"`
+	FetchedAt time.Time          `json:"fetched_at"`
+	Costs     map[string]float64 `json:"costs"`
+}
+
+// dailyRecord is the on-disk format of a month's daily costs
+type dailyRecord struct {
+	Month     string     `json:"month"`
+	FetchedAt time.Time  `json:"fetched_at"`
+	Days      []dayCosts `json:"days"`
+}
+
+// dayCosts is a single day of a dailyRecord
+type dayCosts struct {
+	Date   string             `json:"date"`
+	Cost   float64            `json:"cost"`
+	Groups map[string]float64 `json:"groups,omitempty"`
+}
+
+// Coverage lists the months a series has stored at each granularity, oldest first
+type Coverage struct {
+	Series  Series
+	Monthly []time.Time
+	Daily   []time.Time
+}
+
+// Store is a directory of series, each holding one JSON file per month and granularity
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore creates a store rooted at dir. The directory is created on first write.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// DefaultDir returns the history directory, honoring COSTDIFF_HISTORY_DIR.
+// History cannot be fetched again once it ages out of Cost Explorer, so it
+// lives in the config directory rather than with the disposable query cache.
+func DefaultDir() (string, error) {
+	return fsutil.ConfigDir("COSTDIFF_HISTORY_DIR", "history")
+}
+
+// Dir returns the directory the store reads and writes
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// PutMonthly stores the grouped costs of the month starting at month
+func (s *Store) PutMonthly(series Series, month time.Time, costs map[string]float64) error {
+	return s.put(series, GranularityMonthly, month, monthlyRecord{
+		Month:     month.Format(monthFormat),
+		FetchedAt: s.now().UTC(),
+		Costs:     costs,
+	})
+}
+
+// Monthly loads the grouped costs of the month starting at month, reporting
+// false when they are not stored
+func (s *Store) Monthly(series Series, month time.Time) (map[string]float64, bool, error) {
+	var record monthlyRecord
+	found, err := s.get(series, GranularityMonthly, month, &record)
+	if !found || err != nil {
+		return nil, false, err
+	}
+	return record.Costs, true, nil
+}
+
+// PutDaily stores the daily costs of the month starting at month
+func (s *Store) PutDaily(series Series, month time.Time, days []aws.DailyCost) error {
+	record := dailyRecord{
+		Month:     month.Format(monthFormat),
+		FetchedAt: s.now().UTC(),
+		Days:      make([]dayCosts, len(days)),
+	}
+	for i, day := range days {
+		record.Days[i] = dayCosts{Date: day.Date.Format("2006-01-02"), Cost: day.Cost, Groups: day.Groups}
+	}
+	return s.put(series, GranularityDaily, month, record)
+}
+
+// Daily loads the daily costs of the month starting at month, reporting
+// false when they are not stored
+func (s *Store) Daily(series Series, month time.Time) ([]aws.DailyCost, bool, error) {
+	var record dailyRecord
+	found, err := s.get(series, GranularityDaily, month, &record)
+	if !found || err != nil {
+		return nil, false, err
+	}
+
+	days := make([]aws.DailyCost, len(record.Days))
+	for i, day := range record.Days {
+		date, err := time.Parse("2006-01-02", day.Date)
+		if err != nil {
+			return nil, false, fmt.Errorf("corrupt history record %s: %w", s.path(series, GranularityDaily, month), err)
+		}
+		days[i] = aws.DailyCost{Date: date, Cost: day.Cost, Groups: day.Groups}
+	}
+	return days, true, nil
+}
+
+// List returns the coverage of every series in the store
+func (s *Store) List() ([]Coverage, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	var coverages []Coverage
+	for _, de := range dirEntries {
+		if !de.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, de.Name(), seriesFile))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history series: %w", err)
+		}
+
+		var series Series
+		if err := json.Unmarshal(data, &series); err != nil {
+			return nil, fmt.Errorf("corrupt history series %s: %w", de.Name(), err)
+		}
+		if series.Version != formatVersion {
+			continue
+		}
+
+		coverage := Coverage{Series: series}
+		if coverage.Monthly, err = s.months(series, GranularityMonthly); err != nil {
+			return nil, err
+		}
+		if coverage.Daily, err = s.months(series, GranularityDaily); err != nil {
+			return nil, err
+		}
+		coverages = append(coverages, coverage)
+	}
+
+	sort.Slice(coverages, func(i, j int) bool {
+		a, b := coverages[i].Series, coverages[j].Series
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		if a.GroupBy != b.GroupBy {
+			return a.GroupBy < b.GroupBy
+		}
+		return a.Filter < b.Filter
+	})
+	return coverages, nil
+}
+
+// months returns the months of a series stored at a granularity, oldest first
+func (s *Store) months(series Series, granularity string) ([]time.Time, error) {
+	dirEntries, err := os.ReadDir(filepath.Join(s.dir, series.id(), granularity))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	var months []time.Time
+	for _, de := range dirEntries {
+		name, ok := strings.CutSuffix(de.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if month, err := time.Parse(monthFormat, name); err == nil {
+			months = append(months, month)
+		}
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	return months, nil
+}
+
+// get loads a month's record into v, reporting false when it is not stored
+func (s *Store) get(series Series, granularity string, month time.Time, v interface{}) (bool, error) {
+	path := s.path(series, granularity, month)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read history record: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("corrupt history record %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// put writes a month's record, and the series description on first use
+func (s *Store) put(series Series, granularity string, month time.Time, v interface{}) error {
+	series.Version = formatVersion
+	dir := filepath.Join(s.dir, series.id())
+	if err := os.MkdirAll(filepath.Join(dir, granularity), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, seriesFile)); errors.Is(err, os.ErrNotExist) {
+		if err := writeJSON(dir, seriesFile, series); err != nil {
+			return err
+		}
+	}
+	return writeJSON(filepath.Join(dir, granularity), month.Format(monthFormat)+".json", v)
+}
+
+// path returns the record file of a month
+func (s *Store) path(series Series, granularity string, month time.Time) string {
+	return filepath.Join(s.dir, series.id(), granularity, month.Format(monthFormat)+".json")
+}
+
+// writeJSON writes v to dir/name
+func writeJSON(dir, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+	if err := fsutil.WriteFile(filepath.Join(dir, name), data); err != nil {
+		return fmt.Errorf("failed to write history record: %w", err)
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/groupkey"
 )
 
 // RenderCSV outputs the diff result as CSV to stdout
@@ -20,8 +22,8 @@ func RenderCSVTo(w io.Writer, result *diff.Result) error {
 	defer writer.Flush()
 
 	// Write header
-	header := []string{
-		"name",
+	header := append([]string{"name"}, dimensionColumns(result.GroupBy)...)
+	header = append(header,
 		"from_period",
 		"to_period",
 		"from_cost",
@@ -30,6 +32,24 @@ func RenderCSVTo(w io.Writer, result *diff.Result) error {
 		"diff_percent",
 		"is_new",
 		"is_removed",
+		"contribution_percent",
+		"cumulative_percent",
+		"from_share_percent",
+		"to_share_percent",
+		"share_shift",
+		"synthetic",
+	)
+	hasCharges := result.HasCharges()
+	if hasCharges {
+		header = append(header, "driver")
+	}
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		header = append(header, budgetColumns...)
+	}
+	hasFiscal := isFiscal(result.Fiscal, result.FromPeriod, result.ToPeriod)
+	if hasFiscal {
+		header = append(header, "from_start", "from_end", "to_start", "to_end")
 	}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
@@ -37,16 +57,32 @@ func RenderCSVTo(w io.Writer, result *diff.Result) error {
 
 	// Write rows
 	for _, item := range result.Items {
-		row := []string{
-			item.Name,
-			result.FromPeriod.Label(),
-			result.ToPeriod.Label(),
+		row := append([]string{item.Name}, keyCells(item.Keys, result.GroupBy)...)
+		row = append(row,
+			result.FromLabel(),
+			result.ToPeriod.Label(result.Fiscal),
 			fmt.Sprintf("%.2f", item.FromCost),
 			fmt.Sprintf("%.2f", item.ToCost),
 			fmt.Sprintf("%.2f", item.Diff),
 			fmt.Sprintf("%.2f", item.DiffPct),
 			fmt.Sprintf("%t", item.IsNew),
 			fmt.Sprintf("%t", item.IsRemoved),
+			fmt.Sprintf("%.2f", item.ContributionPct),
+			fmt.Sprintf("%.2f", item.CumulativePct),
+			fmt.Sprintf("%.2f", item.FromSharePct),
+			fmt.Sprintf("%.2f", item.ToSharePct),
+			fmt.Sprintf("%.2f", item.ShareShift),
+			fmt.Sprintf("%t", item.Synthetic),
+		)
+		if hasCharges {
+			row = append(row, item.Driver)
+		}
+		if hasBudgets {
+			row = append(row, budgetCSVCells(item.Budget)...)
+		}
+		if hasFiscal {
+			row = append(row, boundaryCells(result.FromPeriod)...)
+			row = append(row, boundaryCells(result.ToPeriod)...)
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write CSV row: %w", err)
@@ -67,19 +103,38 @@ func RenderTopCSVTo(w io.Writer, result *diff.TopResult) error {
 	defer writer.Flush()
 
 	// Write header
-	header := []string{"rank", "name", "period", "cost", "percent"}
+	header := append([]string{"rank", "name"}, dimensionColumns(result.GroupBy)...)
+	header = append(header, "period", "cost", "percent", "synthetic")
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		header = append(header, budgetColumns...)
+	}
+	hasFiscal := isFiscal(result.Fiscal, result.Period)
+	if hasFiscal {
+		header = append(header, "period_start", "period_end")
+	}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
 	// Write rows
 	for i, item := range result.Items {
-		row := []string{
-			fmt.Sprintf("%d", i+1),
-			item.Name,
-			result.Period.Label(),
+		rank := fmt.Sprintf("%d", i+1)
+		if item.Synthetic {
+			rank = ""
+		}
+		row := append([]string{rank, item.Name}, keyCells(item.Keys, result.GroupBy)...)
+		row = append(row,
+			result.Period.Label(result.Fiscal),
 			fmt.Sprintf("%.2f", item.Cost),
 			fmt.Sprintf("%.2f", item.Percent),
+			fmt.Sprintf("%t", item.Synthetic),
+		)
+		if hasBudgets {
+			row = append(row, budgetCSVCells(item.Budget)...)
+		}
+		if hasFiscal {
+			row = append(row, boundaryCells(result.Period)...)
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write CSV row: %w", err)
@@ -94,25 +149,41 @@ func RenderWatchCSV(result *diff.WatchResult) error {
 	return RenderWatchCSVTo(os.Stdout, result)
 }
 
-// RenderWatchCSVTo outputs the watch result as CSV to the specified writer
+// RenderWatchCSVTo outputs the watch result as CSV to the specified writer.
+// Grouped results have one row per day and group instead of one row per day.
 func RenderWatchCSVTo(w io.Writer, result *diff.WatchResult) error {
 	writer := csv.NewWriter(w)
 	defer writer.Flush()
 
+	if result.IsGrouped() {
+		return writeWatchCSVGrouped(writer, result)
+	}
+
 	// Write header
-	header := []string{"date", "day_of_week", "cost", "change", "change_percent"}
+	header := []string{"date", "day_of_week", "cost", "change", "change_percent", "expected", "anomaly_score", "anomaly", "severity"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
 	// Write rows
 	for _, day := range result.Days {
+		// Leave the baseline empty for days without enough history to score
+		var expected, score string
+		if day.Scored {
+			expected = fmt.Sprintf("%.2f", day.Expected)
+			score = fmt.Sprintf("%.2f", day.Score)
+		}
+
 		row := []string{
 			day.Date.Format("2006-01-02"),
 			day.Date.Format("Monday"),
 			fmt.Sprintf("%.2f", day.Cost),
 			fmt.Sprintf("%.2f", day.Change),
 			fmt.Sprintf("%.2f", day.ChangePercent),
+			expected,
+			score,
+			fmt.Sprintf("%t", day.Anomaly),
+			string(day.Severity),
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write CSV row: %w", err)
@@ -122,3 +193,393 @@ func RenderWatchCSVTo(w io.Writer, result *diff.WatchResult) error {
 	return nil
 }
 
+// writeWatchCSVGrouped writes one row per day and group
+func writeWatchCSVGrouped(writer *csv.Writer, result *diff.WatchResult) error {
+	// Write header
+	header := append([]string{"date", "day_of_week", "name"}, dimensionColumns(result.GroupBy)...)
+	header = append(header, "cost", "is_other")
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	for _, day := range result.Days {
+		for _, group := range result.Groups {
+			row := append([]string{
+				day.Date.Format("2006-01-02"),
+				day.Date.Format("Monday"),
+				group.Name,
+			}, keyCells(group.Keys, result.GroupBy)...)
+			row = append(row,
+				fmt.Sprintf("%.2f", day.Groups[group.Name]),
+				fmt.Sprintf("%t", group.IsOther),
+			)
+			if err := writer.Write(row); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RenderExplainCSV outputs the explain result as CSV to stdout
+func RenderExplainCSV(result *diff.ExplainResult) error {
+	return RenderExplainCSVTo(os.Stdout, result)
+}
+
+// RenderExplainCSVTo outputs the explain result as CSV to the specified writer,
+// one row per tree node in depth-first order starting with the service itself
+func RenderExplainCSVTo(w io.Writer, result *diff.ExplainResult) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{
+		"level",
+		"path",
+		"dimension",
+		"name",
+		"from_period",
+		"to_period",
+		"from_cost",
+		"to_cost",
+		"diff",
+		"diff_percent",
+		"contribution_percent",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	var write func(node diff.ExplainNode, level int, path []string) error
+	write = func(node diff.ExplainNode, level int, path []string) error {
+		path = append(path, node.Name)
+		row := []string{
+			fmt.Sprintf("%d", level),
+			groupkey.Join(path),
+			node.Dimension,
+			node.Name,
+			result.FromPeriod.Label(result.Fiscal),
+			result.ToPeriod.Label(result.Fiscal),
+			fmt.Sprintf("%.2f", node.FromCost),
+			fmt.Sprintf("%.2f", node.ToCost),
+			fmt.Sprintf("%.2f", node.Diff),
+			fmt.Sprintf("%.2f", node.DiffPct),
+			fmt.Sprintf("%.2f", node.ContributionPct),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+
+		for _, child := range node.Children {
+			if err := write(child, level+1, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return write(result.Root, 0, nil)
+}
+
+// RenderForecastCSV outputs the forecast result as CSV to stdout
+func RenderForecastCSV(result *diff.ForecastResult) error {
+	return RenderForecastCSVTo(os.Stdout, result)
+}
+
+// RenderForecastCSVTo outputs the forecast result as CSV to the specified writer
+func RenderForecastCSVTo(w io.Writer, result *diff.ForecastResult) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{
+		"month",
+		"actual",
+		"forecast",
+		"projected",
+		"lower",
+		"upper",
+		"interval",
+		"baseline_period",
+		"baseline_total",
+		"diff",
+		"diff_percent",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	for _, month := range result.Months {
+		row := []string{
+			month.Period.Label(result.Fiscal),
+			fmt.Sprintf("%.2f", month.Actual),
+			fmt.Sprintf("%.2f", month.Forecast),
+			fmt.Sprintf("%.2f", month.Total),
+			fmt.Sprintf("%.2f", month.Lower),
+			fmt.Sprintf("%.2f", month.Upper),
+			fmt.Sprintf("%d", result.Interval),
+			result.Baseline.Label(result.Fiscal),
+			fmt.Sprintf("%.2f", result.BaselineTotal),
+			fmt.Sprintf("%.2f", month.Diff),
+			fmt.Sprintf("%.2f", month.DiffPct),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RenderBudgetCSV outputs the budget result as CSV to stdout
+func RenderBudgetCSV(result *diff.BudgetResult) error {
+	return RenderBudgetCSVTo(os.Stdout, result)
+}
+
+// RenderBudgetCSVTo outputs the budget result as CSV to the specified writer
+func RenderBudgetCSVTo(w io.Writer, result *diff.BudgetResult) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	// Write header
+	header := []string{
+		"name",
+		"dimension",
+		"period",
+		"elapsed_days",
+		"days",
+		"budget",
+		"actual",
+		"projected",
+		"variance",
+		"variance_percent",
+		"status",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	for _, item := range result.Items {
+		row := []string{
+			item.Name,
+			result.Dimension,
+			result.Period.Label(result.Fiscal),
+			fmt.Sprintf("%d", result.ElapsedDays),
+			fmt.Sprintf("%d", result.Period.Days()),
+			fmt.Sprintf("%.2f", item.Budget),
+			fmt.Sprintf("%.2f", item.Actual),
+			fmt.Sprintf("%.2f", item.Projected),
+			fmt.Sprintf("%.2f", item.Variance),
+			fmt.Sprintf("%.2f", item.VariancePct),
+			string(item.Status),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// budgetColumns are the columns diff and top add for --budget
+var budgetColumns = []string{"budget", "projected", "variance", "variance_percent", "budget_status"}
+
+// budgetCSVCells returns the budgetColumns cells for an item; items without a budget are left empty
+func budgetCSVCells(status *diff.BudgetStatus) []string {
+	if status == nil {
+		return make([]string, len(budgetColumns))
+	}
+	return []string{
+		fmt.Sprintf("%.2f", status.Budget),
+		fmt.Sprintf("%.2f", status.Projected),
+		fmt.Sprintf("%.2f", status.Variance),
+		fmt.Sprintf("%.2f", status.VariancePct),
+		string(status.Status),
+	}
+}
+
+// RenderTrendCSV outputs the trend result as CSV to stdout
+func RenderTrendCSV(result *diff.TrendResult, layout string) error {
+	return RenderTrendCSVTo(os.Stdout, result, layout)
+}
+
+// RenderTrendCSVTo outputs the trend result as CSV in the given layout to the specified writer
+func RenderTrendCSVTo(w io.Writer, result *diff.TrendResult, layout string) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if layout == LayoutLong {
+		return writeTrendCSVLong(writer, result)
+	}
+
+	// Write header
+	header := append([]string{"name"}, dimensionColumns(result.GroupBy)...)
+	fiscal := result.IsFiscal()
+	for _, month := range result.Months {
+		if fiscal {
+			// Fiscal periods do not follow months, so name both calendars
+			header = append(header, fmt.Sprintf("%s (%s..%s)", month.Label(result.Fiscal),
+				month.Start.Format("2006-01-02"), month.End.AddDate(0, 0, -1).Format("2006-01-02")))
+		} else {
+			header = append(header, month.Start.Format("2006-01"))
+		}
+	}
+	header = append(header, "total", "change", "change_percent", "cagr_percent")
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	for _, item := range result.Items {
+		row := append([]string{item.Name}, keyCells(item.Keys, result.GroupBy)...)
+		for _, cost := range item.Costs {
+			row = append(row, fmt.Sprintf("%.2f", cost))
+		}
+
+		var cagr string
+		if item.CAGR != nil {
+			cagr = fmt.Sprintf("%.2f", *item.CAGR)
+		}
+		row = append(row,
+			fmt.Sprintf("%.2f", item.Total),
+			fmt.Sprintf("%.2f", item.Change),
+			fmt.Sprintf("%.2f", item.ChangePct),
+			cagr,
+		)
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeTrendCSVLong writes one row per item and month. Fiscal trends start
+// each row with the fiscal period and its calendar boundaries instead of the month.
+func writeTrendCSVLong(writer *csv.Writer, result *diff.TrendResult) error {
+	fiscal := result.IsFiscal()
+
+	// Write header
+	header := []string{"month"}
+	if fiscal {
+		header = []string{"period", "start", "end"}
+	}
+	header = append(append(header, "name"), dimensionColumns(result.GroupBy)...)
+	if err := writer.Write(append(header, "cost")); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	for _, item := range result.Items {
+		for i, month := range result.Months {
+			row := []string{month.Start.Format("2006-01")}
+			if fiscal {
+				row = []string{month.Label(result.Fiscal), month.Start.Format("2006-01-02"), month.End.Format("2006-01-02")}
+			}
+			row = append(append(row, item.Name), keyCells(item.Keys, result.GroupBy)...)
+			if err := writer.Write(append(row, fmt.Sprintf("%.2f", item.Costs[i]))); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RenderChangeCSV outputs the change comparison as CSV to stdout
+func RenderChangeCSV(result *diff.ChangeResult) error {
+	return RenderChangeCSVTo(os.Stdout, result)
+}
+
+// RenderChangeCSVTo outputs the change comparison as CSV to the specified writer
+func RenderChangeCSVTo(w io.Writer, result *diff.ChangeResult) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	// Write header
+	header := append([]string{"name"}, dimensionColumns(result.GroupBy)...)
+	header = append(header,
+		"old_periods",
+		"new_periods",
+		"old_diff",
+		"old_diff_percent",
+		"new_diff",
+		"new_diff_percent",
+		"delta",
+		"only_in_old",
+		"only_in_new",
+		"synthetic",
+	)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	for _, item := range result.Items {
+		row := append([]string{item.Name}, keyCells(item.Keys, result.GroupBy)...)
+		row = append(row,
+			result.Old.Label(result.Fiscal),
+			result.New.Label(result.Fiscal),
+			fmt.Sprintf("%.2f", item.OldDiff),
+			fmt.Sprintf("%.2f", item.OldDiffPct),
+			fmt.Sprintf("%.2f", item.NewDiff),
+			fmt.Sprintf("%.2f", item.NewDiffPct),
+			fmt.Sprintf("%.2f", item.Delta),
+			fmt.Sprintf("%t", item.OnlyInOld),
+			fmt.Sprintf("%t", item.OnlyInNew),
+			fmt.Sprintf("%t", item.Synthetic),
+		)
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// dimensionColumns returns extra CSV columns for multi-level groupings,
+// one per dimension (e.g. "service", "region", "tag_team")
+func dimensionColumns(groupBy []string) []string {
+	if len(groupBy) <= 1 {
+		return nil
+	}
+
+	columns := make([]string, len(groupBy))
+	for i, dim := range groupBy {
+		columns[i] = strings.NewReplacer("-", "_", ":", "_").Replace(dim)
+	}
+	return columns
+}
+
+// keyCells returns the per-dimension key values matching dimensionColumns
+func keyCells(keys []string, groupBy []string) []string {
+	if len(groupBy) <= 1 {
+		return nil
+	}
+
+	cells := make([]string, len(groupBy))
+	copy(cells, keys)
+	return cells
+}
+
+// isFiscal reports whether any of the periods is labeled with fiscal calendar
+// c, in which case CSV output adds their calendar boundaries
+func isFiscal(c *diff.FiscalCalendar, periods ...diff.Period) bool {
+	for _, p := range periods {
+		if _, ok := p.FiscalLabel(c); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// boundaryCells returns the start and end dates of a period; the end is
+// exclusive, as in JSON output
+func boundaryCells(p diff.Period) []string {
+	return []string{p.Start.Format("2006-01-02"), p.End.Format("2006-01-02")}
+}
@@ -49,7 +49,10 @@ func TestRenderCSVTo(t *testing.T) {
 	}
 
 	// Verify header
-	expectedHeader := []string{"name", "from_period", "to_period", "from_cost", "to_cost", "diff", "diff_percent", "is_new", "is_removed"}
+	expectedHeader := []string{
+		"name", "from_period", "to_period", "from_cost", "to_cost", "diff", "diff_percent", "is_new", "is_removed",
+		"contribution_percent", "cumulative_percent", "from_share_percent", "to_share_percent", "share_shift", "synthetic",
+	}
 	if len(records[0]) != len(expectedHeader) {
 		t.Errorf("Header column count = %v, want %v", len(records[0]), len(expectedHeader))
 	}
@@ -77,6 +80,133 @@ func TestRenderCSVTo(t *testing.T) {
 	}
 }
 
+func TestRenderCSVTo_Contribution(t *testing.T) {
+	result := &diff.Result{
+		FromTotal: 1000,
+		ToTotal:   1200,
+		TotalDiff: 200,
+		Items: []diff.Item{
+			{Name: "EC2", FromCost: 500, ToCost: 650, Diff: 150},
+			{Name: "S3", FromCost: 500, ToCost: 550, Diff: 50},
+		},
+	}
+	result.SetContributions()
+
+	var buf bytes.Buffer
+	if err := RenderCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+
+	// contribution_percent, cumulative_percent, from/to share and shift for EC2
+	want := []string{"75.00", "75.00", "50.00", "54.17", "4.17"}
+	for i, value := range want {
+		if got := records[1][9+i]; got != value {
+			t.Errorf("%s = %v, want %v", records[0][9+i], got, value)
+		}
+	}
+	if got := records[2][10]; got != "100.00" {
+		t.Errorf("S3 cumulative_percent = %v, want 100.00", got)
+	}
+}
+
+func TestRenderCSVTo_OtherRow(t *testing.T) {
+	result := &diff.Result{
+		Items: []diff.Item{
+			{Name: "EC2 / us-east-1", FromCost: 100, ToCost: 150, Diff: 50},
+		},
+	}
+	result.SetGroupBy([]string{"service", "region"})
+	result.Items = append(result.Items, diff.Item{Name: "Other (2 items)", FromCost: 40, ToCost: 30, Diff: -10, Synthetic: true})
+
+	var buf bytes.Buffer
+	if err := RenderCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+
+	last := len(records[0]) - 1
+	if records[0][last] != "synthetic" {
+		t.Fatalf("Header[%d] = %v, want synthetic", last, records[0][last])
+	}
+	if records[1][last] != "false" || records[2][last] != "true" {
+		t.Errorf("synthetic = %v, %v, want false, true", records[1][last], records[2][last])
+	}
+	// The rollup has no per-dimension keys
+	if records[2][0] != "Other (2 items)" || records[2][1] != "" || records[2][2] != "" {
+		t.Errorf("Other row = %v, want name and empty keys", records[2][:3])
+	}
+}
+
+func TestRenderTopCSVTo_OtherRow(t *testing.T) {
+	result := &diff.TopResult{
+		Items: []diff.TopItem{
+			{Name: "EC2", Cost: 600, Percent: 60},
+			{Name: "Other (2 items)", Cost: 400, Percent: 40, Synthetic: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTopCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+
+	// rank, name, then cost, percent and synthetic after the period
+	want := map[int]string{0: "", 1: "Other (2 items)", 3: "400.00", 4: "40.00", 5: "true"}
+	for i, cell := range want {
+		if records[2][i] != cell {
+			t.Errorf("Other row[%d] = %v, want %v", i, records[2][i], cell)
+		}
+	}
+}
+
+func TestRenderExplainCSVTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderExplainCSVTo(&buf, testExplainResult()); err != nil {
+		t.Fatalf("RenderExplainCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+
+	// Header, then the service and its four descendants depth first
+	if len(records) != 6 {
+		t.Fatalf("CSV records count = %v, want 6", len(records))
+	}
+	want := [][]string{
+		{"0", "Amazon EC2", "service", "Amazon EC2"},
+		{"1", "Amazon EC2 / us-east-1", "region", "us-east-1"},
+		{"2", "Amazon EC2 / us-east-1 / 111", "account", "111"},
+		{"2", "Amazon EC2 / us-east-1 / 222", "account", "222"},
+		{"1", "Amazon EC2 / eu-west-1", "region", "eu-west-1"},
+	}
+	for i, row := range want {
+		for j, cell := range row {
+			if records[i+1][j] != cell {
+				t.Errorf("Row %d[%d] = %v, want %v", i+1, j, records[i+1][j], cell)
+			}
+		}
+	}
+	if records[3][10] != "94.40" {
+		t.Errorf("contribution_percent = %v, want 94.40", records[3][10])
+	}
+}
+
 func TestRenderCSVTo_EmptyItems(t *testing.T) {
 	result := &diff.Result{
 		FromPeriod: diff.Period{
@@ -241,7 +371,7 @@ func TestRenderWatchCSVTo(t *testing.T) {
 	}
 
 	// Verify header
-	expectedHeader := []string{"date", "day_of_week", "cost", "change", "change_percent"}
+	expectedHeader := []string{"date", "day_of_week", "cost", "change", "change_percent", "expected", "anomaly_score", "anomaly", "severity"}
 	for i, col := range expectedHeader {
 		if records[0][i] != col {
 			t.Errorf("Header[%d] = %v, want %v", i, records[0][i], col)
@@ -325,3 +455,339 @@ func TestRenderWatchCSVTo_EmptyDays(t *testing.T) {
 	}
 }
 
+func TestRenderCSVTo_MultiLevel(t *testing.T) {
+	result := &diff.Result{
+		FromPeriod: diff.Period{
+			Start: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		ToPeriod: diff.Period{
+			Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Items: []diff.Item{
+			{Name: "EC2 / us-east-1", FromCost: 100, ToCost: 150, Diff: 50, DiffPct: 50},
+		},
+	}
+	result.SetGroupBy([]string{"service", "tag:cost-center"})
+
+	var buf bytes.Buffer
+	if err := RenderCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+
+	expectedHeader := []string{"name", "service", "tag_cost_center", "from_period"}
+	for i, col := range expectedHeader {
+		if records[0][i] != col {
+			t.Errorf("Header[%d] = %v, want %v", i, records[0][i], col)
+		}
+	}
+	expectedRow := []string{"EC2 / us-east-1", "EC2", "us-east-1", "Dec 2024"}
+	for i, cell := range expectedRow {
+		if records[1][i] != cell {
+			t.Errorf("Row 1[%d] = %v, want %v", i, records[1][i], cell)
+		}
+	}
+}
+
+func TestRenderForecastCSVTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderForecastCSVTo(&buf, testForecastResult()); err != nil {
+		t.Fatalf("RenderForecastCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("CSV records count = %v, want 2", len(records))
+	}
+
+	want := []string{"Oct 2024", "450.00", "700.00", "1150.00", "1050.00", "1300.00", "80", "Sep 2024", "1000.00", "150.00", "15.00"}
+	for i, cell := range want {
+		if records[1][i] != cell {
+			t.Errorf("Row 1[%d] (%s) = %v, want %v", i, records[0][i], records[1][i], cell)
+		}
+	}
+}
+
+func TestRenderTrendCSVTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderTrendCSVTo(&buf, testTrendResult(), LayoutWide); err != nil {
+		t.Fatalf("RenderTrendCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("CSV records count = %v, want 3", len(records))
+	}
+
+	wantHeader := []string{"name", "2024-08", "2024-09", "2024-10", "total", "change", "change_percent", "cagr_percent"}
+	for i, col := range wantHeader {
+		if records[0][i] != col {
+			t.Errorf("Header[%d] = %v, want %v", i, records[0][i], col)
+		}
+	}
+	if got := records[2]; got[0] != "Amazon S3" || got[3] != "0.00" || got[7] != "" {
+		t.Errorf("Row 2 = %v, want Amazon S3 with no October cost and empty CAGR", got)
+	}
+
+	buf.Reset()
+	if err := RenderTrendCSVTo(&buf, testTrendResult(), LayoutLong); err != nil {
+		t.Fatalf("RenderTrendCSVTo() error = %v", err)
+	}
+
+	records, err = csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	if len(records) != 7 {
+		t.Fatalf("CSV records count = %v, want 7", len(records))
+	}
+	if got := records[3]; got[0] != "2024-10" || got[1] != "Amazon EC2" || got[2] != "200.00" {
+		t.Errorf("Row 3 = %v, want [2024-10 Amazon EC2 200.00]", got)
+	}
+}
+
+func TestRenderTrendCSVTo_Fiscal(t *testing.T) {
+	result := testTrendResult()
+	result.Fiscal = &diff.FiscalCalendar{StartMonth: time.August}
+
+	var buf bytes.Buffer
+	if err := RenderTrendCSVTo(&buf, result, LayoutWide); err != nil {
+		t.Fatalf("RenderTrendCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	if got := records[0][1]; got != "FY25-P01 (2024-08-01..2024-08-31)" {
+		t.Errorf("Header[1] = %v, want the fiscal label with calendar dates", got)
+	}
+
+	buf.Reset()
+	if err := RenderTrendCSVTo(&buf, result, LayoutLong); err != nil {
+		t.Fatalf("RenderTrendCSVTo() error = %v", err)
+	}
+
+	records, err = csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	want := [][]string{
+		{"period", "start", "end", "name", "cost"},
+		{"FY25-P01", "2024-08-01", "2024-09-01", "Amazon EC2", "100.00"},
+	}
+	for r, row := range want {
+		for i, cell := range row {
+			if records[r][i] != cell {
+				t.Errorf("Row %d[%d] = %v, want %v", r, i, records[r][i], cell)
+			}
+		}
+	}
+}
+
+func TestRenderCSVTo_Fiscal(t *testing.T) {
+	result := &diff.Result{
+		FromPeriod: diff.Period{
+			Start: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		ToPeriod: diff.Period{
+			Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Items:  []diff.Item{{Name: "EC2", FromCost: 500, ToCost: 600, Diff: 100, DiffPct: 20}},
+		Fiscal: &diff.FiscalCalendar{StartMonth: time.August},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	header, row := records[0], records[1]
+	if got := header[len(header)-4:]; strings.Join(got, ",") != "from_start,from_end,to_start,to_end" {
+		t.Errorf("last header columns = %v, want the period boundaries", got)
+	}
+	if row[1] != "FY25-P05" || row[2] != "FY25-P06" || row[len(row)-2] != "2025-01-01" {
+		t.Errorf("row = %v, want fiscal labels and calendar boundaries", row)
+	}
+}
+
+func TestRenderWatchCSVTo_Grouped(t *testing.T) {
+	result := &diff.WatchResult{
+		Days: []diff.DayItem{
+			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 110, Groups: map[string]float64{"EC2 / us-east-1": 100, "S3 / eu-west-1": 10}},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 118, Groups: map[string]float64{"EC2 / us-east-1": 118}},
+		},
+	}
+	result.SetGroups([]string{"service", "region"}, 10)
+
+	var buf bytes.Buffer
+	if err := RenderWatchCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("CSV records count = %v, want 5 (header + 2 days x 2 groups)", len(records))
+	}
+
+	wantHeader := []string{"date", "day_of_week", "name", "service", "region", "cost", "is_other"}
+	for i, col := range wantHeader {
+		if records[0][i] != col {
+			t.Errorf("Header[%d] = %v, want %v", i, records[0][i], col)
+		}
+	}
+	want := []string{"2025-01-02", "Thursday", "S3 / eu-west-1", "S3", "eu-west-1", "0.00", "false"}
+	for i, cell := range want {
+		if records[4][i] != cell {
+			t.Errorf("Row 4[%d] = %v, want %v", i, records[4][i], cell)
+		}
+	}
+}
+
+func TestRenderWatchCSVTo_Anomalies(t *testing.T) {
+	result := &diff.WatchResult{
+		Days: []diff.DayItem{
+			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 100},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 400, Scored: true, Expected: 100,
+				Score: 9.25, Anomaly: true, Severity: diff.SeverityHigh},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWatchCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+
+	// Unscored days leave the baseline empty
+	if got := records[1][5:]; got[0] != "" || got[1] != "" || got[2] != "false" || got[3] != "" {
+		t.Errorf("Row 1 anomaly columns = %v, want [  false ]", got)
+	}
+	if got := records[2][5:]; got[0] != "100.00" || got[1] != "9.25" || got[2] != "true" || got[3] != "high" {
+		t.Errorf("Row 2 anomaly columns = %v, want [100.00 9.25 true high]", got)
+	}
+}
+
+func TestRenderCSVTo_Budgets(t *testing.T) {
+	result := &diff.Result{
+		Items: []diff.Item{
+			{Name: "EC2", ToCost: 700,
+				Budget: &diff.BudgetStatus{Budget: 600, Actual: 700, Projected: 800, Variance: 200, VariancePct: 33.333, Status: diff.BudgetOver}},
+			{Name: "S3", ToCost: 200},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+
+	header := records[0][len(records[0])-5:]
+	if strings.Join(header, ",") != "budget,projected,variance,variance_percent,budget_status" {
+		t.Errorf("budget columns = %v", header)
+	}
+	if got := strings.Join(records[1][len(records[1])-5:], ","); got != "600.00,800.00,200.00,33.33,over" {
+		t.Errorf("EC2 budget cells = %v", got)
+	}
+	if got := strings.Join(records[2][len(records[2])-5:], ","); got != ",,,," {
+		t.Errorf("S3 budget cells = %v, want empty without a budget", got)
+	}
+}
+
+func TestRenderTopCSVTo_NoBudgetColumns(t *testing.T) {
+	result := &diff.TopResult{Items: []diff.TopItem{{Name: "EC2", Cost: 600, Percent: 100}}}
+
+	var buf bytes.Buffer
+	if err := RenderTopCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopCSVTo() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "budget") {
+		t.Error("Budget columns should only be written with --budget")
+	}
+}
+
+func TestRenderBudgetCSVTo(t *testing.T) {
+	result := &diff.BudgetResult{
+		Period: diff.Period{
+			Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Dimension:   "service",
+		ElapsedDays: 31,
+		Items: []diff.BudgetItem{
+			{Name: "EC2", BudgetStatus: diff.NewBudgetStatus(1000, 1200, 1200)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderBudgetCSVTo(&buf, result); err != nil {
+		t.Fatalf("RenderBudgetCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	want := []string{"EC2", "service", "Oct 2024", "31", "31", "1000.00", "1200.00", "1200.00", "200.00", "20.00", "over"}
+	if strings.Join(records[1], ",") != strings.Join(want, ",") {
+		t.Errorf("row = %v, want %v", records[1], want)
+	}
+}
+
+func TestRenderChangeCSVTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderChangeCSVTo(&buf, testChangeResult()); err != nil {
+		t.Fatalf("RenderChangeCSVTo() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV output: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("CSV records count = %v, want 4", len(records))
+	}
+
+	want := [][]string{
+		{"Amazon EC2", "Sep 2024 → Oct 2024", "Oct 2024 → Nov 2024", "80.00", "20.00", "260.00", "50.00", "180.00", "false", "false", "false"},
+		{"AWS Lambda", "Sep 2024 → Oct 2024", "Oct 2024 → Nov 2024", "0.00", "0.00", "40.00", "100.00", "40.00", "false", "true", "false"},
+		{"Amazon S3", "Sep 2024 → Oct 2024", "Oct 2024 → Nov 2024", "20.00", "10.00", "0.00", "0.00", "-20.00", "true", "false", "false"},
+	}
+	for i, row := range want {
+		for j, cell := range row {
+			if records[i+1][j] != cell {
+				t.Errorf("Row %d[%d] (%s) = %v, want %v", i+1, j, records[0][j], records[i+1][j], cell)
+			}
+		}
+	}
+}
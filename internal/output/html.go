@@ -0,0 +1,717 @@
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+// Chart dimensions and colors of the HTML report
+const (
+	htmlChartHeight   = 280
+	htmlChartMargin   = 48
+	htmlLabelMaxWidth = 18
+	htmlDonutRadius   = 90
+	htmlDonutWidth    = 36
+
+	htmlColorUp    = "#d73a49"
+	htmlColorDown  = "#22863a"
+	htmlColorTotal = "#6a737d"
+	htmlColorBar   = "#0366d6"
+	htmlColorWarn  = "#e36209"
+	htmlColorOther = "#c0c4c8"
+)
+
+// htmlPalette colors the slices of the top donut chart
+var htmlPalette = []string{
+	"#0366d6", "#28a745", "#d73a49", "#6f42c1", "#e36209",
+	"#0598bc", "#dbab09", "#ea4aaa", "#2f363d", "#79b8ff",
+}
+
+// htmlPage is what the report template renders
+type htmlPage struct {
+	Title   string
+	Summary []string
+	Chart   template.HTML
+	Table   *htmlTable
+	Details string // heading of the table
+	Empty   bool
+	Notes   []string
+	Failed  bool // notes are policy violations
+}
+
+// htmlTable is a table whose columns can be sorted in the browser
+type htmlTable struct {
+	Header []htmlHeader
+	Rows   []htmlRow
+	Footer []htmlCell
+}
+
+type htmlHeader struct {
+	Label   string
+	Numeric bool
+}
+
+type htmlRow struct {
+	Cells []htmlCell
+	Fixed bool // stays at the bottom when sorting, like "Other" rows
+}
+
+type htmlCell struct {
+	Text  string
+	Sort  string // value to sort by, when it differs from the text
+	Class string
+}
+
+// RenderHTML outputs the diff result as a self-contained HTML report to stdout
+func RenderHTML(result *diff.Result) error {
+	return RenderHTMLTo(os.Stdout, result)
+}
+
+// RenderHTMLTo outputs the diff result as a self-contained HTML report to the
+// specified writer: a waterfall chart of the changes and a sortable table
+func RenderHTMLTo(w io.Writer, result *diff.Result) error {
+	title := fmt.Sprintf("AWS Cost Diff: %s → %s", result.FromLabel(), result.ToPeriod.Label(result.Fiscal))
+	if result.PerDay {
+		title += " (per day)"
+	}
+	page := htmlPage{
+		Title: title,
+		Summary: []string{fmt.Sprintf("Total: %s → %s (%s)",
+			FormatCurrency(result.FromTotal),
+			FormatCurrency(result.ToTotal),
+			plainChange(result.TotalDiff, result.TotalPct, false, false))},
+		Details: "Changes",
+		Empty:   len(result.Items) == 0,
+	}
+	if result.Budget != nil {
+		page.Summary = append(page.Summary, htmlBudgetLine(result.Budget))
+	}
+	if gate := result.Gate; gate != nil {
+		if gate.Passed {
+			page.Notes = []string{"Policy passed: " + strings.Join(gate.Conditions, ", ")}
+		} else {
+			page.Failed = true
+			for _, v := range gate.Violations {
+				page.Notes = append(page.Notes, v.Condition+": "+FormatViolation(v))
+			}
+		}
+	}
+	if page.Empty {
+		return renderHTMLPage(w, page)
+	}
+
+	page.Chart = waterfallChart(result)
+
+	// Build table
+	table := &htmlTable{}
+	for _, h := range groupHeaders(result.GroupBy) {
+		table.Header = append(table.Header, htmlHeader{Label: h})
+	}
+	table.Header = append(table.Header,
+		htmlHeader{Label: result.FromLabel(), Numeric: true},
+		htmlHeader{Label: result.ToPeriod.Label(result.Fiscal), Numeric: true},
+		htmlHeader{Label: "Change", Numeric: true},
+		htmlHeader{Label: "Change %", Numeric: true},
+	)
+	showContribution := result.Pareto > 0
+	if showContribution {
+		table.Header = append(table.Header,
+			htmlHeader{Label: "Contribution", Numeric: true},
+			htmlHeader{Label: "Cumulative", Numeric: true},
+			htmlHeader{Label: "Mix Shift", Numeric: true},
+		)
+	}
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		table.Header = append(table.Header, htmlBudgetHeaders()...)
+	}
+	hasCharges := result.HasCharges()
+	if hasCharges {
+		table.Header = append(table.Header, htmlHeader{Label: "Driver"})
+	}
+
+	for _, item := range result.Items {
+		cells := htmlGroupCells(item.Name, item.Keys, item.Synthetic, result.GroupBy, result.IsMultiLevel())
+		pct := htmlCell{Text: FormatPercent(item.DiffPct), Sort: htmlNumber(item.DiffPct)}
+		switch {
+		case item.IsNew:
+			pct = htmlCell{Text: "new", Sort: htmlNumber(math.Inf(1))}
+		case item.IsRemoved:
+			pct = htmlCell{Text: "removed", Sort: htmlNumber(-100)}
+		}
+		pct.Class = htmlChangeClass(item.Diff)
+		cells = append(cells,
+			htmlMoneyCell(item.FromCost),
+			htmlMoneyCell(item.ToCost),
+			htmlCell{Text: trendArrow(item.Diff) + FormatChange(item.Diff), Sort: htmlNumber(item.Diff), Class: htmlChangeClass(item.Diff)},
+			pct,
+		)
+		if showContribution {
+			cells = append(cells,
+				htmlCell{Text: fmt.Sprintf("%.1f%%", item.ContributionPct), Sort: htmlNumber(item.ContributionPct)},
+				htmlCell{Text: fmt.Sprintf("%.1f%%", item.CumulativePct), Sort: htmlNumber(item.CumulativePct)},
+				htmlCell{Text: FormatShareShift(item.ShareShift), Sort: htmlNumber(item.ShareShift)},
+			)
+		}
+		if hasBudgets {
+			cells = append(cells, htmlBudgetCells(item.Budget)...)
+		}
+		if hasCharges {
+			cells = append(cells, htmlCell{Text: item.Driver})
+		}
+		table.Rows = append(table.Rows, htmlRow{Cells: cells, Fixed: item.Synthetic})
+	}
+	page.Table = table
+
+	if showContribution {
+		var explained float64
+		var count int
+		for _, item := range result.Items {
+			if item.Synthetic {
+				continue
+			}
+			explained = math.Max(explained, item.CumulativePct)
+			count++
+		}
+		summary := fmt.Sprintf("%d items explain", count)
+		if count == 1 {
+			summary = "1 item explains"
+		}
+		page.Summary = append(page.Summary, fmt.Sprintf("%s %.1f%% of the net change (--pareto %g)",
+			summary, explained, result.Pareto))
+	}
+
+	return renderHTMLPage(w, page)
+}
+
+// RenderTopHTML outputs the top result as a self-contained HTML report to stdout
+func RenderTopHTML(result *diff.TopResult) error {
+	return RenderTopHTMLTo(os.Stdout, result)
+}
+
+// RenderTopHTMLTo outputs the top result as a self-contained HTML report to the
+// specified writer: a donut chart of the shares and a sortable table
+func RenderTopHTMLTo(w io.Writer, result *diff.TopResult) error {
+	page := htmlPage{
+		Title:   fmt.Sprintf("AWS Top Costs: %s", result.Period.Label(result.Fiscal)),
+		Summary: []string{"Total: " + FormatCurrency(result.Total)},
+		Details: "Top costs",
+		Empty:   len(result.Items) == 0,
+	}
+	if result.Budget != nil {
+		page.Summary = append(page.Summary, htmlBudgetLine(result.Budget))
+	}
+	if page.Empty {
+		return renderHTMLPage(w, page)
+	}
+
+	page.Chart = donutChart(result)
+
+	// Build table
+	table := &htmlTable{Header: []htmlHeader{{Label: "#", Numeric: true}}}
+	for _, h := range groupHeaders(result.GroupBy) {
+		table.Header = append(table.Header, htmlHeader{Label: h})
+	}
+	table.Header = append(table.Header,
+		htmlHeader{Label: "Cost", Numeric: true},
+		htmlHeader{Label: "% of Total", Numeric: true},
+	)
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		table.Header = append(table.Header, htmlBudgetHeaders()...)
+	}
+
+	for i, item := range result.Items {
+		rank := htmlCell{Text: fmt.Sprintf("%d", i+1)}
+		if item.Synthetic {
+			rank = htmlCell{}
+		}
+		cells := append([]htmlCell{rank},
+			htmlGroupCells(item.Name, item.Keys, item.Synthetic, result.GroupBy, result.IsMultiLevel())...)
+		cells = append(cells,
+			htmlMoneyCell(item.Cost),
+			htmlCell{Text: fmt.Sprintf("%.1f%%", item.Percent), Sort: htmlNumber(item.Percent)},
+		)
+		if hasBudgets {
+			cells = append(cells, htmlBudgetCells(item.Budget)...)
+		}
+		table.Rows = append(table.Rows, htmlRow{Cells: cells, Fixed: item.Synthetic})
+	}
+	page.Table = table
+
+	return renderHTMLPage(w, page)
+}
+
+// RenderWatchHTML outputs the watch result as a self-contained HTML report to stdout
+func RenderWatchHTML(result *diff.WatchResult) error {
+	return RenderWatchHTMLTo(os.Stdout, result)
+}
+
+// RenderWatchHTMLTo outputs the watch result as a self-contained HTML report to
+// the specified writer: a chart of the daily series and a sortable table
+func RenderWatchHTMLTo(w io.Writer, result *diff.WatchResult) error {
+	page := htmlPage{
+		Title: fmt.Sprintf("AWS Daily Costs: %s to %s",
+			result.StartDate.Format("Jan 2"),
+			result.EndDate.Format("Jan 2, 2006")),
+		Summary: []string{fmt.Sprintf("Total: %s · Daily Average: %s",
+			FormatCurrency(result.Total),
+			FormatCurrency(result.Average))},
+		Details: "Daily costs",
+		Empty:   len(result.Days) == 0,
+	}
+	if result.Anomalies > 0 {
+		page.Summary[0] += fmt.Sprintf(" · Anomalies: %d", result.Anomalies)
+	}
+	if page.Empty {
+		return renderHTMLPage(w, page)
+	}
+
+	page.Chart = dailyChart(result)
+
+	// Build table
+	table := &htmlTable{Header: []htmlHeader{{Label: "Date"}, {Label: "Day"}}}
+	for _, group := range result.Groups {
+		table.Header = append(table.Header, htmlHeader{Label: group.Name, Numeric: true})
+	}
+	if result.IsGrouped() {
+		table.Header = append(table.Header, htmlHeader{Label: "Total", Numeric: true})
+	} else {
+		table.Header = append(table.Header,
+			htmlHeader{Label: "Cost", Numeric: true},
+			htmlHeader{Label: "Change", Numeric: true},
+		)
+	}
+	table.Header = append(table.Header, htmlHeader{Label: "Anomaly", Numeric: true})
+
+	for i, day := range result.Days {
+		cells := []htmlCell{
+			{Text: day.Date.Format("Jan 2"), Sort: day.Date.Format("2006-01-02")},
+			{Text: day.Date.Format("Mon"), Sort: day.Date.Format("2006-01-02")},
+		}
+		for _, group := range result.Groups {
+			cells = append(cells, htmlMoneyCell(day.Groups[group.Name]))
+		}
+		cells = append(cells, htmlMoneyCell(day.Cost))
+		if !result.IsGrouped() {
+			change := htmlCell{Text: "-", Sort: "0"}
+			if i > 0 {
+				change = htmlCell{
+					Text:  plainChange(day.Change, day.ChangePercent, false, false),
+					Sort:  htmlNumber(day.Change),
+					Class: htmlChangeClass(day.Change),
+				}
+			}
+			cells = append(cells, change)
+		}
+		anomaly := htmlCell{Text: plainAnomaly(day), Sort: htmlNumber(day.Score)}
+		if day.Anomaly {
+			anomaly.Class = "up"
+			if day.Score < 0 {
+				anomaly.Class = "warn"
+			}
+		}
+		table.Rows = append(table.Rows, htmlRow{Cells: append(cells, anomaly)})
+	}
+
+	if result.IsGrouped() {
+		table.Footer = []htmlCell{{Text: "Total"}, {}}
+		for _, group := range result.Groups {
+			table.Footer = append(table.Footer, htmlMoneyCell(group.Total))
+		}
+		table.Footer = append(table.Footer, htmlMoneyCell(result.Total), htmlCell{})
+	}
+	page.Table = table
+
+	return renderHTMLPage(w, page)
+}
+
+// renderHTMLPage executes the report template, right-aligning the cells of
+// numeric columns
+func renderHTMLPage(w io.Writer, page htmlPage) error {
+	if table := page.Table; table != nil {
+		alignNumeric := func(cells []htmlCell) {
+			for i := range cells {
+				if i < len(table.Header) && table.Header[i].Numeric {
+					cells[i].Class = strings.TrimSpace("num " + cells[i].Class)
+				}
+			}
+		}
+		for _, row := range table.Rows {
+			alignNumeric(row.Cells)
+		}
+		alignNumeric(table.Footer)
+	}
+
+	if err := htmlTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return nil
+}
+
+// waterfallChart draws the from total, each item's change as a floating bar,
+// and the to total. Changes not shown as items are drawn as one more step so
+// the bars always end at the to total.
+func waterfallChart(result *diff.Result) template.HTML {
+	type step struct {
+		label      string
+		start, end float64
+		color      string
+	}
+
+	steps := []step{{label: result.FromLabel(), end: result.FromTotal, color: htmlColorTotal}}
+	running := result.FromTotal
+	for _, item := range result.Items {
+		if item.Diff == 0 {
+			continue
+		}
+		color := htmlColorUp
+		if item.Diff < 0 {
+			color = htmlColorDown
+		}
+		steps = append(steps, step{label: item.Name, start: running, end: running + item.Diff, color: color})
+		running += item.Diff
+	}
+	if rest := result.ToTotal - running; math.Abs(rest) >= 0.005 {
+		color := htmlColorUp
+		if rest < 0 {
+			color = htmlColorDown
+		}
+		steps = append(steps, step{label: "Not shown", start: running, end: result.ToTotal, color: color})
+	}
+	steps = append(steps, step{label: result.ToPeriod.Label(result.Fiscal), end: result.ToTotal, color: htmlColorTotal})
+
+	lo, hi := 0.0, 0.0
+	for _, s := range steps {
+		lo = math.Min(lo, math.Min(s.start, s.end))
+		hi = math.Max(hi, math.Max(s.start, s.end))
+	}
+	if hi == lo {
+		return ""
+	}
+
+	// The first label slants left of its bar, so the chart starts further in
+	const barWidth, gap = 36, 14
+	left := htmlChartMargin * 2
+	width := left + htmlChartMargin + len(steps)*(barWidth+gap)
+	plot := float64(htmlChartHeight - htmlChartMargin*2)
+	y := func(v float64) float64 {
+		return htmlChartMargin + (hi-v)/(hi-lo)*plot
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="Waterfall of changes">`,
+		width, htmlChartHeight+40, width, htmlChartHeight+40)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#d1d5da"/>`,
+		htmlChartMargin/2, y(0), width-htmlChartMargin/2, y(0))
+	for i, s := range steps {
+		x := left + i*(barWidth+gap)
+		top, bottom := y(math.Max(s.start, s.end)), y(math.Min(s.start, s.end))
+		height := math.Max(bottom-top, 1)
+		value := FormatCurrency(s.end)
+		if s.color != htmlColorTotal {
+			value = FormatChange(s.end - s.start)
+		}
+		fmt.Fprintf(&b, `<g><title>%s: %s</title><rect x="%d" y="%.1f" width="%d" height="%.1f" fill="%s"/></g>`,
+			svgEscape(s.label), value, x, top, barWidth, height, s.color)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" font-size="10" text-anchor="middle">%s</text>`,
+			x+barWidth/2, top-4, value)
+		if i < len(steps)-1 {
+			next := x + barWidth + gap
+			fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#959da5" stroke-dasharray="2,2"/>`,
+				x+barWidth, y(s.end), next, y(s.end))
+		}
+		lx, ly := x+barWidth/2, htmlChartHeight-htmlChartMargin+14
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="11" text-anchor="end" transform="rotate(-35 %d %d)">%s</text>`,
+			lx, ly, lx, ly, svgEscape(Truncate(s.label, htmlLabelMaxWidth)))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// donutChart draws each item's share of the total as a slice of a donut, with
+// a legend. Items without positive spend are left out.
+func donutChart(result *diff.TopResult) template.HTML {
+	var sum float64
+	for _, item := range result.Items {
+		if item.Cost > 0 {
+			sum += item.Cost
+		}
+	}
+	if sum == 0 {
+		return ""
+	}
+
+	const size = 2 * (htmlDonutRadius + htmlDonutWidth)
+	center := size / 2
+	circumference := 2 * math.Pi * htmlDonutRadius
+	width := size + 360
+	height := max(size, 24*len(result.Items)+20)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="Share of total">`,
+		width, height, width, height)
+	fmt.Fprintf(&b, `<g transform="rotate(-90 %d %d)">`, center, center)
+
+	var offset float64
+	var legend strings.Builder
+	var slices int
+	for _, item := range result.Items {
+		if item.Cost <= 0 {
+			continue
+		}
+		color := htmlPalette[slices%len(htmlPalette)]
+		if item.Synthetic {
+			color = htmlColorOther
+		}
+		length := item.Cost / sum * circumference
+		fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="%d" fill="none" stroke="%s" stroke-width="%d" stroke-dasharray="%.2f %.2f" stroke-dashoffset="%.2f"><title>%s: %s (%.1f%%)</title></circle>`,
+			center, center, htmlDonutRadius, color, htmlDonutWidth, length, circumference-length, circumference-offset,
+			svgEscape(item.Name), FormatCurrency(item.Cost), item.Percent)
+		offset += length
+
+		ly := 20 + 24*slices
+		slices++
+		fmt.Fprintf(&legend, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/><text x="%d" y="%d" font-size="12">%s · %s (%.1f%%)</text>`,
+			size+20, ly, color, size+38, ly+11, svgEscape(Truncate(item.Name, 32)), FormatCurrency(item.Cost), item.Percent)
+	}
+	b.WriteString(`</g>`)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="16" font-weight="bold" text-anchor="middle">%s</text>`,
+		center, center+6, svgEscape(FormatCurrency(result.Total)))
+	b.WriteString(legend.String())
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// dailyChart draws one bar per day, colored like the terminal bar chart, with
+// the daily average as a dashed line and a line through the expected costs of
+// scored days
+func dailyChart(result *diff.WatchResult) template.HTML {
+	maxCost := math.Max(maxDayCost(result.Days), result.Average)
+	if maxCost == 0 {
+		return ""
+	}
+
+	slot := math.Max(8, math.Min(40, 720/float64(len(result.Days))))
+	width := htmlChartMargin*2 + int(slot*float64(len(result.Days)))
+	plot := float64(htmlChartHeight - htmlChartMargin*2)
+	y := func(v float64) float64 {
+		return htmlChartMargin + (1-math.Max(v, 0)/maxCost)*plot
+	}
+	labelEvery := int(math.Ceil(40 / slot))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="Daily costs">`,
+		width, htmlChartHeight, width, htmlChartHeight)
+
+	var expected []string
+	for i, day := range result.Days {
+		x := htmlChartMargin + slot*float64(i)
+		color := htmlColorBar
+		switch {
+		case day.Anomaly && day.Score > 0:
+			color = htmlColorUp
+		case day.Anomaly:
+			color = htmlColorWarn
+		case !day.Scored && day.Cost > result.Average*AboveAverageThreshold:
+			color = htmlColorUp
+		case !day.Scored && day.Cost < result.Average*BelowAverageThreshold:
+			color = htmlColorDown
+		}
+		title := fmt.Sprintf("%s: %s", day.Date.Format("Mon Jan 2"), FormatCurrency(day.Cost))
+		if day.Anomaly {
+			title += " " + plainAnomaly(day)
+		}
+		fmt.Fprintf(&b, `<g><title>%s</title><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/></g>`,
+			svgEscape(title), x+1, y(day.Cost), slot-2, y(0)-y(day.Cost), color)
+		if i%labelEvery == 0 {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%d" font-size="10" text-anchor="middle">%s</text>`,
+				x+slot/2, htmlChartHeight-htmlChartMargin+14, day.Date.Format("Jan 2"))
+		}
+		if day.Scored {
+			expected = append(expected, fmt.Sprintf("%.1f,%.1f", x+slot/2, y(day.Expected)))
+		}
+	}
+	if len(expected) > 1 {
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#24292e" stroke-width="1.5"><title>Expected</title></polyline>`,
+			strings.Join(expected, " "))
+	}
+
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#959da5" stroke-dasharray="4,3"><title>Daily average: %s</title></line>`,
+		htmlChartMargin, y(result.Average), width-htmlChartMargin, y(result.Average), FormatCurrency(result.Average))
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" font-size="10" fill="#586069">avg %s</text>`,
+		width-htmlChartMargin+4, y(result.Average)+3, svgEscape(FormatCurrency(result.Average)))
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#d1d5da"/>`,
+		htmlChartMargin, y(0), width-htmlChartMargin, y(0))
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// htmlGroupCells returns the group name cell(s) for an item. "Other" rows have
+// the name in the first group column.
+func htmlGroupCells(name string, keys []string, synthetic bool, groupBy []string, multiLevel bool) []htmlCell {
+	if synthetic {
+		cells := make([]htmlCell, len(groupHeaders(groupBy)))
+		cells[0] = htmlCell{Text: name, Class: "muted"}
+		return cells
+	}
+	if !multiLevel {
+		return []htmlCell{{Text: name}}
+	}
+	cells := make([]htmlCell, len(keys))
+	for i, key := range keys {
+		cells[i] = htmlCell{Text: key}
+	}
+	return cells
+}
+
+// htmlMoneyCell returns a cell with a dollar amount
+func htmlMoneyCell(amount float64) htmlCell {
+	return htmlCell{Text: FormatCurrency(amount), Sort: htmlNumber(amount)}
+}
+
+// htmlBudgetHeaders returns budgetHeaders as sortable columns
+func htmlBudgetHeaders() []htmlHeader {
+	headers := make([]htmlHeader, len(budgetHeaders))
+	for i, h := range budgetHeaders {
+		headers[i] = htmlHeader{Label: h, Numeric: i < len(budgetHeaders)-1}
+	}
+	return headers
+}
+
+// htmlBudgetCells returns the budget cells of an item. Items without a budget
+// get dashes.
+func htmlBudgetCells(status *diff.BudgetStatus) []htmlCell {
+	if status == nil {
+		return []htmlCell{{Text: "-", Sort: "0"}, {Text: "-", Sort: "0"}, {Text: "-", Sort: "0"}, {}}
+	}
+	class := ""
+	if status.Status == diff.BudgetOver || status.Status == diff.BudgetAtRisk {
+		class = "up"
+	}
+	return []htmlCell{
+		htmlMoneyCell(status.Budget),
+		htmlMoneyCell(status.Projected),
+		{
+			Text:  plainChange(status.Variance, status.VariancePct, false, false),
+			Sort:  htmlNumber(status.Variance),
+			Class: htmlChangeClass(status.Variance),
+		},
+		{Text: strings.ReplaceAll(string(status.Status), "-", " "), Class: class},
+	}
+}
+
+// htmlBudgetLine summarizes total spend against the overall budget
+func htmlBudgetLine(status *diff.BudgetStatus) string {
+	return fmt.Sprintf("Budget: %s, projected %s (%s) %s",
+		FormatCurrency(status.Budget),
+		FormatCurrency(status.Projected),
+		plainChange(status.Variance, status.VariancePct, false, false),
+		strings.ReplaceAll(string(status.Status), "-", " "))
+}
+
+// htmlChangeClass returns the CSS class coloring a change: increases are red
+func htmlChangeClass(change float64) string {
+	switch {
+	case change > 0:
+		return "up"
+	case change < 0:
+		return "down"
+	}
+	return ""
+}
+
+// htmlNumber formats a sort value; infinities sort new items first
+func htmlNumber(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "1e308"
+	case math.IsInf(v, -1):
+		return "-1e308"
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+// svgEscape escapes text for use inside SVG markup
+func svgEscape(s string) string {
+	return template.HTMLEscapeString(s)
+}
+
+// htmlTemplate is the single-file report. It has no external assets, so it
+// can be opened offline, attached to tickets or archived.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292e; margin: 2rem auto; max-width: 1100px; padding: 0 1rem; }
+h1 { font-size: 1.5rem; margin-bottom: 0.5rem; }
+h2 { font-size: 1.1rem; margin-top: 2rem; }
+.summary p { margin: 0.25rem 0; font-size: 1.05rem; }
+.chart { display: block; max-width: 100%; height: auto; margin: 1.5rem 0; font-family: inherit; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9rem; }
+th, td { padding: 0.4rem 0.6rem; border-bottom: 1px solid #e1e4e8; text-align: left; white-space: nowrap; }
+th { cursor: pointer; user-select: none; background: #f6f8fa; }
+th.num, td.num { text-align: right; font-variant-numeric: tabular-nums; }
+th[aria-sort=ascending]::after { content: " ▲"; }
+th[aria-sort=descending]::after { content: " ▼"; }
+tfoot td { font-weight: bold; }
+.up { color: #d73a49; }
+.down { color: #22863a; }
+.warn { color: #e36209; }
+.muted { color: #6a737d; font-style: italic; }
+.notes.failed { color: #d73a49; }
+footer { margin-top: 2rem; color: #6a737d; font-size: 0.8rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="summary">{{range .Summary}}
+<p>{{.}}</p>{{end}}
+</div>
+{{if .Empty}}<p class="muted">No cost data found for the specified period.</p>
+{{else}}{{.Chart}}
+<h2>{{.Details}}</h2>
+<table class="sortable">
+<thead><tr>{{range .Table.Header}}<th{{if .Numeric}} class="num"{{end}}>{{.Label}}</th>{{end}}</tr></thead>
+<tbody>{{range .Table.Rows}}
+<tr{{if .Fixed}} data-fixed{{end}}>{{range .Cells}}<td{{if .Class}} class="{{.Class}}"{{end}}{{if .Sort}} data-sort="{{.Sort}}"{{end}}>{{.Text}}</td>{{end}}</tr>{{end}}
+</tbody>{{if .Table.Footer}}
+<tfoot><tr>{{range .Table.Footer}}<td{{if .Class}} class="{{.Class}}"{{end}}>{{.Text}}</td>{{end}}</tr></tfoot>{{end}}
+</table>
+{{end}}{{if .Notes}}<ul class="notes{{if .Failed}} failed{{end}}">{{range .Notes}}
+<li>{{.}}</li>{{end}}
+</ul>
+{{end}}<footer>Generated by costdiff</footer>
+<script>
+document.querySelectorAll("table.sortable th").forEach(function (th, col) {
+  th.addEventListener("click", function () {
+    var table = th.closest("table"), body = table.tBodies[0];
+    var numeric = th.classList.contains("num");
+    var dir = th.getAttribute("aria-sort") === "descending" ? 1 : -1;
+    if (!numeric && !th.hasAttribute("aria-sort")) dir = 1;
+    table.querySelectorAll("th").forEach(function (h) { h.removeAttribute("aria-sort"); });
+    th.setAttribute("aria-sort", dir > 0 ? "ascending" : "descending");
+    var value = function (row) {
+      var cell = row.cells[col], v = cell.dataset.sort !== undefined ? cell.dataset.sort : cell.textContent;
+      return numeric ? parseFloat(v) || 0 : v.toLowerCase();
+    };
+    var rows = Array.prototype.slice.call(body.rows);
+    var fixed = rows.filter(function (r) { return r.hasAttribute("data-fixed"); });
+    rows = rows.filter(function (r) { return !r.hasAttribute("data-fixed"); });
+    rows.sort(function (a, b) { var x = value(a), y = value(b); return x < y ? -dir : x > y ? dir : 0; });
+    rows.concat(fixed).forEach(function (r) { body.appendChild(r); });
+  });
+});
+</script>
+</body>
+</html>
+`))
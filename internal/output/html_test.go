@@ -0,0 +1,202 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+func TestRenderHTMLTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderHTMLTo(&buf, testMarkdownResult()); err != nil {
+		t.Fatalf("RenderHTMLTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>AWS Cost Diff: Dec 2024 → Jan 2025</title>",
+		`aria-label="Waterfall of changes"`,
+		"<title>EC2: +$100.00</title>",
+		`<th class="num">Change</th>`,
+		`<td class="num up" data-sort="100">▲ &#43;$100.00</td>`,
+		`<td class="num down" data-sort="-30">▼ -$30.00</td>`,
+		`<tr data-fixed><td class="muted">Other (2 items)</td>`,
+		`table.sortable th`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+
+	// The report must work offline
+	for _, external := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(output, external) {
+			t.Errorf("Output should not reference external assets (%q)", external)
+		}
+	}
+}
+
+func TestRenderHTMLTo_Escapes(t *testing.T) {
+	result := testMarkdownResult()
+	result.Items = []diff.Item{{Name: `<script>alert("x")</script>`, FromCost: 1, ToCost: 2, Diff: 1, DiffPct: 100}}
+
+	var buf bytes.Buffer
+	if err := RenderHTMLTo(&buf, result); err != nil {
+		t.Fatalf("RenderHTMLTo() error = %v", err)
+	}
+	if strings.Contains(buf.String(), `<script>alert`) {
+		t.Errorf("Item names should be escaped in the table and chart\n%s", buf.String())
+	}
+}
+
+func TestRenderHTMLTo_GateAndEmpty(t *testing.T) {
+	result := testMarkdownResult()
+	result.Items = nil
+	result.Gate = &diff.Gate{
+		Conditions: []string{"total>10%"},
+		Violations: []diff.Violation{{Condition: "total>10%", Kind: diff.ConditionTotal, Unit: diff.UnitPercent, Value: 12}},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderHTMLTo(&buf, result); err != nil {
+		t.Fatalf("RenderHTMLTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"No cost data found for the specified period.",
+		`<ul class="notes failed">`,
+		"total&gt;10%: total increased &#43;12.0%",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "<svg") {
+		t.Errorf("Output should have no chart without items\n%s", output)
+	}
+}
+
+func TestWaterfallChart_EndsAtToTotal(t *testing.T) {
+	result := testMarkdownResult()
+	result.Items = result.Items[:1] // the other changes were filtered out
+
+	chart := string(waterfallChart(result))
+	for _, want := range []string{
+		"<title>Dec 2024: $1000.00</title>",
+		"<title>EC2: +$100.00</title>",
+		"<title>Not shown: +$20.00</title>",
+		"<title>Jan 2025: $1120.00</title>",
+	} {
+		if !strings.Contains(chart, want) {
+			t.Errorf("Chart should contain %q\n%s", want, chart)
+		}
+	}
+}
+
+func TestRenderTopHTMLTo(t *testing.T) {
+	result := &diff.TopResult{
+		Period: diff.Period{
+			Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Total: 1000,
+		Items: []diff.TopItem{
+			{Name: "EC2", Cost: 600, Percent: 60},
+			{Name: "S3", Cost: 300, Percent: 30},
+			{Name: "Other (3 items)", Cost: 100, Percent: 10, Synthetic: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTopHTMLTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopHTMLTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"<title>AWS Top Costs: Jan 2025</title>",
+		`aria-label="Share of total"`,
+		"<title>EC2: $600.00 (60.0%)</title>",
+		`stroke="` + htmlColorOther + `"`,
+		`<td class="num">1</td><td>EC2</td>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+	if got := strings.Count(output, "<circle"); got != 3 {
+		t.Errorf("Donut has %d slices, want 3", got)
+	}
+}
+
+func TestRenderWatchHTMLTo(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &diff.WatchResult{
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, 3),
+		Total:     400,
+		Average:   133.33,
+		Anomalies: 1,
+		Days: []diff.DayItem{
+			{Date: start, Cost: 100, Scored: true, Expected: 100},
+			{Date: start.AddDate(0, 0, 1), Cost: 100, Scored: true, Expected: 100},
+			{Date: start.AddDate(0, 0, 2), Cost: 200, Change: 100, ChangePercent: 100,
+				Scored: true, Expected: 100, Score: 9.25, Anomaly: true, Severity: diff.SeverityHigh},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWatchHTMLTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchHTMLTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"<title>AWS Daily Costs: Jan 1 to Jan 4, 2025</title>",
+		"Anomalies: 1",
+		`aria-label="Daily costs"`,
+		"<title>Fri Jan 3: $200.00 ▲ high (z=9.2)</title>",
+		`fill="` + htmlColorUp + `"`,
+		"<polyline",
+		"Daily average: $133.33",
+		`<td class="num up" data-sort="9.25">▲ high (z=9.2)</td>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderWatchHTMLTo_Grouped(t *testing.T) {
+	result := &diff.WatchResult{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		Total:     240,
+		Average:   120,
+		Days: []diff.DayItem{
+			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 110, Groups: map[string]float64{"Amazon EC2": 100, "Amazon S3": 10}},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 130, Groups: map[string]float64{"Amazon EC2": 118, "Amazon S3": 12}},
+		},
+	}
+	result.SetGroups([]string{"service"}, 10)
+
+	var buf bytes.Buffer
+	if err := RenderWatchHTMLTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchHTMLTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`<th class="num">Amazon EC2</th>`,
+		`<tfoot><tr><td>Total</td><td></td><td class="num">$218.00</td>`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
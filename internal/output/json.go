@@ -42,6 +42,69 @@ func RenderWatchJSONTo(w io.Writer, result *diff.WatchResult) error {
 	return writeJSON(w, output)
 }
 
+// RenderForecastJSON outputs the forecast result as JSON to stdout
+func RenderForecastJSON(result *diff.ForecastResult) error {
+	return RenderForecastJSONTo(os.Stdout, result)
+}
+
+// RenderForecastJSONTo outputs the forecast result as JSON to the specified writer
+func RenderForecastJSONTo(w io.Writer, result *diff.ForecastResult) error {
+	output := result.ToJSON()
+	return writeJSON(w, output)
+}
+
+// RenderBudgetJSON outputs the budget result as JSON to stdout
+func RenderBudgetJSON(result *diff.BudgetResult) error {
+	return RenderBudgetJSONTo(os.Stdout, result)
+}
+
+// RenderBudgetJSONTo outputs the budget result as JSON to the specified writer
+func RenderBudgetJSONTo(w io.Writer, result *diff.BudgetResult) error {
+	output := result.ToJSON()
+	return writeJSON(w, output)
+}
+
+// RenderExplainJSON outputs the explain result as JSON to stdout
+func RenderExplainJSON(result *diff.ExplainResult) error {
+	return RenderExplainJSONTo(os.Stdout, result)
+}
+
+// RenderExplainJSONTo outputs the explain result as JSON to the specified writer
+func RenderExplainJSONTo(w io.Writer, result *diff.ExplainResult) error {
+	output := result.ToJSON()
+	return writeJSON(w, output)
+}
+
+// RenderChangeJSON outputs the change comparison as JSON to stdout
+func RenderChangeJSON(result *diff.ChangeResult) error {
+	return RenderChangeJSONTo(os.Stdout, result)
+}
+
+// RenderChangeJSONTo outputs the change comparison as JSON to the specified writer
+func RenderChangeJSONTo(w io.Writer, result *diff.ChangeResult) error {
+	output := result.ToJSON()
+	return writeJSON(w, output)
+}
+
+// Layouts for outputs that list a value per item and month
+const (
+	LayoutWide = "wide" // one row per item, one column per month
+	LayoutLong = "long" // one row per item and month
+)
+
+// RenderTrendJSON outputs the trend result as JSON to stdout
+func RenderTrendJSON(result *diff.TrendResult, layout string) error {
+	return RenderTrendJSONTo(os.Stdout, result, layout)
+}
+
+// RenderTrendJSONTo outputs the trend result as JSON in the given layout to the specified writer
+func RenderTrendJSONTo(w io.Writer, result *diff.TrendResult, layout string) error {
+	if layout == LayoutLong {
+		return writeJSON(w, result.ToLongJSON())
+	}
+	return writeJSON(w, result.ToJSON())
+}
+
 func writeJSON(w io.Writer, v interface{}) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -3,6 +3,7 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -135,6 +136,53 @@ func TestRenderTopJSONTo(t *testing.T) {
 	}
 }
 
+func TestRenderTopJSONTo_OtherRow(t *testing.T) {
+	result := &diff.TopResult{
+		Items: []diff.TopItem{
+			{Name: "EC2", Cost: 600, Percent: 60},
+			{Name: "Other (2 items)", Cost: 400, Percent: 40, Synthetic: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTopJSONTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopJSONTo() error = %v", err)
+	}
+
+	var output diff.TopResultJSON
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if output.Items[0].Synthetic || !output.Items[1].Synthetic {
+		t.Errorf("Synthetic = %v, %v, want false, true", output.Items[0].Synthetic, output.Items[1].Synthetic)
+	}
+	if strings.Count(buf.String(), `"synthetic"`) != 1 {
+		t.Error("synthetic should only be written for the Other row")
+	}
+}
+
+func TestRenderExplainJSONTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderExplainJSONTo(&buf, testExplainResult()); err != nil {
+		t.Fatalf("RenderExplainJSONTo() error = %v", err)
+	}
+
+	var output diff.ExplainResultJSON
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	if output.FromPeriod.Label != "Sep 2024" {
+		t.Errorf("FromPeriod.Label = %v, want Sep 2024", output.FromPeriod.Label)
+	}
+	if output.Root.Scores[0].Dimension != "region" {
+		t.Errorf("best dimension = %v, want region", output.Root.Scores[0].Dimension)
+	}
+	if got := output.Root.Children[0].Children[1].Name; got != "222" {
+		t.Errorf("nested child = %v, want 222", got)
+	}
+}
+
 func TestRenderWatchJSONTo(t *testing.T) {
 	result := &diff.WatchResult{
 		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
@@ -219,3 +267,140 @@ func containsIndentation(s string) bool {
 	return false
 }
 
+func TestRenderForecastJSONTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderForecastJSONTo(&buf, testForecastResult()); err != nil {
+		t.Fatalf("RenderForecastJSONTo() error = %v", err)
+	}
+
+	var parsed diff.ForecastResultJSON
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	if parsed.Baseline.Label != "Sep 2024" || parsed.BaselineTotal != 1000 || parsed.Interval != 80 {
+		t.Errorf("baseline = %+v (%v, %d%%)", parsed.Baseline, parsed.BaselineTotal, parsed.Interval)
+	}
+	if len(parsed.Months) != 1 {
+		t.Fatalf("got %d months, want 1", len(parsed.Months))
+	}
+	month := parsed.Months[0]
+	if month.Period.Start != "2024-10-01" || month.Total != 1150 || month.Diff != 150 || month.DiffPct != 15 {
+		t.Errorf("month = %+v", month)
+	}
+}
+
+func TestRenderTrendJSONTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderTrendJSONTo(&buf, testTrendResult(), LayoutWide); err != nil {
+		t.Fatalf("RenderTrendJSONTo() error = %v", err)
+	}
+
+	var wide diff.TrendResultJSON
+	if err := json.Unmarshal(buf.Bytes(), &wide); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(wide.Months) != 3 || len(wide.Items) != 2 {
+		t.Fatalf("got %d months and %d items, want 3 and 2", len(wide.Months), len(wide.Items))
+	}
+	if ec2 := wide.Items[0]; ec2.Name != "Amazon EC2" || ec2.Costs[2] != 200 || ec2.CAGR == nil {
+		t.Errorf("item 0 = %+v", ec2)
+	}
+	if !strings.Contains(buf.String(), `"cagr_percent": null`) {
+		t.Error("Undefined CAGR should be encoded as null")
+	}
+
+	buf.Reset()
+	if err := RenderTrendJSONTo(&buf, testTrendResult(), LayoutLong); err != nil {
+		t.Fatalf("RenderTrendJSONTo() error = %v", err)
+	}
+
+	var long diff.TrendResultLongJSON
+	if err := json.Unmarshal(buf.Bytes(), &long); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(long.Records) != 6 {
+		t.Fatalf("got %d records, want 6", len(long.Records))
+	}
+	if record := long.Records[0]; record.Month != "2024-08" || record.Name != "Amazon EC2" || record.Cost != 100 {
+		t.Errorf("record 0 = %+v", record)
+	}
+}
+
+func TestRenderBudgetJSONTo(t *testing.T) {
+	result := &diff.BudgetResult{
+		Period: diff.Period{
+			Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Dimension:   "service",
+		ElapsedDays: 15,
+		Total:       diff.NewBudgetStatus(1000, 600, 1240),
+		Items: []diff.BudgetItem{
+			{Name: "EC2", BudgetStatus: diff.NewBudgetStatus(1000, 600, 1240)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderBudgetJSONTo(&buf, result); err != nil {
+		t.Fatalf("RenderBudgetJSONTo() error = %v", err)
+	}
+
+	var output diff.BudgetResultJSON
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if output.Days != 31 || output.ElapsedDays != 15 || output.Period.Label != "Oct 2024" {
+		t.Errorf("period = %+v, %d of %d days", output.Period, output.ElapsedDays, output.Days)
+	}
+	// Item budget fields are flattened next to the name
+	if !strings.Contains(buf.String(), `"name": "EC2",
+      "budget": 1000`) {
+		t.Errorf("items should be flat:\n%s", buf.String())
+	}
+	if output.Items[0].Status != diff.BudgetAtRisk || output.Total.Variance != 240 {
+		t.Errorf("output = %+v", output)
+	}
+}
+
+func TestRenderJSONTo_Gate(t *testing.T) {
+	result := &diff.Result{Items: []diff.Item{}}
+	result.Evaluate([]diff.Condition{{Text: "total>10%", Kind: diff.ConditionTotal, Threshold: 10, Percent: true}})
+
+	var buf bytes.Buffer
+	if err := RenderJSONTo(&buf, result); err != nil {
+		t.Fatalf("RenderJSONTo() error = %v", err)
+	}
+
+	// A passed gate still lists an empty violations array
+	if !strings.Contains(buf.String(), `"violations": []`) || !strings.Contains(buf.String(), `"passed": true`) {
+		t.Errorf("gate should be written with an empty violations list:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := RenderJSONTo(&buf, &diff.Result{Items: []diff.Item{}}); err != nil {
+		t.Fatalf("RenderJSONTo() error = %v", err)
+	}
+	if strings.Contains(buf.String(), `"gate"`) {
+		t.Error("gate should only be written with --fail-on")
+	}
+}
+
+func TestRenderChangeJSONTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderChangeJSONTo(&buf, testChangeResult()); err != nil {
+		t.Fatalf("RenderChangeJSONTo() error = %v", err)
+	}
+
+	var parsed diff.ChangeResultJSON
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	if parsed.Old.Label != "Sep 2024 → Oct 2024" || parsed.New.ToPeriod.Start != "2024-11-01" || parsed.TotalDelta != 200 {
+		t.Errorf("sides = %+v, %+v (delta %v)", parsed.Old, parsed.New, parsed.TotalDelta)
+	}
+	if len(parsed.Items) != 3 || parsed.Items[0].Name != "Amazon EC2" || parsed.Items[0].Delta != 180 {
+		t.Errorf("items = %+v", parsed.Items)
+	}
+}
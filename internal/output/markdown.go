@@ -0,0 +1,489 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+// MarkdownVisibleRows is how many rows of a long list stay visible; the rest
+// go into a collapsed <details> section
+const MarkdownVisibleRows = 10
+
+// RenderMarkdown outputs the diff result as GitHub-flavored Markdown to stdout
+func RenderMarkdown(result *diff.Result) error {
+	return RenderMarkdownTo(os.Stdout, result)
+}
+
+// RenderMarkdownTo outputs the diff result as GitHub-flavored Markdown to the specified writer
+func RenderMarkdownTo(w io.Writer, result *diff.Result) error {
+	// Print header
+	title := fmt.Sprintf("AWS Cost Diff: %s → %s", result.FromLabel(), result.ToPeriod.Label(result.Fiscal))
+	if result.PerDay {
+		title += " (per day)"
+	}
+	fmt.Fprintf(w, "### %s\n\n", escapeMarkdown(title))
+
+	// Print summary
+	summary := fmt.Sprintf("**Total:** %s → %s (%s)",
+		FormatCurrency(result.FromTotal),
+		FormatCurrency(result.ToTotal),
+		plainChange(result.TotalDiff, result.TotalPct, false, false))
+	var increased, decreased int
+	for _, item := range result.Items {
+		switch {
+		case item.Synthetic:
+		case item.Diff > 0:
+			increased++
+		case item.Diff < 0:
+			decreased++
+		}
+	}
+	if increased+decreased > 0 {
+		summary += fmt.Sprintf(" · %d increased, %d decreased", increased, decreased)
+	}
+	fmt.Fprintln(w, summary)
+	if result.Budget != nil {
+		fmt.Fprintf(w, "\n%s\n", markdownBudgetLine(result.Budget))
+	}
+	fmt.Fprintln(w)
+
+	if len(result.Items) == 0 {
+		fmt.Fprint(w, "_No cost data found for the specified period._\n\n")
+		renderMarkdownGateTo(w, result.Gate)
+		return nil
+	}
+
+	// Build table
+	header := append(groupHeaders(result.GroupBy),
+		result.FromLabel(),
+		result.ToPeriod.Label(result.Fiscal),
+		"Change",
+	)
+	alignments := append(groupAlignments(result.GroupBy),
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+	)
+	showContribution := result.Pareto > 0
+	if showContribution {
+		header = append(header, "Contribution", "Cumulative", "Mix Shift")
+		alignments = append(alignments,
+			tablewriter.ALIGN_RIGHT,
+			tablewriter.ALIGN_RIGHT,
+			tablewriter.ALIGN_RIGHT,
+		)
+	}
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		header = append(header, budgetHeaders...)
+		alignments = append(alignments, budgetAlignments...)
+	}
+	hasCharges := result.HasCharges()
+	if hasCharges {
+		header = append(header, "Driver")
+		alignments = append(alignments, tablewriter.ALIGN_LEFT)
+	}
+
+	// Each item is a row, followed by its charge type rows with --charges
+	items := make([][][]string, len(result.Items))
+	for i, item := range result.Items {
+		row := markdownGroupCells(item.Name, item.Keys, item.Synthetic, result.GroupBy, result.IsMultiLevel())
+		row = append(row,
+			FormatCurrency(item.FromCost),
+			FormatCurrency(item.ToCost),
+			plainChange(item.Diff, item.DiffPct, item.IsNew, item.IsRemoved),
+		)
+		if showContribution {
+			row = append(row,
+				fmt.Sprintf("%.1f%%", item.ContributionPct),
+				fmt.Sprintf("%.1f%%", item.CumulativePct),
+				FormatShareShift(item.ShareShift),
+			)
+		}
+		if hasBudgets {
+			row = append(row, markdownBudgetCells(item.Budget)...)
+		}
+		if !hasCharges {
+			items[i] = [][]string{row}
+			continue
+		}
+
+		rows := [][]string{append(row, escapeMarkdown(item.Driver))}
+		for _, charge := range chargeRows(item) {
+			chargeRow := make([]string, len(groupHeaders(result.GroupBy)))
+			chargeRow[0] = "↳ " + escapeMarkdown(charge.Type)
+			chargeRow = append(chargeRow,
+				FormatCurrency(charge.FromCost),
+				FormatCurrency(charge.ToCost),
+				trendArrow(charge.Diff)+FormatChange(charge.Diff),
+			)
+			if showContribution {
+				chargeRow = append(chargeRow, "", "", "")
+			}
+			if hasBudgets {
+				chargeRow = append(chargeRow, make([]string, len(budgetHeaders))...)
+			}
+			rows = append(rows, append(chargeRow, ""))
+		}
+		items[i] = rows
+	}
+	renderMarkdownListTo(w, header, alignments, items)
+
+	if showContribution {
+		var explained float64
+		var count int
+		for _, item := range result.Items {
+			if item.Synthetic {
+				continue
+			}
+			explained = math.Max(explained, item.CumulativePct)
+			count++
+		}
+		summary := fmt.Sprintf("%d items explain", count)
+		if count == 1 {
+			summary = "1 item explains"
+		}
+		fmt.Fprintf(w, "_%s %.1f%% of the net change (--pareto %g)_\n\n", summary, explained, result.Pareto)
+	}
+
+	renderMarkdownGateTo(w, result.Gate)
+
+	return nil
+}
+
+// renderMarkdownGateTo prints the outcome of the --fail-on conditions as a list
+func renderMarkdownGateTo(w io.Writer, gate *diff.Gate) {
+	if gate == nil {
+		return
+	}
+
+	if gate.Passed {
+		fmt.Fprintf(w, "**Policy passed:** %s\n\n", escapeMarkdown(strings.Join(gate.Conditions, ", ")))
+		return
+	}
+
+	summary := fmt.Sprintf("Policy failed: %d violations", len(gate.Violations))
+	if len(gate.Violations) == 1 {
+		summary = "Policy failed: 1 violation"
+	}
+	fmt.Fprintf(w, "**%s**\n\n", summary)
+	for _, v := range gate.Violations {
+		fmt.Fprintf(w, "- `%s` %s\n", v.Condition, escapeMarkdown(FormatViolation(v)))
+	}
+	fmt.Fprintln(w)
+}
+
+// RenderTopMarkdown outputs the top result as GitHub-flavored Markdown to stdout
+func RenderTopMarkdown(result *diff.TopResult) error {
+	return RenderTopMarkdownTo(os.Stdout, result)
+}
+
+// RenderTopMarkdownTo outputs the top result as GitHub-flavored Markdown to the specified writer
+func RenderTopMarkdownTo(w io.Writer, result *diff.TopResult) error {
+	// Print header
+	fmt.Fprintf(w, "### %s\n\n", escapeMarkdown(fmt.Sprintf("AWS Top Costs: %s", result.Period.Label(result.Fiscal))))
+
+	// Print summary
+	fmt.Fprintf(w, "**Total:** %s\n", FormatCurrency(result.Total))
+	if result.Budget != nil {
+		fmt.Fprintf(w, "\n%s\n", markdownBudgetLine(result.Budget))
+	}
+	fmt.Fprintln(w)
+
+	if len(result.Items) == 0 {
+		fmt.Fprint(w, "_No cost data found for the specified period._\n\n")
+		return nil
+	}
+
+	// Build table
+	header := append([]string{"#"}, groupHeaders(result.GroupBy)...)
+	header = append(header, "Cost", "% of Total")
+	alignments := append([]int{tablewriter.ALIGN_RIGHT}, groupAlignments(result.GroupBy)...)
+	alignments = append(alignments,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+	)
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		header = append(header, budgetHeaders...)
+		alignments = append(alignments, budgetAlignments...)
+	}
+
+	items := make([][][]string, len(result.Items))
+	for i, item := range result.Items {
+		rank := fmt.Sprintf("%d", i+1)
+		if item.Synthetic {
+			rank = ""
+		}
+		row := append([]string{rank},
+			markdownGroupCells(item.Name, item.Keys, item.Synthetic, result.GroupBy, result.IsMultiLevel())...)
+		row = append(row,
+			FormatCurrency(item.Cost),
+			fmt.Sprintf("%.1f%%", item.Percent),
+		)
+		if hasBudgets {
+			row = append(row, markdownBudgetCells(item.Budget)...)
+		}
+		items[i] = [][]string{row}
+	}
+	renderMarkdownListTo(w, header, alignments, items)
+
+	return nil
+}
+
+// RenderWatchMarkdown outputs the watch result as GitHub-flavored Markdown to stdout
+func RenderWatchMarkdown(result *diff.WatchResult) error {
+	return RenderWatchMarkdownTo(os.Stdout, result)
+}
+
+// RenderWatchMarkdownTo outputs the watch result as GitHub-flavored Markdown to
+// the specified writer. Long day tables are collapsed below the bar chart.
+func RenderWatchMarkdownTo(w io.Writer, result *diff.WatchResult) error {
+	// Print header
+	fmt.Fprintf(w, "### AWS Daily Costs: %s to %s\n\n",
+		result.StartDate.Format("Jan 2"),
+		result.EndDate.Format("Jan 2, 2006"))
+
+	// Print summary
+	fmt.Fprintf(w, "**Total:** %s · **Daily Average:** %s",
+		FormatCurrency(result.Total),
+		FormatCurrency(result.Average))
+	if result.Anomalies > 0 {
+		fmt.Fprintf(w, " · **Anomalies:** %d", result.Anomalies)
+	}
+	fmt.Fprint(w, "\n\n")
+
+	if len(result.Days) == 0 {
+		fmt.Fprint(w, "_No cost data found for the specified period._\n\n")
+		return nil
+	}
+
+	// Print bar chart
+	if chart := markdownBarChart(result.Days); chart != "" {
+		fmt.Fprintf(w, "```text\n%s```\n\n", chart)
+	}
+
+	// Build table
+	var header []string
+	var alignments []int
+	rows := make([][]string, len(result.Days))
+	if result.IsGrouped() {
+		header = []string{"Date", "Day"}
+		alignments = []int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT}
+		for _, group := range result.Groups {
+			header = append(header, group.Name)
+			alignments = append(alignments, tablewriter.ALIGN_RIGHT)
+		}
+		header = append(header, "Total", "Anomaly")
+		alignments = append(alignments, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT)
+
+		for i, day := range result.Days {
+			row := []string{day.Date.Format("Jan 2"), day.Date.Format("Mon")}
+			for _, group := range result.Groups {
+				row = append(row, FormatCurrency(day.Groups[group.Name]))
+			}
+			rows[i] = append(row, FormatCurrency(day.Cost), plainAnomaly(day))
+		}
+
+		footer := []string{"**Total**", ""}
+		for _, group := range result.Groups {
+			footer = append(footer, "**"+FormatCurrency(group.Total)+"**")
+		}
+		rows = append(rows, append(footer, "**"+FormatCurrency(result.Total)+"**", ""))
+	} else {
+		header = []string{"Date", "Day", "Cost", "Change", "Anomaly"}
+		alignments = []int{
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_LEFT,
+			tablewriter.ALIGN_RIGHT,
+			tablewriter.ALIGN_RIGHT,
+			tablewriter.ALIGN_LEFT,
+		}
+
+		for i, day := range result.Days {
+			change := "-"
+			if i > 0 {
+				change = plainChange(day.Change, day.ChangePercent, false, false)
+			}
+			rows[i] = []string{
+				day.Date.Format("Jan 2"),
+				day.Date.Format("Mon"),
+				FormatCurrency(day.Cost),
+				change,
+				plainAnomaly(day),
+			}
+		}
+	}
+
+	if len(result.Days) <= MarkdownVisibleRows {
+		writeMarkdownTable(w, header, alignments, rows)
+		fmt.Fprintln(w)
+		return nil
+	}
+
+	fmt.Fprintf(w, "<details>\n<summary>Daily costs (%d days)</summary>\n\n", len(result.Days))
+	writeMarkdownTable(w, header, alignments, rows)
+	fmt.Fprint(w, "\n</details>\n\n")
+
+	return nil
+}
+
+// renderMarkdownListTo writes a table with one group of rows per item. When
+// there are more than MarkdownVisibleRows items, the rest are collapsed into
+// a <details> section below the table; a single extra item is shown instead.
+func renderMarkdownListTo(w io.Writer, header []string, alignments []int, items [][][]string) {
+	visible := len(items)
+	if visible > MarkdownVisibleRows+1 {
+		visible = MarkdownVisibleRows
+	}
+
+	writeMarkdownTable(w, header, alignments, flattenRows(items[:visible]))
+	fmt.Fprintln(w)
+
+	if hidden := items[visible:]; len(hidden) > 0 {
+		fmt.Fprintf(w, "<details>\n<summary>%d more items</summary>\n\n", len(hidden))
+		writeMarkdownTable(w, header, alignments, flattenRows(hidden))
+		fmt.Fprint(w, "\n</details>\n\n")
+	}
+}
+
+// flattenRows joins the rows of several items
+func flattenRows(items [][][]string) [][]string {
+	var rows [][]string
+	for _, item := range items {
+		rows = append(rows, item...)
+	}
+	return rows
+}
+
+// writeMarkdownTable writes a GitHub-flavored Markdown table. Alignments are
+// the tablewriter ones used by the terminal tables. Header cells are plain text
+// and get escaped; row cells are Markdown, with any text from cost data
+// already escaped, so that they can add emphasis.
+func writeMarkdownTable(w io.Writer, header []string, alignments []int, rows [][]string) {
+	cells := make([]string, len(header))
+	for i, h := range header {
+		cells[i] = escapeMarkdown(h)
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+
+	for i := range cells {
+		cells[i] = "---"
+		if i < len(alignments) && alignments[i] == tablewriter.ALIGN_RIGHT {
+			cells[i] = "---:"
+		}
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+
+	for _, row := range rows {
+		fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+	}
+}
+
+// markdownGroupCells returns the group name cell(s) for an item, untruncated.
+// "Other" rows are italic, with the name in the first group column.
+func markdownGroupCells(name string, keys []string, synthetic bool, groupBy []string, multiLevel bool) []string {
+	if synthetic {
+		cells := make([]string, len(groupHeaders(groupBy)))
+		cells[0] = "_" + escapeMarkdown(name) + "_"
+		return cells
+	}
+	if !multiLevel {
+		return []string{escapeMarkdown(name)}
+	}
+	cells := make([]string, len(keys))
+	for i, key := range keys {
+		cells[i] = escapeMarkdown(key)
+	}
+	return cells
+}
+
+// markdownBudgetCells returns the budget cells of an item, like budgetCells
+// without colors
+func markdownBudgetCells(status *diff.BudgetStatus) []string {
+	if status == nil {
+		return []string{"-", "-", "-", ""}
+	}
+	return []string{
+		FormatCurrency(status.Budget),
+		FormatCurrency(status.Projected),
+		plainChange(status.Variance, status.VariancePct, false, false),
+		markdownBudgetState(status.Status),
+	}
+}
+
+// markdownBudgetLine summarizes total spend against the overall budget
+func markdownBudgetLine(status *diff.BudgetStatus) string {
+	return fmt.Sprintf("**Budget:** %s, projected %s (%s) %s",
+		FormatCurrency(status.Budget),
+		FormatCurrency(status.Projected),
+		plainChange(status.Variance, status.VariancePct, false, false),
+		markdownBudgetState(status.Status))
+}
+
+// markdownBudgetState returns the label of a budget state, bold when it needs attention
+func markdownBudgetState(state diff.BudgetState) string {
+	switch state {
+	case diff.BudgetOver:
+		return "**over**"
+	case diff.BudgetAtRisk:
+		return "**at risk**"
+	case diff.BudgetOK:
+		return "ok"
+	case diff.BudgetUnbudgeted:
+		return "unbudgeted"
+	}
+	return string(state)
+}
+
+// markdownBarChart renders the daily bar chart of the terminal output as plain
+// text for a code block
+func markdownBarChart(days []diff.DayItem) string {
+	maxCost := maxDayCost(days)
+	if maxCost == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, day := range days {
+		width := barWidth(day.Cost, maxCost)
+		line := fmt.Sprintf("%-6s %s%s %s",
+			day.Date.Format("Jan 2"),
+			strings.Repeat("█", width),
+			strings.Repeat(" ", BarChartMaxWidth-width),
+			FormatCurrency(day.Cost))
+		if day.Anomaly {
+			line += " " + plainAnomaly(day)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// markdownEscaper escapes the characters that would break a table cell or be
+// read as emphasis, code, links or HTML
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"_", `\_`,
+	"*", `\*`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"\n", " ",
+)
+
+// escapeMarkdown makes text from cost data safe to use in headings, list items
+// and table cells
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,279 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+func testMarkdownResult() *diff.Result {
+	return &diff.Result{
+		FromPeriod: diff.Period{
+			Start: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		ToPeriod: diff.Period{
+			Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		FromTotal: 1000,
+		ToTotal:   1120,
+		TotalDiff: 120,
+		TotalPct:  12,
+		Items: []diff.Item{
+			{Name: "EC2", FromCost: 500, ToCost: 600, Diff: 100, DiffPct: 20},
+			{Name: "S3", FromCost: 300, ToCost: 270, Diff: -30, DiffPct: -10},
+			{Name: "Lambda", ToCost: 50, Diff: 50, IsNew: true},
+			{Name: "Other (2 items)", FromCost: 200, ToCost: 200, Synthetic: true},
+		},
+	}
+}
+
+func TestRenderMarkdownTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderMarkdownTo(&buf, testMarkdownResult()); err != nil {
+		t.Fatalf("RenderMarkdownTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"### AWS Cost Diff: Dec 2024 → Jan 2025",
+		"**Total:** $1000.00 → $1120.00 (▲ +$120.00 (+12.0%)) · 2 increased, 1 decreased",
+		"| Service | Dec 2024 | Jan 2025 | Change |",
+		"| --- | ---: | ---: | ---: |",
+		"| EC2 | $500.00 | $600.00 | ▲ +$100.00 (+20.0%) |",
+		"| S3 | $300.00 | $270.00 | ▼ -$30.00 (-10.0%) |",
+		"| Lambda | $0.00 | $50.00 | ▲ +$50.00 (new) |",
+		"| _Other (2 items)_ | $200.00 | $200.00 | +$0.00 (+0.0%) |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "\x1b[") || strings.Contains(output, "<details>") {
+		t.Errorf("Output should have no colors and no collapsed rows\n%s", output)
+	}
+}
+
+func TestRenderMarkdownTo_CollapsesLongLists(t *testing.T) {
+	result := testMarkdownResult()
+	result.Items = nil
+	for i := 1; i <= 14; i++ {
+		result.Items = append(result.Items, diff.Item{Name: fmt.Sprintf("Service %02d", i), ToCost: 10, Diff: 10, IsNew: true})
+	}
+
+	var buf bytes.Buffer
+	if err := RenderMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderMarkdownTo() error = %v", err)
+	}
+
+	output := buf.String()
+	visible, collapsed, found := strings.Cut(output, "<details>\n<summary>4 more items</summary>")
+	if !found {
+		t.Fatalf("Output should collapse 4 items\n%s", output)
+	}
+	if !strings.Contains(visible, "| Service 10 |") || strings.Contains(visible, "| Service 11 |") {
+		t.Errorf("The first %d items should be visible\n%s", MarkdownVisibleRows, output)
+	}
+	if !strings.Contains(collapsed, "| Service 11 |") || !strings.Contains(collapsed, "</details>") {
+		t.Errorf("The remaining items should be collapsed\n%s", output)
+	}
+
+	// A single extra row is not worth collapsing
+	result.Items = result.Items[:MarkdownVisibleRows+1]
+	buf.Reset()
+	if err := RenderMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderMarkdownTo() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "<details>") {
+		t.Errorf("Output should not collapse a single row\n%s", buf.String())
+	}
+}
+
+func TestRenderMarkdownTo_GateAndBudget(t *testing.T) {
+	result := testMarkdownResult()
+	result.Budget = &diff.BudgetStatus{Budget: 1000, Projected: 1120, Variance: 120, VariancePct: 12, Status: diff.BudgetOver}
+	result.Gate = &diff.Gate{
+		Conditions: []string{"total>10%"},
+		Violations: []diff.Violation{{Condition: "total>10%", Kind: diff.ConditionTotal, Unit: diff.UnitPercent, Value: 12}},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderMarkdownTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"**Budget:** $1000.00, projected $1120.00 (▲ +$120.00 (+12.0%)) **over**",
+		"**Policy failed: 1 violation**",
+		"- `total>10%` total increased +12.0%",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderMarkdownTo_Escapes(t *testing.T) {
+	result := testMarkdownResult()
+	result.Items = []diff.Item{
+		{Name: "a|b", FromCost: 1, ToCost: 2, Diff: 1, DiffPct: 100},
+		{Name: "team_a_b", FromCost: 1, ToCost: 2, Diff: 1, DiffPct: 100},
+		{Name: "<details>", FromCost: 1, ToCost: 2, Diff: 1, DiffPct: 100},
+		{Name: "*[`x`]*", FromCost: 1, ToCost: 2, Diff: 1, DiffPct: 100},
+		{Name: "Other (2 items)", FromCost: 2, ToCost: 2, Synthetic: true},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderMarkdownTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`| a\|b |`,
+		`| team\_a\_b |`,
+		`| \<details\> |`,
+		"| \\*\\[\\`x\\`\\]\\* |",
+		"| _Other (2 items)_ |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "<details>") {
+		t.Errorf("Item names should not open HTML elements\n%s", output)
+	}
+}
+
+func TestRenderTopMarkdownTo(t *testing.T) {
+	result := &diff.TopResult{
+		Period: diff.Period{
+			Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		GroupBy: []string{"service", "region"},
+		Total:   1000,
+		Items: []diff.TopItem{
+			{Name: "EC2 / us-east-1", Keys: []string{"EC2", "us-east-1"}, Cost: 600, Percent: 60},
+			{Name: "S3 / eu-west-1", Keys: []string{"S3", "eu-west-1"}, Cost: 300, Percent: 30},
+			{Name: "Other (3 items)", Cost: 100, Percent: 10, Synthetic: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTopMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopMarkdownTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"### AWS Top Costs: Jan 2025",
+		"**Total:** $1000.00",
+		"| # | Service | Region | Cost | % of Total |",
+		"| ---: | --- | --- | ---: | ---: |",
+		"| 1 | EC2 | us-east-1 | $600.00 | 60.0% |",
+		"|  | _Other (3 items)_ |  | $100.00 | 10.0% |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderWatchMarkdownTo(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &diff.WatchResult{
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, 12),
+		Total:     1300,
+		Average:   100,
+		Anomalies: 1,
+	}
+	for i := 0; i < 12; i++ {
+		day := diff.DayItem{Date: start.AddDate(0, 0, i), Cost: 100}
+		if i == 5 {
+			day = diff.DayItem{Date: start.AddDate(0, 0, i), Cost: 200, Change: 100, ChangePercent: 100,
+				Scored: true, Score: 9.25, Anomaly: true, Severity: diff.SeverityHigh}
+		}
+		result.Days = append(result.Days, day)
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWatchMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchMarkdownTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"### AWS Daily Costs: Jan 1 to Jan 13, 2025",
+		"**Total:** $1300.00 · **Daily Average:** $100.00 · **Anomalies:** 1",
+		"```text\nJan 1  " + strings.Repeat("█", BarChartMaxWidth/2),
+		"Jan 6  " + strings.Repeat("█", BarChartMaxWidth) + " $200.00 ▲ high (z=9.2)",
+		"<details>\n<summary>Daily costs (12 days)</summary>",
+		"| Jan 6 | Mon | $200.00 | ▲ +$100.00 (+100.0%) | ▲ high (z=9.2) |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+
+	// The chart comes before the collapsed table
+	if strings.Index(output, "```text") > strings.Index(output, "<details>") {
+		t.Errorf("The bar chart should come first\n%s", output)
+	}
+}
+
+func TestRenderWatchMarkdownTo_Grouped(t *testing.T) {
+	result := &diff.WatchResult{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		Total:     240,
+		Average:   120,
+		Days: []diff.DayItem{
+			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 110, Groups: map[string]float64{"Amazon EC2": 100, "Amazon S3": 10}},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 130, Groups: map[string]float64{"Amazon EC2": 118, "Amazon S3": 12}},
+		},
+	}
+	result.SetGroups([]string{"service"}, 10)
+
+	var buf bytes.Buffer
+	if err := RenderWatchMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchMarkdownTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"| Date | Day | Amazon EC2 | Amazon S3 | Total | Anomaly |",
+		"| Jan 2 | Thu | $118.00 | $12.00 | $130.00 |  |",
+		"| **Total** |  | **$218.00** | **$22.00** | **$240.00** |  |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "<details>") {
+		t.Errorf("Short watches should not be collapsed\n%s", output)
+	}
+}
+
+func TestRenderWatchMarkdownTo_EmptyDays(t *testing.T) {
+	result := &diff.WatchResult{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC),
+		Days:      []diff.DayItem{},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWatchMarkdownTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchMarkdownTo() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "_No cost data found for the specified period._") {
+		t.Errorf("Output should contain the empty message\n%s", buf.String())
+	}
+}
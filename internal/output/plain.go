@@ -0,0 +1,41 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+// plainChange formats a change like FormatDiffFull, with an arrow instead of
+// color, for the Markdown and HTML reports
+func plainChange(change, pct float64, isNew, isRemoved bool) string {
+	var text string
+	switch {
+	case isNew:
+		text = fmt.Sprintf("+$%.2f (new)", change)
+	case isRemoved:
+		text = fmt.Sprintf("-$%.2f (removed)", -change)
+	default:
+		text = fmt.Sprintf("%s (%s)", FormatChange(change), FormatPercent(pct))
+	}
+	return trendArrow(change) + text
+}
+
+// trendArrow returns "▲ " for increases, "▼ " for decreases and "" otherwise
+func trendArrow(change float64) string {
+	switch {
+	case change > 0:
+		return "▲ "
+	case change < 0:
+		return "▼ "
+	}
+	return ""
+}
+
+// plainAnomaly describes an anomalous day like FormatAnomaly, without colors
+func plainAnomaly(day diff.DayItem) string {
+	if !day.Anomaly {
+		return ""
+	}
+	return fmt.Sprintf("%s%s (z=%.1f)", trendArrow(day.Score), day.Severity, day.Score)
+}
@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"strings"
 
@@ -19,30 +20,49 @@ func RenderTable(result *diff.Result) error {
 // RenderTableTo outputs the diff result as a formatted table to the specified writer
 func RenderTableTo(w io.Writer, result *diff.Result) error {
 	// Print header
-	fmt.Fprintf(w, "\n%s\n\n", Header(fmt.Sprintf("AWS Cost Diff: %s → %s",
-		result.FromPeriod.Label(),
-		result.ToPeriod.Label())))
+	title := fmt.Sprintf("AWS Cost Diff: %s → %s", result.FromLabel(), result.ToPeriod.Label(result.Fiscal))
+	if result.PerDay {
+		title += " (per day)"
+	}
+	fmt.Fprintf(w, "\n%s\n\n", Header(title))
 
 	// Print total
 	totalChange := FormatDiffFull(result.TotalDiff, result.TotalPct, false, false)
-	fmt.Fprintf(w, "Total: %s → %s (%s)\n\n",
+	fmt.Fprintf(w, "Total: %s → %s (%s)\n",
 		FormatCurrency(result.FromTotal),
 		FormatCurrency(result.ToTotal),
 		totalChange)
+	if result.Budget != nil {
+		fmt.Fprintln(w, budgetLine(result.Budget))
+	}
+	fmt.Fprintln(w)
 
 	if len(result.Items) == 0 {
 		fmt.Fprintln(w, Muted("No cost data found for the specified period."))
+		renderGateTo(w, result.Gate)
 		return nil
 	}
 
 	// Create table
 	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{
-		"Service",
-		result.FromPeriod.Label(),
-		result.ToPeriod.Label(),
+	header := append(groupHeaders(result.GroupBy),
+		result.FromLabel(),
+		result.ToPeriod.Label(result.Fiscal),
 		"Change",
-	})
+	)
+	showContribution := result.Pareto > 0
+	if showContribution {
+		header = append(header, "Contribution", "Cumulative", "Mix Shift")
+	}
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		header = append(header, budgetHeaders...)
+	}
+	hasCharges := result.HasCharges()
+	if hasCharges {
+		header = append(header, "Driver")
+	}
+	table.SetHeader(header)
 
 	// Configure table style
 	table.SetBorder(false)
@@ -51,30 +71,138 @@ func RenderTableTo(w io.Writer, result *diff.Result) error {
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAlignment(tablewriter.ALIGN_RIGHT)
 	table.SetAutoWrapText(false)
-	table.SetColumnAlignment([]int{
-		tablewriter.ALIGN_LEFT,
+	alignments := append(groupAlignments(result.GroupBy),
 		tablewriter.ALIGN_RIGHT,
 		tablewriter.ALIGN_RIGHT,
 		tablewriter.ALIGN_RIGHT,
-	})
+	)
+	if showContribution {
+		alignments = append(alignments,
+			tablewriter.ALIGN_RIGHT,
+			tablewriter.ALIGN_RIGHT,
+			tablewriter.ALIGN_RIGHT,
+		)
+	}
+	if hasBudgets {
+		alignments = append(alignments, budgetAlignments...)
+	}
+	table.SetColumnAlignment(append(alignments, tablewriter.ALIGN_LEFT))
 
 	// Add rows
 	for _, item := range result.Items {
 		change := FormatDiffFull(item.Diff, item.DiffPct, item.IsNew, item.IsRemoved)
-		table.Append([]string{
-			Truncate(item.Name, ServiceNameMaxWidth),
+		row := groupCells(item.Name, item.Keys, result.IsMultiLevel(), ServiceNameMaxWidth)
+		if item.Synthetic {
+			row = otherCells(item.Name, result.GroupBy)
+		}
+		row = append(row,
 			FormatCurrency(item.FromCost),
 			FormatCurrency(item.ToCost),
 			change,
-		})
+		)
+		if showContribution {
+			row = append(row,
+				fmt.Sprintf("%.1f%%", item.ContributionPct),
+				fmt.Sprintf("%.1f%%", item.CumulativePct),
+				FormatShareShift(item.ShareShift),
+			)
+		}
+		if hasBudgets {
+			row = append(row, budgetCells(item.Budget)...)
+		}
+		if !hasCharges {
+			table.Append(row)
+			continue
+		}
+
+		table.Append(append(row, item.Driver))
+		for _, charge := range chargeRows(item) {
+			chargeRow := []string{
+				Muted("  ↳ " + Truncate(charge.Type, ServiceNameMaxWidth-4)),
+				FormatCurrency(charge.FromCost),
+				FormatCurrency(charge.ToCost),
+				ColorizeChange(charge.Diff, FormatChange(charge.Diff)),
+			}
+			if showContribution {
+				chargeRow = append(chargeRow, "", "", "")
+			}
+			if hasBudgets {
+				chargeRow = append(chargeRow, make([]string, len(budgetHeaders))...)
+			}
+			table.Append(append(chargeRow, ""))
+		}
 	}
 
 	table.Render()
 	fmt.Fprintln(w)
 
+	if showContribution {
+		var explained float64
+		var count int
+		for _, item := range result.Items {
+			if item.Synthetic {
+				continue
+			}
+			explained = math.Max(explained, item.CumulativePct)
+			count++
+		}
+		summary := fmt.Sprintf("%d items explain", count)
+		if count == 1 {
+			summary = "1 item explains"
+		}
+		fmt.Fprintf(w, "%s\n\n", Muted(fmt.Sprintf("%s %.1f%% of the net change (--pareto %g)",
+			summary, explained, result.Pareto)))
+	}
+
+	renderGateTo(w, result.Gate)
+
 	return nil
 }
 
+// renderGateTo prints the outcome of the --fail-on conditions, one line per violation
+func renderGateTo(w io.Writer, gate *diff.Gate) {
+	if gate == nil {
+		return
+	}
+
+	if gate.Passed {
+		fmt.Fprintf(w, "%s\n\n", Success("Policy passed: "+strings.Join(gate.Conditions, ", ")))
+		return
+	}
+
+	summary := fmt.Sprintf("Policy failed: %d violations", len(gate.Violations))
+	if len(gate.Violations) == 1 {
+		summary = "Policy failed: 1 violation"
+	}
+	fmt.Fprintln(w, Error(summary))
+	for _, v := range gate.Violations {
+		fmt.Fprintf(w, "  %s  %s\n", Error(v.Condition), FormatViolation(v))
+	}
+	fmt.Fprintln(w)
+}
+
+// FormatViolation describes what crossed a --fail-on threshold
+func FormatViolation(v diff.Violation) string {
+	switch v.Kind {
+	case diff.ConditionTotal:
+		if v.Unit == diff.UnitPercent {
+			return fmt.Sprintf("total increased %s", FormatPercent(v.Value))
+		}
+		return fmt.Sprintf("total increased %s", FormatChange(v.Value))
+	case diff.ConditionNew:
+		return fmt.Sprintf("%s is new at %s", v.Name, FormatCurrency(v.Value))
+	}
+	return fmt.Sprintf("%s increased %s", v.Name, FormatChange(v.Value))
+}
+
+// FormatShareShift formats a change in share of total, in percentage points
+func FormatShareShift(points float64) string {
+	if points >= 0 {
+		return fmt.Sprintf("+%.1f pts", points)
+	}
+	return fmt.Sprintf("%.1f pts", points)
+}
+
 // RenderTopTable outputs the top result as a formatted table to stdout
 func RenderTopTable(result *diff.TopResult) error {
 	return RenderTopTableTo(os.Stdout, result)
@@ -83,10 +211,14 @@ func RenderTopTable(result *diff.TopResult) error {
 // RenderTopTableTo outputs the top result as a formatted table to the specified writer
 func RenderTopTableTo(w io.Writer, result *diff.TopResult) error {
 	// Print header
-	fmt.Fprintf(w, "\n%s\n\n", Header(fmt.Sprintf("AWS Top Costs: %s", result.Period.Label())))
+	fmt.Fprintf(w, "\n%s\n\n", Header(fmt.Sprintf("AWS Top Costs: %s", result.Period.Label(result.Fiscal))))
 
 	// Print total
-	fmt.Fprintf(w, "Total: %s\n\n", FormatCurrency(result.Total))
+	fmt.Fprintf(w, "Total: %s\n", FormatCurrency(result.Total))
+	if result.Budget != nil {
+		fmt.Fprintln(w, budgetLine(result.Budget))
+	}
+	fmt.Fprintln(w)
 
 	if len(result.Items) == 0 {
 		fmt.Fprintln(w, Muted("No cost data found for the specified period."))
@@ -95,7 +227,13 @@ func RenderTopTableTo(w io.Writer, result *diff.TopResult) error {
 
 	// Create table
 	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"#", "Service", "Cost", "% of Total"})
+	header := append([]string{"#"}, groupHeaders(result.GroupBy)...)
+	header = append(header, "Cost", "% of Total")
+	hasBudgets := result.HasBudgets()
+	if hasBudgets {
+		header = append(header, budgetHeaders...)
+	}
+	table.SetHeader(header)
 
 	// Configure table style
 	table.SetBorder(false)
@@ -104,21 +242,31 @@ func RenderTopTableTo(w io.Writer, result *diff.TopResult) error {
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAlignment(tablewriter.ALIGN_RIGHT)
 	table.SetAutoWrapText(false)
-	table.SetColumnAlignment([]int{
-		tablewriter.ALIGN_RIGHT,
-		tablewriter.ALIGN_LEFT,
+	alignments := append([]int{tablewriter.ALIGN_RIGHT}, groupAlignments(result.GroupBy)...)
+	alignments = append(alignments,
 		tablewriter.ALIGN_RIGHT,
 		tablewriter.ALIGN_RIGHT,
-	})
+	)
+	if hasBudgets {
+		alignments = append(alignments, budgetAlignments...)
+	}
+	table.SetColumnAlignment(alignments)
 
 	// Add rows
 	for i, item := range result.Items {
-		table.Append([]string{
-			fmt.Sprintf("%d", i+1),
-			Truncate(item.Name, TopServiceNameMaxWidth),
+		row := append([]string{fmt.Sprintf("%d", i+1)},
+			groupCells(item.Name, item.Keys, result.IsMultiLevel(), TopServiceNameMaxWidth)...)
+		if item.Synthetic {
+			row = append([]string{""}, otherCells(item.Name, result.GroupBy)...)
+		}
+		row = append(row,
 			FormatCurrency(item.Cost),
 			fmt.Sprintf("%.1f%%", item.Percent),
-		})
+		)
+		if hasBudgets {
+			row = append(row, budgetCells(item.Budget)...)
+		}
+		table.Append(row)
 	}
 
 	table.Render()
@@ -140,18 +288,38 @@ func RenderWatchTableTo(w io.Writer, result *diff.WatchResult) error {
 		result.EndDate.Format("Jan 2, 2006"))))
 
 	// Print summary
-	fmt.Fprintf(w, "Total: %s  |  Daily Average: %s\n\n",
+	fmt.Fprintf(w, "Total: %s  |  Daily Average: %s",
 		FormatCurrency(result.Total),
 		FormatCurrency(result.Average))
+	if result.Anomalies > 0 {
+		fmt.Fprintf(w, "  |  %s", Warning(fmt.Sprintf("Anomalies: %d", result.Anomalies)))
+	}
+	fmt.Fprint(w, "\n\n")
 
 	if len(result.Days) == 0 {
 		fmt.Fprintln(w, Muted("No cost data found for the specified period."))
 		return nil
 	}
 
+	if result.IsGrouped() {
+		renderWatchMatrixTo(w, result)
+	} else {
+		renderWatchDaysTo(w, result)
+	}
+
+	// Print visual bar chart
+	fmt.Fprintln(w)
+	renderBarChartTo(w, result.Days, result.Average)
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// renderWatchDaysTo renders one row per day with its change from the day before
+func renderWatchDaysTo(w io.Writer, result *diff.WatchResult) {
 	// Create table
 	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"Date", "Day", "Cost", "Change"})
+	table.SetHeader([]string{"Date", "Day", "Cost", "Change", "Anomaly"})
 
 	// Configure table style
 	table.SetBorder(false)
@@ -165,6 +333,7 @@ func RenderWatchTableTo(w io.Writer, result *diff.WatchResult) error {
 		tablewriter.ALIGN_LEFT,
 		tablewriter.ALIGN_RIGHT,
 		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_LEFT,
 	})
 
 	// Add rows
@@ -181,14 +350,447 @@ func RenderWatchTableTo(w io.Writer, result *diff.WatchResult) error {
 			day.Date.Format("Mon"),
 			FormatCurrency(day.Cost),
 			changeStr,
+			FormatAnomaly(day),
 		})
 	}
 
 	table.Render()
+}
 
-	// Print visual bar chart
+// renderWatchMatrixTo renders a day × group matrix with a total per day and per group
+func renderWatchMatrixTo(w io.Writer, result *diff.WatchResult) {
+	// Create table
+	table := tablewriter.NewWriter(w)
+	header := []string{"Date", "Day"}
+	for _, group := range result.Groups {
+		header = append(header, Truncate(group.Name, WatchGroupMaxWidth))
+	}
+	table.SetHeader(append(header, "Total", "Anomaly"))
+
+	// Configure table style
+	table.SetBorder(false)
+	table.SetHeaderLine(true)
+	table.SetColumnSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetAutoWrapText(false)
+	alignments := []int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT}
+	for range result.Groups {
+		alignments = append(alignments, tablewriter.ALIGN_RIGHT)
+	}
+	table.SetColumnAlignment(append(alignments, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_LEFT))
+
+	// Add rows
+	for _, day := range result.Days {
+		row := []string{day.Date.Format("Jan 2"), day.Date.Format("Mon")}
+		for _, group := range result.Groups {
+			row = append(row, FormatCurrency(day.Groups[group.Name]))
+		}
+		table.Append(append(row, FormatCurrency(day.Cost), FormatAnomaly(day)))
+	}
+
+	// Add per-group totals
+	footer := []string{"Total", ""}
+	for _, group := range result.Groups {
+		footer = append(footer, FormatCurrency(group.Total))
+	}
+	table.SetFooter(append(footer, FormatCurrency(result.Total), ""))
+	table.SetFooterAlignment(tablewriter.ALIGN_RIGHT)
+
+	table.Render()
+}
+
+// RenderForecastTable outputs the forecast result as a formatted table to stdout
+func RenderForecastTable(result *diff.ForecastResult) error {
+	return RenderForecastTableTo(os.Stdout, result)
+}
+
+// RenderForecastTableTo outputs the forecast result as a formatted table to the specified writer
+func RenderForecastTableTo(w io.Writer, result *diff.ForecastResult) error {
+	// Print header
+	title := "AWS Cost Forecast"
+	if len(result.Months) > 0 {
+		title = fmt.Sprintf("AWS Cost Forecast: %s", result.Months[0].Period.Label(result.Fiscal))
+		if len(result.Months) > 1 {
+			title = fmt.Sprintf("AWS Cost Forecast: %s → %s", result.Months[0].Period.Label(result.Fiscal), result.Months[len(result.Months)-1].Period.Label(result.Fiscal))
+		}
+	}
+	fmt.Fprintf(w, "\n%s\n\n", Header(title))
+
+	// Print baseline
+	fmt.Fprintf(w, "Baseline: %s (%s actual)\n\n",
+		FormatCurrency(result.BaselineTotal),
+		result.Baseline.Label(result.Fiscal))
+
+	if len(result.Months) == 0 {
+		fmt.Fprintln(w, Muted("No forecast available for the specified period."))
+		return nil
+	}
+
+	// Create table
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{
+		"Month",
+		"Actual",
+		"Forecast",
+		"Projected",
+		fmt.Sprintf("Range (%d%%)", result.Interval),
+		"vs " + result.Baseline.Label(result.Fiscal),
+	})
+
+	// Configure table style
+	table.SetBorder(false)
+	table.SetHeaderLine(true)
+	table.SetColumnSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+	})
+
+	// Add rows
+	for _, month := range result.Months {
+		table.Append([]string{
+			month.Period.Label(result.Fiscal),
+			FormatCurrency(month.Actual),
+			FormatCurrency(month.Forecast),
+			FormatCurrency(month.Total),
+			fmt.Sprintf("%s - %s", FormatCurrency(month.Lower), FormatCurrency(month.Upper)),
+			FormatDiffFull(month.Diff, month.DiffPct, false, false),
+		})
+	}
+
+	table.Render()
 	fmt.Fprintln(w)
-	renderBarChartTo(w, result.Days, result.Average)
+
+	return nil
+}
+
+// RenderBudgetTable outputs the budget result as a formatted table to stdout
+func RenderBudgetTable(result *diff.BudgetResult) error {
+	return RenderBudgetTableTo(os.Stdout, result)
+}
+
+// RenderBudgetTableTo outputs the budget result as a formatted table to the specified writer
+func RenderBudgetTableTo(w io.Writer, result *diff.BudgetResult) error {
+	// Print header
+	title := fmt.Sprintf("AWS Budget: %s", result.Period.Label(result.Fiscal))
+	switch days := result.Period.Days(); {
+	case result.ElapsedDays == 0:
+		title += " (no complete days yet)"
+	case result.ElapsedDays < days:
+		title += fmt.Sprintf(" (%d of %d days, projected at the current run rate)", result.ElapsedDays, days)
+	}
+	fmt.Fprintf(w, "\n%s\n\n", Header(title))
+
+	// Print total
+	fmt.Fprintf(w, "Total: %s actual\n%s\n\n", FormatCurrency(result.Total.Actual), budgetLine(&result.Total))
+
+	if len(result.Items) == 0 {
+		fmt.Fprintln(w, Muted("No budgets or cost data found for the specified period."))
+		return nil
+	}
+
+	// Create table
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{
+		DimensionLabel(result.Dimension),
+		"Budget",
+		"Actual",
+		"Projected",
+		"Variance",
+		"Status",
+	})
+
+	// Configure table style
+	table.SetBorder(false)
+	table.SetHeaderLine(true)
+	table.SetColumnSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_LEFT,
+	})
+
+	// Add rows, highlighting overruns
+	for _, item := range result.Items {
+		name := Truncate(item.Name, ServiceNameMaxWidth)
+		switch item.Status {
+		case diff.BudgetOver:
+			name = Error(name)
+		case diff.BudgetUnbudgeted:
+			name = Muted(name)
+		}
+
+		budget := FormatCurrency(item.Budget)
+		variance := FormatDiffFull(item.Variance, item.VariancePct, false, false)
+		if item.Status == diff.BudgetUnbudgeted {
+			budget, variance = "-", "-"
+		}
+		table.Append([]string{
+			name,
+			budget,
+			FormatCurrency(item.Actual),
+			FormatCurrency(item.Projected),
+			variance,
+			FormatBudgetState(item.Status),
+		})
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// budgetHeaders are the columns diff and top add for --budget
+var budgetHeaders = []string{"Budget", "Projected", "Variance", "Status"}
+
+// budgetAlignments are the alignments of budgetHeaders
+var budgetAlignments = []int{
+	tablewriter.ALIGN_RIGHT,
+	tablewriter.ALIGN_RIGHT,
+	tablewriter.ALIGN_RIGHT,
+	tablewriter.ALIGN_LEFT,
+}
+
+// budgetCells returns the budget, projected, variance and status cells for an
+// item's budget. Items without a budget get dashes.
+func budgetCells(status *diff.BudgetStatus) []string {
+	if status == nil {
+		return []string{"-", "-", "-", ""}
+	}
+	return []string{
+		FormatCurrency(status.Budget),
+		FormatCurrency(status.Projected),
+		FormatDiffFull(status.Variance, status.VariancePct, false, false),
+		FormatBudgetState(status.Status),
+	}
+}
+
+// budgetLine summarizes total spend against the overall budget
+func budgetLine(status *diff.BudgetStatus) string {
+	return fmt.Sprintf("Budget: %s, projected %s (%s) %s",
+		FormatCurrency(status.Budget),
+		FormatCurrency(status.Projected),
+		FormatDiffFull(status.Variance, status.VariancePct, false, false),
+		FormatBudgetState(status.Status))
+}
+
+// FormatBudgetState returns a colored label for a budget state
+func FormatBudgetState(state diff.BudgetState) string {
+	switch state {
+	case diff.BudgetOver:
+		return Error("over")
+	case diff.BudgetAtRisk:
+		return Warning("at risk")
+	case diff.BudgetOK:
+		return Success("ok")
+	case diff.BudgetUnbudgeted:
+		return Muted("unbudgeted")
+	}
+	return string(state)
+}
+
+// RenderExplainTable outputs the explain result as a formatted tree to stdout
+func RenderExplainTable(result *diff.ExplainResult) error {
+	return RenderExplainTableTo(os.Stdout, result)
+}
+
+// RenderExplainTableTo outputs the explain result as a formatted tree to the specified writer
+func RenderExplainTableTo(w io.Writer, result *diff.ExplainResult) error {
+	root := result.Root
+
+	// Print header
+	fmt.Fprintf(w, "\n%s\n\n", Header(fmt.Sprintf("AWS Cost Explain: %s, %s → %s",
+		root.Name, result.FromPeriod.Label(result.Fiscal), result.ToPeriod.Label(result.Fiscal))))
+
+	// Print total
+	fmt.Fprintf(w, "Total: %s → %s (%s)\n\n",
+		FormatCurrency(root.FromCost),
+		FormatCurrency(root.ToCost),
+		FormatDiffFull(root.Diff, root.DiffPct, false, false))
+
+	if len(root.Children) == 0 {
+		if root.Diff == 0 {
+			fmt.Fprintln(w, Muted("No change to explain."))
+		} else {
+			fmt.Fprintln(w, Muted("No dimension explains the change: it has a single value in every breakdown."))
+		}
+		return nil
+	}
+
+	// Print which dimension explains the change best
+	best := root.Scores[0]
+	fmt.Fprintf(w, "Best explained by %s: %s holds %.0f%% of the gross change\n",
+		DimensionLabel(best.Dimension), best.Top, best.Score)
+	ranking := make([]string, len(root.Scores))
+	for i, score := range root.Scores {
+		ranking[i] = fmt.Sprintf("%s %.0f%%", DimensionLabel(score.Dimension), score.Score)
+	}
+	fmt.Fprintf(w, "%s\n\n", Muted("Ranking: "+strings.Join(ranking, " · ")))
+
+	// Create table
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{
+		"Contributor",
+		result.FromPeriod.Label(result.Fiscal),
+		result.ToPeriod.Label(result.Fiscal),
+		"Change",
+		"Share",
+	})
+
+	// Configure table style
+	table.SetBorder(false)
+	table.SetHeaderLine(true)
+	table.SetColumnSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment([]int{
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+	})
+
+	// Add rows
+	table.AppendBulk(explainRows(root.Children, "", false))
+
+	table.Render()
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// explainRows renders nodes and their descendants as tree rows. Top-level nodes
+// are not indented; nested nodes hang off their parent with box-drawing branches.
+func explainRows(nodes []diff.ExplainNode, indent string, nested bool) [][]string {
+	var rows [][]string
+	for i, node := range nodes {
+		branch, next := "", ""
+		if nested {
+			branch, next = "├─ ", "│  "
+			if i == len(nodes)-1 {
+				branch, next = "└─ ", "   "
+			}
+		}
+
+		name := node.Name
+		if name == "" {
+			name = "(none)"
+		}
+		label := fmt.Sprintf("%s: %s", DimensionLabel(node.Dimension), Truncate(name, ExplainNameMaxWidth))
+
+		rows = append(rows, []string{
+			Muted(indent+branch) + label,
+			FormatCurrency(node.FromCost),
+			FormatCurrency(node.ToCost),
+			FormatDiffFull(node.Diff, node.DiffPct, false, false),
+			fmt.Sprintf("%.1f%%", node.ContributionPct),
+		})
+		rows = append(rows, explainRows(node.Children, indent+next, true)...)
+	}
+	return rows
+}
+
+// RenderTrendTable outputs the trend result as a formatted table to stdout
+func RenderTrendTable(result *diff.TrendResult) error {
+	return RenderTrendTableTo(os.Stdout, result)
+}
+
+// RenderTrendTableTo outputs the trend result as a formatted table to the specified writer
+func RenderTrendTableTo(w io.Writer, result *diff.TrendResult) error {
+	if len(result.Months) == 0 {
+		fmt.Fprintf(w, "\n%s\n\n", Header("AWS Cost Trend"))
+		fmt.Fprintln(w, Muted("No cost data found for the specified period."))
+		return nil
+	}
+
+	// Print header
+	first, last := result.Months[0], result.Months[len(result.Months)-1]
+	fmt.Fprintf(w, "\n%s\n\n", Header(fmt.Sprintf("AWS Cost Trend: %s → %s", first.Label(result.Fiscal), last.Label(result.Fiscal))))
+
+	// Print total
+	firstTotal, lastTotal := result.Totals[0], result.Totals[len(result.Totals)-1]
+	var totalPct float64
+	if firstTotal > 0 {
+		totalPct = ((lastTotal - firstTotal) / firstTotal) * 100
+	}
+	fmt.Fprintf(w, "Total: %s → %s (%s)  %s\n\n",
+		FormatCurrency(firstTotal),
+		FormatCurrency(lastTotal),
+		FormatDiffFull(lastTotal-firstTotal, totalPct, false, false),
+		Cyan.Sprint(Sparkline(result.Totals)))
+
+	if len(result.Items) == 0 {
+		fmt.Fprintln(w, Muted("No cost data found for the specified period."))
+		return nil
+	}
+
+	// Create table
+	table := tablewriter.NewWriter(w)
+	header := groupHeaders(result.GroupBy)
+	fiscal := result.IsFiscal()
+	for _, month := range result.Months {
+		if fiscal {
+			header = append(header, month.Label(result.Fiscal))
+		} else {
+			header = append(header, month.Start.Format("Jan 06"))
+		}
+	}
+	table.SetHeader(append(header, "Trend", "Change", "CAGR"))
+
+	// Configure table style
+	table.SetBorder(false)
+	table.SetHeaderLine(true)
+	table.SetColumnSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetAutoWrapText(false)
+	alignments := groupAlignments(result.GroupBy)
+	for range result.Months {
+		alignments = append(alignments, tablewriter.ALIGN_RIGHT)
+	}
+	table.SetColumnAlignment(append(alignments,
+		tablewriter.ALIGN_LEFT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+	))
+
+	// Add rows
+	for _, item := range result.Items {
+		row := groupCells(item.Name, item.Keys, result.IsMultiLevel(), GroupKeyMaxWidth)
+		for _, cost := range item.Costs {
+			row = append(row, FormatCurrency(cost))
+		}
+
+		cagr := Muted("-")
+		if item.CAGR != nil {
+			cagr = ColorizePercent(*item.CAGR)
+		}
+
+		table.Append(append(row,
+			Sparkline(item.Costs),
+			ColorizeChange(item.Change, FormatChange(item.Change)),
+			cagr,
+		))
+	}
+
+	table.Render()
 	fmt.Fprintln(w)
 
 	return nil
@@ -198,51 +800,252 @@ func RenderWatchTableTo(w io.Writer, result *diff.WatchResult) error {
 const (
 	ServiceNameMaxWidth    = 40
 	TopServiceNameMaxWidth = 40
+	GroupKeyMaxWidth       = 30
+	WatchGroupMaxWidth     = 20
+	ExplainNameMaxWidth    = 40
 	BarChartMaxWidth       = 40
 	AboveAverageThreshold  = 1.2
 	BelowAverageThreshold  = 0.8
 )
 
+// RenderChangeTable outputs the change comparison as a formatted table to stdout
+func RenderChangeTable(result *diff.ChangeResult) error {
+	return RenderChangeTableTo(os.Stdout, result)
+}
+
+// RenderChangeTableTo outputs the change comparison as a formatted table to the specified writer
+func RenderChangeTableTo(w io.Writer, result *diff.ChangeResult) error {
+	// Print header
+	title := fmt.Sprintf("AWS Cost Diff Comparison: %s vs %s", result.Old.Label(result.Fiscal), result.New.Label(result.Fiscal))
+	if result.PerDay {
+		title += " (per day)"
+	}
+	fmt.Fprintf(w, "\n%s\n\n", Header(title))
+
+	// Print totals
+	for _, side := range []struct {
+		name string
+		diff.ChangeSide
+	}{{"Old", result.Old}, {"New", result.New}} {
+		fmt.Fprintf(w, "%s: %s → %s (%s)\n",
+			side.name,
+			FormatCurrency(side.FromTotal),
+			FormatCurrency(side.ToTotal),
+			FormatDiffFull(side.TotalDiff, side.TotalPct, false, false))
+	}
+	fmt.Fprintf(w, "Delta: %s\n\n", ColorizeChange(result.TotalDelta, FormatChange(result.TotalDelta)))
+
+	if len(result.Items) == 0 {
+		fmt.Fprintln(w, Muted("No items to compare."))
+		return nil
+	}
+
+	// Create table
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(append(groupHeaders(result.GroupBy),
+		result.Old.Label(result.Fiscal),
+		result.New.Label(result.Fiscal),
+		"Delta",
+	))
+
+	// Configure table style
+	table.SetBorder(false)
+	table.SetHeaderLine(true)
+	table.SetColumnSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetAutoWrapText(false)
+	table.SetColumnAlignment(append(groupAlignments(result.GroupBy),
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+		tablewriter.ALIGN_RIGHT,
+	))
+
+	// Add rows
+	for _, item := range result.Items {
+		row := groupCells(item.Name, item.Keys, result.IsMultiLevel(), ServiceNameMaxWidth)
+		if item.Synthetic {
+			row = otherCells(item.Name, result.GroupBy)
+		}
+		table.Append(append(row,
+			changeCell(item.OldDiff, item.OldDiffPct, item.OnlyInNew, item.Synthetic),
+			changeCell(item.NewDiff, item.NewDiffPct, item.OnlyInOld, item.Synthetic),
+			ColorizeChange(item.Delta, FormatChange(item.Delta)),
+		))
+	}
+
+	table.Render()
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// changeCell formats one side of a change comparison: the change with its
+// percentage, without it for rollups, or a dash when the item is not listed
+func changeCell(change, pct float64, missing, synthetic bool) string {
+	switch {
+	case missing:
+		return Muted("-")
+	case synthetic:
+		return ColorizeChange(change, FormatChange(change))
+	default:
+		return FormatDiffFull(change, pct, false, false)
+	}
+}
+
+// chargeRows returns the charge types worth listing under an item: those that
+// changed, and only when the item has more than one charge type
+func chargeRows(item diff.Item) []diff.ChargeDiff {
+	if len(item.Charges) < 2 {
+		return nil
+	}
+
+	var rows []diff.ChargeDiff
+	for _, charge := range item.Charges {
+		if charge.Diff != 0 {
+			rows = append(rows, charge)
+		}
+	}
+	return rows
+}
+
+// groupHeaders returns the header(s) for the group name column(s).
+// Multi-level groupings get one column per dimension.
+func groupHeaders(groupBy []string) []string {
+	if len(groupBy) <= 1 {
+		return []string{"Service"}
+	}
+
+	headers := make([]string, len(groupBy))
+	for i, dim := range groupBy {
+		headers[i] = DimensionLabel(dim)
+	}
+	return headers
+}
+
+// groupAlignments returns the alignment for each group name column
+func groupAlignments(groupBy []string) []int {
+	alignments := make([]int, len(groupHeaders(groupBy)))
+	for i := range alignments {
+		alignments[i] = tablewriter.ALIGN_LEFT
+	}
+	return alignments
+}
+
+// groupCells returns the group name cell(s) for an item
+func groupCells(name string, keys []string, multiLevel bool, maxWidth int) []string {
+	if !multiLevel {
+		return []string{Truncate(name, maxWidth)}
+	}
+
+	cells := make([]string, len(keys))
+	for i, key := range keys {
+		cells[i] = Truncate(key, GroupKeyMaxWidth)
+	}
+	return cells
+}
+
+// otherCells returns the group cells of a synthetic "Other" row, with the
+// name in the first group column
+func otherCells(name string, groupBy []string) []string {
+	cells := make([]string, max(len(groupBy), 1))
+	cells[0] = Muted(name)
+	return cells
+}
+
+// DimensionLabel returns a human-readable label for a grouping dimension
+// such as "usage-type" or "tag:team"
+func DimensionLabel(dim string) string {
+	switch dim {
+	case "service":
+		return "Service"
+	case "usage-type":
+		return "Usage Type"
+	case "region":
+		return "Region"
+	case "account":
+		return "Account"
+	case "operation":
+		return "Operation"
+	}
+	if tag, ok := strings.CutPrefix(dim, "tag:"); ok {
+		return "Tag: " + tag
+	}
+	return dim
+}
+
 // renderBarChartTo renders a simple ASCII bar chart to the specified writer
 func renderBarChartTo(w io.Writer, days []diff.DayItem, average float64) {
 	if len(days) == 0 {
 		return
 	}
 
-	// Find max cost for scaling
-	var maxCost float64
-	for _, day := range days {
-		if day.Cost > maxCost {
-			maxCost = day.Cost
-		}
-	}
-
+	maxCost := maxDayCost(days)
 	if maxCost == 0 {
 		return
 	}
 
 	for _, day := range days {
-		width := int((day.Cost / maxCost) * BarChartMaxWidth)
-		if width < 1 && day.Cost > 0 {
-			width = 1
-		}
+		bar := strings.Repeat("█", barWidth(day.Cost, maxCost))
 
-		bar := strings.Repeat("█", width)
-
-		// Color based on comparison to average
-		if day.Cost > average*AboveAverageThreshold {
+		// Color anomalies by direction; days without enough history to be
+		// scored fall back to a comparison with the average
+		switch {
+		case day.Anomaly && day.Score > 0:
+			bar = Red.Sprint(bar)
+		case day.Anomaly:
+			bar = Yellow.Sprint(bar)
+		case day.Scored:
+			bar = Cyan.Sprint(bar)
+		case day.Cost > average*AboveAverageThreshold:
 			bar = Red.Sprint(bar)
-		} else if day.Cost < average*BelowAverageThreshold {
+		case day.Cost < average*BelowAverageThreshold:
 			bar = Green.Sprint(bar)
-		} else {
+		default:
 			bar = Cyan.Sprint(bar)
 		}
 
-		fmt.Fprintf(w, "%s %s %s\n",
+		line := fmt.Sprintf("%s %s %s",
 			Muted(day.Date.Format("Jan 2")),
 			bar,
 			Muted(FormatCurrency(day.Cost)))
+		if day.Anomaly {
+			line += " " + FormatAnomaly(day)
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// maxDayCost returns the highest daily cost, which bar charts scale to
+func maxDayCost(days []diff.DayItem) float64 {
+	var maxCost float64
+	for _, day := range days {
+		if day.Cost > maxCost {
+			maxCost = day.Cost
+		}
+	}
+	return maxCost
+}
+
+// barWidth returns the length of a day's bar, at least 1 for any spend
+func barWidth(cost, maxCost float64) int {
+	width := int((cost / maxCost) * BarChartMaxWidth)
+	if width < 1 && cost > 0 {
+		width = 1
+	}
+	return width
+}
+
+// FormatAnomaly describes an anomalous day, e.g. "▲ high (z=9.3)", or returns ""
+// for normal days. Spikes are red, drops yellow.
+func FormatAnomaly(day diff.DayItem) string {
+	if !day.Anomaly {
+		return ""
+	}
+	if day.Score > 0 {
+		return Red.Sprintf("▲ %s (z=%.1f)", day.Severity, day.Score)
 	}
+	return Yellow.Sprintf("▼ %s (z=%.1f)", day.Severity, day.Score)
 }
 
 // Truncate shortens a string to maxLen characters, preferring to break at word boundaries.
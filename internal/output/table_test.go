@@ -213,6 +213,70 @@ func TestRenderWatchTableTo(t *testing.T) {
 	}
 }
 
+func TestRenderWatchTableTo_Anomalies(t *testing.T) {
+	result := &diff.WatchResult{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		Total:     500,
+		Average:   250,
+		Anomalies: 1,
+		Days: []diff.DayItem{
+			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 100, Scored: true, Expected: 100},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 400, Scored: true, Expected: 100,
+				Score: 9.25, Anomaly: true, Severity: diff.SeverityHigh},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderWatchTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"ANOMALY", "Anomalies: 1", "▲ high (z=9.2)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderWatchTableTo_Grouped(t *testing.T) {
+	result := &diff.WatchResult{
+		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		Total:     240,
+		Average:   120,
+		Days: []diff.DayItem{
+			{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Cost: 110, Groups: map[string]float64{"Amazon EC2": 100, "Amazon S3": 10}},
+			{Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Cost: 130, Groups: map[string]float64{"Amazon EC2": 118, "Amazon S3": 12}},
+		},
+	}
+	result.SetGroups([]string{"service"}, 10)
+
+	var buf bytes.Buffer
+	if err := RenderWatchTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderWatchTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"AMAZON EC2", "AMAZON S3", "$118.00", "$218.00", "$22.00", "$240.00"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestFormatAnomaly(t *testing.T) {
+	if got := FormatAnomaly(diff.DayItem{Scored: true}); got != "" {
+		t.Errorf("FormatAnomaly(normal) = %q, want empty", got)
+	}
+
+	drop := diff.DayItem{Scored: true, Score: -4.04, Anomaly: true, Severity: diff.SeverityLow}
+	if got := FormatAnomaly(drop); got != "▼ low (z=-4.0)" {
+		t.Errorf("FormatAnomaly(drop) = %q", got)
+	}
+}
+
 func TestRenderWatchTableTo_EmptyDays(t *testing.T) {
 	result := &diff.WatchResult{
 		StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
@@ -349,3 +413,615 @@ func TestRenderTableTo_NewAndRemovedItems(t *testing.T) {
 		t.Error("Output should contain 'removed' label")
 	}
 }
+
+func TestRenderTopTableTo_MultiLevel(t *testing.T) {
+	result := &diff.TopResult{
+		Period: diff.Period{
+			Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Total: 1000,
+		Items: []diff.TopItem{
+			{Name: "EC2 / us-east-1", Cost: 600, Percent: 60},
+			{Name: "EC2 / eu-west-1", Cost: 400, Percent: 40},
+		},
+	}
+	result.SetGroupBy([]string{"service", "region"})
+
+	var buf bytes.Buffer
+	if err := RenderTopTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"SERVICE", "REGION", "us-east-1", "eu-west-1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q", want)
+		}
+	}
+	if strings.Contains(output, "EC2 / us-east-1") {
+		t.Error("Multi-level output should split keys into separate columns")
+	}
+}
+
+func TestDimensionLabel(t *testing.T) {
+	tests := []struct {
+		dim  string
+		want string
+	}{
+		{"service", "Service"},
+		{"usage-type", "Usage Type"},
+		{"region", "Region"},
+		{"account", "Account"},
+		{"tag:team", "Tag: team"},
+	}
+
+	for _, tt := range tests {
+		if got := DimensionLabel(tt.dim); got != tt.want {
+			t.Errorf("DimensionLabel(%q) = %q, want %q", tt.dim, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTableTo_Charges(t *testing.T) {
+	result := &diff.Result{
+		Items: []diff.Item{
+			{
+				Name: "EC2", FromCost: 50, ToCost: 110, Diff: 60, DiffPct: 120,
+				Driver: "Credit",
+				Charges: []diff.ChargeDiff{
+					{Type: "Credit", FromCost: -50, ToCost: 0, Diff: 50},
+					{Type: "Usage", FromCost: 100, ToCost: 110, Diff: 10},
+					{Type: "Tax", FromCost: 5, ToCost: 5, Diff: 0},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"DRIVER", "↳ Credit", "↳ Usage", "+$50.00"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q", want)
+		}
+	}
+	if strings.Contains(output, "↳ Tax") {
+		t.Error("Unchanged charge types should not be listed")
+	}
+}
+
+func TestRenderTableTo_PerDay(t *testing.T) {
+	result := &diff.Result{
+		PerDay: true,
+		Items:  []diff.Item{{Name: "EC2", FromCost: 10, ToCost: 12, Diff: 2, DiffPct: 20}},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "(per day)") {
+		t.Error("Title should mention per-day normalization")
+	}
+}
+
+func TestRenderTableTo_AverageBaseline(t *testing.T) {
+	month := func(m time.Month) diff.Period {
+		start := time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+		return diff.Period{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+	baseline := []diff.Period{month(time.July), month(time.August), month(time.September)}
+	result := &diff.Result{
+		FromPeriod: diff.Span(baseline),
+		ToPeriod:   month(time.October),
+		Baseline:   baseline,
+		Items:      []diff.Item{{Name: "EC2", FromCost: 10, ToCost: 12, Diff: 2, DiffPct: 20}},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "AWS Cost Diff: Avg Q3 2024 → Oct 2024") {
+		t.Errorf("Title should name the averaged periods, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderTableTo_Pareto(t *testing.T) {
+	result := &diff.Result{
+		FromTotal: 1000,
+		ToTotal:   1200,
+		TotalDiff: 200,
+		Items: []diff.Item{
+			{Name: "EC2", FromCost: 500, ToCost: 680, Diff: 180},
+			{Name: "S3", FromCost: 500, ToCost: 520, Diff: 20},
+		},
+	}
+	result.SetContributions()
+	result.Items = diff.Pareto(result.Items, 80)
+	result.Pareto = 80
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"CONTRIBUTION", "CUMULATIVE", "MIX SHIFT", "90.0%", "+6.7 pts", "1 item explains 90.0% of the net change"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "S3") {
+		t.Error("Items beyond the Pareto cutoff should not be shown")
+	}
+}
+
+func TestRenderTableTo_OtherRow(t *testing.T) {
+	result := &diff.Result{
+		Items: []diff.Item{
+			{Name: "EC2 / us-east-1", FromCost: 100, ToCost: 150, Diff: 50, DiffPct: 50},
+		},
+	}
+	result.SetGroupBy([]string{"service", "region"})
+	result.Items = append(result.Items, diff.Item{Name: "Other (3 items)", FromCost: 40, ToCost: 30, Diff: -10, DiffPct: -25, Synthetic: true})
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	var otherLine string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.Contains(line, "Other (3 items)") {
+			otherLine = line
+		}
+	}
+	if otherLine == "" {
+		t.Fatalf("Output should contain the Other row:\n%s", buf.String())
+	}
+	if !strings.Contains(otherLine, "$40.00") || !strings.Contains(otherLine, "$30.00") {
+		t.Errorf("Other row = %q, want its from and to costs", otherLine)
+	}
+}
+
+func TestRenderTopTableTo_OtherRow(t *testing.T) {
+	result := &diff.TopResult{
+		Total: 1000,
+		Items: []diff.TopItem{
+			{Name: "EC2", Cost: 600, Percent: 60},
+			{Name: "Other (2 items)", Cost: 400, Percent: 40, Synthetic: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTopTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Other (2 items)") || !strings.Contains(output, "40.0%") {
+		t.Errorf("Output should contain the Other row:\n%s", output)
+	}
+	if strings.Contains(output, " 2 ") {
+		t.Errorf("The Other row should not be ranked:\n%s", output)
+	}
+}
+
+func TestRenderTableTo_NoContributionColumns(t *testing.T) {
+	result := &diff.Result{
+		Items: []diff.Item{{Name: "EC2", FromCost: 10, ToCost: 12, Diff: 2, DiffPct: 20}},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "CONTRIBUTION") {
+		t.Error("Contribution columns should only be shown with --pareto")
+	}
+}
+
+func TestFormatShareShift(t *testing.T) {
+	tests := []struct {
+		points float64
+		want   string
+	}{
+		{6.666, "+6.7 pts"},
+		{0, "+0.0 pts"},
+		{-2.25, "-2.2 pts"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatShareShift(tt.points); got != tt.want {
+			t.Errorf("FormatShareShift(%v) = %q, want %q", tt.points, got, tt.want)
+		}
+	}
+}
+
+func TestRenderExplainTableTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderExplainTableTo(&buf, testExplainResult()); err != nil {
+		t.Fatalf("RenderExplainTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"AWS Cost Explain: Amazon EC2, Sep 2024 → Oct 2024",
+		"Best explained by Region: us-east-1 holds 95% of the gross change",
+		"Region 95% · Operation 0%",
+		"Region: us-east-1",
+		"├─ Account: 111",
+		"└─ Account: 222",
+		"94.7%",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderExplainTableTo_Unexplained(t *testing.T) {
+	result := testExplainResult()
+	result.Root.Children = nil
+
+	var buf bytes.Buffer
+	if err := RenderExplainTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderExplainTableTo() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "No dimension explains the change") {
+		t.Errorf("Output should say nothing explains the change:\n%s", buf.String())
+	}
+}
+
+func testExplainResult() *diff.ExplainResult {
+	return &diff.ExplainResult{
+		FromPeriod: diff.Period{
+			Start: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		ToPeriod: diff.Period{
+			Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Root: diff.ExplainNode{
+			Dimension: "service", Name: "Amazon EC2", FromCost: 500, ToCost: 690, Diff: 190, DiffPct: 38, ContributionPct: 100,
+			Scores: []diff.DimensionScore{
+				{Dimension: "region", Score: 95, Top: "us-east-1", Values: 2},
+				{Dimension: "operation", Score: 0, Top: "RunInstances", Values: 1},
+			},
+			Children: []diff.ExplainNode{
+				{
+					Dimension: "region", Name: "us-east-1", FromCost: 420, ToCost: 600, Diff: 180, DiffPct: 42.9, ContributionPct: 94.7,
+					Children: []diff.ExplainNode{
+						{Dimension: "account", Name: "111", FromCost: 320, ToCost: 490, Diff: 170, DiffPct: 53.1, ContributionPct: 94.4},
+						{Dimension: "account", Name: "222", FromCost: 100, ToCost: 110, Diff: 10, DiffPct: 10, ContributionPct: 5.6},
+					},
+				},
+				{Dimension: "region", Name: "eu-west-1", FromCost: 80, ToCost: 90, Diff: 10, DiffPct: 12.5, ContributionPct: 5.3},
+			},
+		},
+	}
+}
+
+func testForecastResult() *diff.ForecastResult {
+	return diff.CompareForecast(
+		diff.Period{
+			Start: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		1000,
+		[]diff.ForecastMonth{
+			{
+				Period: diff.Period{
+					Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+				},
+				Actual: 450, Forecast: 700, Total: 1150, Lower: 1050, Upper: 1300,
+			},
+		},
+		80,
+	)
+}
+
+func TestRenderForecastTableTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderForecastTableTo(&buf, testForecastResult()); err != nil {
+		t.Fatalf("RenderForecastTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"AWS Cost Forecast: Oct 2024",
+		"Baseline: $1000.00 (Sep 2024 actual)",
+		"RANGE (80%)",
+		"$1150.00",
+		"$1050.00 - $1300.00",
+		"+$150.00 (+15.0%)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q", want)
+		}
+	}
+}
+
+func testTrendResult() *diff.TrendResult {
+	months := make([]diff.Period, 3)
+	for i := range months {
+		start := time.Date(2024, time.Month(8+i), 1, 0, 0, 0, 0, time.UTC)
+		months[i] = diff.Period{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+
+	return diff.BuildTrend(months, []map[string]float64{
+		{"Amazon EC2": 100, "Amazon S3": 20},
+		{"Amazon EC2": 150, "Amazon S3": 20},
+		{"Amazon EC2": 200},
+	})
+}
+
+func TestRenderTrendTableTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderTrendTableTo(&buf, testTrendResult()); err != nil {
+		t.Fatalf("RenderTrendTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"AWS Cost Trend: Aug 2024 → Oct 2024",
+		"Total: $120.00 → $200.00 (+$80.00 (+66.7%))",
+		"AUG 24",
+		"OCT 24",
+		"▁▄█",
+		"+$100.00",
+		"-$20.00",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderTrendTableTo_NoMonths(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderTrendTableTo(&buf, &diff.TrendResult{}); err != nil {
+		t.Fatalf("RenderTrendTableTo() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "No cost data found") {
+		t.Error("Output should contain 'No cost data found' message")
+	}
+}
+
+func TestRenderTableTo_Budgets(t *testing.T) {
+	result := &diff.Result{
+		ToTotal: 900,
+		Budget:  &diff.BudgetStatus{Budget: 1000, Actual: 900, Projected: 1100, Variance: 100, VariancePct: 10, Status: diff.BudgetAtRisk},
+		Items: []diff.Item{
+			{Name: "EC2", FromCost: 500, ToCost: 700, Diff: 200, DiffPct: 40,
+				Budget: &diff.BudgetStatus{Budget: 600, Actual: 700, Projected: 800, Variance: 200, VariancePct: 33.3, Status: diff.BudgetOver}},
+			{Name: "S3", FromCost: 200, ToCost: 200},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"BUDGET", "PROJECTED", "VARIANCE", "STATUS", "Budget: $1000.00, projected $1100.00 (+$100.00 (+10.0%)) at risk"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q:\n%s", want, output)
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "EC2") && (!strings.Contains(line, "$600.00") || !strings.Contains(line, "over")) {
+			t.Errorf("EC2 row = %q, want its budget and over status", line)
+		}
+		if strings.Contains(line, "S3") && !strings.Contains(line, "-") {
+			t.Errorf("S3 row = %q, want dashes without a budget", line)
+		}
+	}
+}
+
+func TestRenderTopTableTo_Budgets(t *testing.T) {
+	result := &diff.TopResult{
+		Total: 700,
+		Items: []diff.TopItem{
+			{Name: "EC2", Cost: 700, Percent: 100,
+				Budget: &diff.BudgetStatus{Budget: 1000, Actual: 700, Projected: 900, Variance: -100, VariancePct: -10, Status: diff.BudgetOK}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTopTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTopTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "$900.00") || !strings.Contains(output, "-$100.00 (-10.0%)") || !strings.Contains(output, "ok") {
+		t.Errorf("Output should contain the budget columns:\n%s", output)
+	}
+	if strings.Contains(output, "Budget:") {
+		t.Errorf("No budget line without an overall budget:\n%s", output)
+	}
+}
+
+func TestRenderBudgetTableTo(t *testing.T) {
+	result := &diff.BudgetResult{
+		Period: diff.Period{
+			Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Dimension:   "tag:team",
+		ElapsedDays: 10,
+		Total:       diff.NewBudgetStatus(1500, 550, 1705),
+		Items: []diff.BudgetItem{
+			{Name: "team$web", BudgetStatus: diff.NewBudgetStatus(1000, 500, 1550)},
+			{Name: "team$data", BudgetStatus: diff.BudgetStatus{Actual: 50, Projected: 155, Variance: 155, Status: diff.BudgetUnbudgeted}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderBudgetTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderBudgetTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"AWS Budget: Oct 2024 (10 of 31 days, projected at the current run rate)",
+		"Total: $550.00 actual",
+		"Budget: $1500.00, projected $1705.00",
+		"TAG: TEAM",
+		"at risk",
+		"unbudgeted",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q:\n%s", want, output)
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "team$data") && strings.Contains(line, "$0.00") {
+			t.Errorf("Unbudgeted row = %q, should not show a zero budget", line)
+		}
+	}
+}
+
+func TestFormatBudgetState(t *testing.T) {
+	tests := map[diff.BudgetState]string{
+		diff.BudgetOK:         "ok",
+		diff.BudgetAtRisk:     "at risk",
+		diff.BudgetOver:       "over",
+		diff.BudgetUnbudgeted: "unbudgeted",
+	}
+	for state, want := range tests {
+		if got := FormatBudgetState(state); got != want {
+			t.Errorf("FormatBudgetState(%s) = %q, want %q", state, got, want)
+		}
+	}
+}
+
+func TestRenderTableTo_Gate(t *testing.T) {
+	result := &diff.Result{
+		Items: []diff.Item{{Name: "EC2", FromCost: 100, ToCost: 300, Diff: 200, DiffPct: 200}},
+		Gate: &diff.Gate{
+			Conditions: []string{"total>10%", "item>$100"},
+			Violations: []diff.Violation{
+				{Condition: "total>10%", Kind: diff.ConditionTotal, Value: 200, Threshold: 10, Unit: diff.UnitPercent},
+				{Condition: "item>$100", Kind: diff.ConditionItem, Name: "EC2", Value: 200, Threshold: 100, Unit: diff.UnitUSD},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Policy failed: 2 violations", "total increased +200.0%", "EC2 increased +$200.00"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q:\n%s", want, output)
+		}
+	}
+
+	result.Gate = &diff.Gate{Conditions: []string{"total>10%"}, Passed: true}
+	buf.Reset()
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Policy passed: total>10%") {
+		t.Errorf("Output should report the passed gate:\n%s", buf.String())
+	}
+}
+
+func TestFormatViolation(t *testing.T) {
+	tests := []struct {
+		violation diff.Violation
+		want      string
+	}{
+		{diff.Violation{Kind: diff.ConditionTotal, Value: 12.5, Unit: diff.UnitPercent}, "total increased +12.5%"},
+		{diff.Violation{Kind: diff.ConditionTotal, Value: 600, Unit: diff.UnitUSD}, "total increased +$600.00"},
+		{diff.Violation{Kind: diff.ConditionItem, Name: "EC2", Value: 180, Unit: diff.UnitUSD}, "EC2 increased +$180.00"},
+		{diff.Violation{Kind: diff.ConditionNew, Name: "SQS", Value: 60, Unit: diff.UnitUSD}, "SQS is new at $60.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatViolation(tt.violation); got != tt.want {
+			t.Errorf("FormatViolation() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRenderTableTo_Snapshot(t *testing.T) {
+	result := &diff.Result{
+		FromPeriod: diff.Period{Start: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)},
+		ToPeriod:   diff.Period{Start: time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)},
+		Snapshot:   "after-migration",
+		Items:      []diff.Item{{Name: "EC2", FromCost: 10, ToCost: 12, Diff: 2, DiffPct: 20}},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTableTo(&buf, result); err != nil {
+		t.Fatalf("RenderTableTo() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "AWS Cost Diff: after-migration (Sep 2024) → Oct 2024") {
+		t.Errorf("Title should name the snapshot, got:\n%s", buf.String())
+	}
+}
+
+func testChangeResult() *diff.ChangeResult {
+	month := func(m time.Month) diff.Period {
+		start := time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+		return diff.Period{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+
+	return diff.CompareChanges(
+		&diff.Result{
+			FromPeriod: month(time.September), ToPeriod: month(time.October),
+			FromTotal: 1000, ToTotal: 1100, TotalDiff: 100, TotalPct: 10,
+			Items: []diff.Item{
+				{Name: "Amazon EC2", Diff: 80, DiffPct: 20},
+				{Name: "Amazon S3", Diff: 20, DiffPct: 10},
+			},
+		},
+		&diff.Result{
+			FromPeriod: month(time.October), ToPeriod: month(time.November),
+			FromTotal: 1100, ToTotal: 1400, TotalDiff: 300, TotalPct: 27.3,
+			Items: []diff.Item{
+				{Name: "Amazon EC2", Diff: 260, DiffPct: 50},
+				{Name: "AWS Lambda", Diff: 40, DiffPct: 100, IsNew: true},
+			},
+		},
+	)
+}
+
+func TestRenderChangeTableTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderChangeTableTo(&buf, testChangeResult()); err != nil {
+		t.Fatalf("RenderChangeTableTo() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"AWS Cost Diff Comparison: Sep 2024 → Oct 2024 vs Oct 2024 → Nov 2024",
+		"Old: $1000.00 → $1100.00 (+$100.00 (+10.0%))",
+		"New: $1100.00 → $1400.00 (+$300.00 (+27.3%))",
+		"Delta: +$200.00",
+		"+$80.00 (+20.0%)",
+		"+$260.00 (+50.0%)",
+		"+$180.00",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package report reads the JSON output of costdiff and costdiff top back
+// into results, so that saved reports can be compared without querying AWS
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+// Kind is the command a report was produced by
+type Kind string
+
+// Kinds of reports
+const (
+	KindTop  Kind = "top"  // costdiff top -o json
+	KindDiff Kind = "diff" // costdiff -o json
+)
+
+// Report is a saved JSON output; Top is set for top reports and Diff for diff reports
+type Report struct {
+	Path string
+	Kind Kind
+	Top  *diff.TopResult
+	Diff *diff.Result
+}
+
+// probe holds the fields that tell the kinds of reports apart
+type probe struct {
+	Period     *json.RawMessage `json:"period"`
+	FromPeriod *json.RawMessage `json:"from_period"`
+	ToPeriod   *json.RawMessage `json:"to_period"`
+	Items      *json.RawMessage `json:"items"`
+}
+
+// Load reads a report from a file, or from stdin when path is "-"
+func Load(path string) (*Report, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	report, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	report.Path = path
+	return report, nil
+}
+
+// Parse reads a report from the JSON output of costdiff or costdiff top
+func Parse(data []byte) (*Report, error) {
+	var p probe
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+
+	switch {
+	case p.FromPeriod != nil && p.ToPeriod != nil && p.Items != nil:
+		var raw diff.ResultJSON
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse diff report: %w", err)
+		}
+		result, err := fromResultJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &Report{Kind: KindDiff, Diff: result}, nil
+
+	case p.Period != nil && p.Items != nil:
+		var raw diff.TopResultJSON
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse top report: %w", err)
+		}
+		result, err := fromTopResultJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		return &Report{Kind: KindTop, Top: result}, nil
+
+	default:
+		return nil, fmt.Errorf("not a JSON report of costdiff or costdiff top (-o json)")
+	}
+}
+
+// fromResultJSON converts a diff report back to a Result
+func fromResultJSON(raw diff.ResultJSON) (*diff.Result, error) {
+	from, err := raw.FromPeriod.Period()
+	if err != nil {
+		return nil, fmt.Errorf("invalid from_period: %w", err)
+	}
+	to, err := raw.ToPeriod.Period()
+	if err != nil {
+		return nil, fmt.Errorf("invalid to_period: %w", err)
+	}
+
+	var baseline []diff.Period
+	for _, p := range raw.Baseline {
+		period, err := p.Period()
+		if err != nil {
+			return nil, fmt.Errorf("invalid baseline: %w", err)
+		}
+		baseline = append(baseline, period)
+	}
+
+	return &diff.Result{
+		FromPeriod: from,
+		ToPeriod:   to,
+		Baseline:   baseline,
+		Snapshot:   raw.Snapshot,
+		GroupBy:    raw.GroupBy,
+		PerDay:     raw.PerDay,
+		Pareto:     raw.Pareto,
+		FromTotal:  raw.FromTotal,
+		ToTotal:    raw.ToTotal,
+		TotalDiff:  raw.TotalDiff,
+		TotalPct:   raw.TotalPct,
+		Budget:     raw.Budget,
+		Gate:       raw.Gate,
+		Items:      raw.Items,
+	}, nil
+}
+
+// fromTopResultJSON converts a top report back to a TopResult
+func fromTopResultJSON(raw diff.TopResultJSON) (*diff.TopResult, error) {
+	period, err := raw.Period.Period()
+	if err != nil {
+		return nil, fmt.Errorf("invalid period: %w", err)
+	}
+
+	return &diff.TopResult{
+		Period:  period,
+		GroupBy: raw.GroupBy,
+		Total:   raw.Total,
+		Budget:  raw.Budget,
+		Items:   raw.Items,
+	}, nil
+}
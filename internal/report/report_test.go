@@ -0,0 +1,122 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/output"
+)
+
+func month(m time.Month) diff.Period {
+	start := time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+	return diff.Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+func writeReport(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Top(t *testing.T) {
+	top := &diff.TopResult{
+		Period: month(time.September),
+		Total:  900,
+		Items: []diff.TopItem{
+			{Name: "Amazon EC2 / us-east-1", Cost: 600, Percent: 66.7},
+			{Name: "Other (2 items)", Cost: 300, Percent: 33.3, Synthetic: true},
+		},
+	}
+	top.SetGroupBy([]string{"service", "region"})
+
+	var buf strings.Builder
+	if err := output.RenderTopJSONTo(&buf, top); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Load(writeReport(t, buf.String()))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if report.Kind != KindTop || report.Top == nil || report.Diff != nil {
+		t.Fatalf("Load() = %+v, want a top report", report)
+	}
+	if !report.Top.Period.Start.Equal(top.Period.Start) || !report.Top.Period.End.Equal(top.Period.End) {
+		t.Errorf("Period = %s, want Sep 2024", report.Top.Period.Label(nil))
+	}
+	if report.Top.Total != 900 || len(report.Top.Items) != 2 || !report.Top.Items[1].Synthetic {
+		t.Errorf("Top = %+v", report.Top)
+	}
+	if len(report.Top.GroupBy) != 2 || report.Top.Items[0].Keys[1] != "us-east-1" {
+		t.Errorf("grouping not kept: %v, %v", report.Top.GroupBy, report.Top.Items[0].Keys)
+	}
+}
+
+func TestLoad_Diff(t *testing.T) {
+	baseline := []diff.Period{month(time.July), month(time.August), month(time.September)}
+	result := &diff.Result{
+		FromPeriod: diff.Span(baseline),
+		ToPeriod:   month(time.October),
+		Baseline:   baseline,
+		FromTotal:  1000,
+		ToTotal:    1200,
+		TotalDiff:  200,
+		TotalPct:   20,
+		Items:      []diff.Item{{Name: "EC2", FromCost: 1000, ToCost: 1200, Diff: 200, DiffPct: 20}},
+	}
+
+	var buf strings.Builder
+	if err := output.RenderJSONTo(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Load(writeReport(t, buf.String()))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if report.Kind != KindDiff || report.Diff == nil {
+		t.Fatalf("Load() = %+v, want a diff report", report)
+	}
+	if got := report.Diff.FromLabel(); got != "Avg Q3 2024" {
+		t.Errorf("FromLabel() = %q, want Avg Q3 2024", got)
+	}
+	if report.Diff.TotalDiff != 200 || len(report.Diff.Items) != 1 || report.Diff.Items[0].Diff != 200 {
+		t.Errorf("Diff = %+v", report.Diff)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"not JSON", "period,cost\n", "failed to parse report"},
+		{"other output", `{"months": [], "items": []}`, "not a JSON report"},
+		{"bad period", `{"period": {"start": "2024-09", "end": "2024-10-01"}, "total": 0, "items": []}`, "invalid period"},
+		{"bad to period", `{"from_period": {"start": "2024-09-01", "end": "2024-10-01"}, "to_period": {"start": "x", "end": "y"}, "items": []}`, "invalid to_period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil || !strings.Contains(err.Error(), "failed to read report") {
+		t.Errorf("Load() error = %v, want a read error", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Package snapshot saves cost results under a name so that later runs can be
+// compared against them
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+	"github.com/hserkanyilmaz/costdiff/internal/fsutil"
+)
+
+const (
+	// formatVersion is bumped whenever the snapshot file format changes
+	formatVersion = 1
+
+	// snapshotExt is the file extension of snapshots
+	snapshotExt = ".json"
+)
+
+// namePattern restricts names to ones that are safe as file names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ErrNotFound is returned when no snapshot has the requested name
+var ErrNotFound = errors.New("snapshot not found")
+
+// Query holds the flags a snapshot was taken with. Comparisons reuse them so
+// that both sides measure the same costs.
+type Query struct {
+	Metric             string   `json:"metric"` // --metric name, e.g. net-amortized
+	Group              string   `json:"group"`  // -g value, e.g. service,region
+	Tag                string   `json:"tag,omitempty"`
+	Service            string   `json:"service,omitempty"`
+	Filter             string   `json:"filter,omitempty"`
+	ExcludeRecordTypes []string `json:"exclude_record_types,omitempty"`
+}
+
+// Snapshot is a saved top result: the cost of every group over a period
+type Snapshot struct {
+	Version   int                `json:"version"`
+	Name      string             `json:"name"`
+	CreatedAt time.Time          `json:"created_at"`
+	Query     Query              `json:"query"`
+	Result    diff.TopResultJSON `json:"result"`
+}
+
+// New creates a snapshot of a top result taken with query
+func New(name string, query Query, result *diff.TopResult, now time.Time) *Snapshot {
+	return &Snapshot{
+		Version:   formatVersion,
+		Name:      name,
+		CreatedAt: now.UTC(),
+		Query:     query,
+		Result:    result.ToJSON(),
+	}
+}
+
+// Period returns the period the snapshot covers
+func (s *Snapshot) Period() (diff.Period, error) {
+	period, err := s.Result.Period.Period()
+	if err != nil {
+		return diff.Period{}, fmt.Errorf("invalid snapshot %s: %w", s.Name, err)
+	}
+	return period, nil
+}
+
+// Costs returns the saved cost of each group
+func (s *Snapshot) Costs() map[string]float64 {
+	costs := make(map[string]float64, len(s.Result.Items))
+	for _, item := range s.Result.Items {
+		costs[item.Name] += item.Cost
+	}
+	return costs
+}
+
+// Store is a directory of snapshots, one JSON file per name
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store rooted at dir. The directory is created on first save.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the snapshot directory, honoring COSTDIFF_SNAPSHOT_DIR
+func DefaultDir() (string, error) {
+	return fsutil.ConfigDir("COSTDIFF_SNAPSHOT_DIR", "snapshots")
+}
+
+// Dir returns the directory the store reads and writes
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// ValidateName checks that a snapshot name can be used as a file name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q (use letters, digits, '.', '_' and '-')", name)
+	}
+	return nil
+}
+
+// Save writes a snapshot. An existing snapshot of the same name is only
+// replaced when overwrite is set.
+func (s *Store) Save(snap *Snapshot, overwrite bool) error {
+	if err := ValidateName(snap.Name); err != nil {
+		return err
+	}
+
+	path := s.path(snap.Name)
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("snapshot %s already exists (use --force to replace it)", snap.Name)
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	if err := fsutil.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads the snapshot with the given name
+func (s *Store) Load(name string) (*Snapshot, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s (see costdiff snapshot list)", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("corrupt snapshot %s: %w", s.path(name), err)
+	}
+	if snap.Version != formatVersion {
+		return nil, fmt.Errorf("snapshot %s has unsupported version %d", name, snap.Version)
+	}
+
+	return &snap, nil
+}
+
+// List returns every snapshot in the store, sorted by name
+func (s *Store) List() ([]*Snapshot, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var snapshots []*Snapshot
+	for _, de := range dirEntries {
+		name, ok := strings.CutSuffix(de.Name(), snapshotExt)
+		if de.IsDir() || !ok || ValidateName(name) != nil {
+			continue
+		}
+		snap, err := s.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots, nil
+}
+
+// Delete removes the snapshot with the given name
+func (s *Store) Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	err := os.Remove(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of the snapshot with the given name
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+snapshotExt)
+}
@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hserkanyilmaz/costdiff/internal/diff"
+)
+
+func testSnapshot(name string) *Snapshot {
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	result := &diff.TopResult{
+		Period: diff.Period{Start: start, End: start.AddDate(0, 1, 0)},
+		Total:  800,
+		Items: []diff.TopItem{
+			{Name: "Amazon EC2", Cost: 500, Percent: 62.5},
+			{Name: "Amazon S3", Cost: 300, Percent: 37.5},
+		},
+	}
+	result.SetGroupBy([]string{"SERVICE"})
+	query := Query{Metric: "unblended", Group: "service"}
+	return New(name, query, result, time.Date(2024, 10, 2, 9, 0, 0, 0, time.UTC))
+}
+
+func TestStore_SaveLoad(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "snapshots"))
+	if err := store.Save(testSnapshot("sep"), false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	snap, err := store.Load("sep")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if snap.Query.Metric != "unblended" || snap.Query.Group != "service" {
+		t.Errorf("Query = %+v", snap.Query)
+	}
+
+	period, err := snap.Period()
+	if err != nil {
+		t.Fatalf("Period() error = %v", err)
+	}
+	if period.Label(nil) != "Sep 2024" {
+		t.Errorf("Period() = %s, want Sep 2024", period.Label(nil))
+	}
+
+	costs := snap.Costs()
+	if len(costs) != 2 || costs["Amazon EC2"] != 500 || costs["Amazon S3"] != 300 {
+		t.Errorf("Costs() = %v", costs)
+	}
+}
+
+func TestStore_SaveExisting(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(testSnapshot("sep"), false); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	err := store.Save(testSnapshot("sep"), false)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Save() error = %v, want an already exists error", err)
+	}
+	if err := store.Save(testSnapshot("sep"), true); err != nil {
+		t.Errorf("Save() with overwrite error = %v", err)
+	}
+}
+
+func TestStore_ListDelete(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, name := range []string{"oct", "aug", "sep"} {
+		if err := store.Save(testSnapshot(name), false); err != nil {
+			t.Fatalf("Save(%s) error = %v", name, err)
+		}
+	}
+	// Stray files are not snapshots
+	if err := os.WriteFile(filepath.Join(store.Dir(), "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Delete("oct"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	snapshots, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var names []string
+	for _, snap := range snapshots {
+		names = append(names, snap.Name)
+	}
+	if strings.Join(names, ",") != "aug,sep" {
+		t.Errorf("List() = %v, want [aug sep]", names)
+	}
+}
+
+func TestStore_NotFound(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "missing"))
+
+	if _, err := store.Load("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() error = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() error = %v, want ErrNotFound", err)
+	}
+	if snapshots, err := store.List(); snapshots != nil || err != nil {
+		t.Errorf("List() = %v, %v, want nothing for a missing directory", snapshots, err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"sep", "after-migration", "2024.09_prod"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../etc", "a/b", ".hidden", "has space"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) should fail", name)
+		}
+	}
+}
+
+func TestStore_LoadUnsupportedVersion(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := os.WriteFile(filepath.Join(store.Dir(), "old.json"), []byte(`{"version": 99, "name": "old"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := store.Load("old")
+	if err == nil || !strings.Contains(err.Error(), "unsupported version") {
+		t.Errorf("Load() error = %v, want an unsupported version error", err)
+	}
+}